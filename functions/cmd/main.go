@@ -6,16 +6,23 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go/v4"
 
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
 	"github.com/techworld-hackathon/functions/internal/domain/service"
 	"github.com/techworld-hackathon/functions/internal/interface/gateway/ai"
+	authGateway "github.com/techworld-hackathon/functions/internal/interface/gateway/auth"
+	eventGateway "github.com/techworld-hackathon/functions/internal/interface/gateway/event"
 	firestoreGateway "github.com/techworld-hackathon/functions/internal/interface/gateway/firestore"
 	imageGateway "github.com/techworld-hackathon/functions/internal/interface/gateway/image"
+	inmemoryGateway "github.com/techworld-hackathon/functions/internal/interface/gateway/inmemory"
 	storageGateway "github.com/techworld-hackathon/functions/internal/interface/gateway/storage"
 	"github.com/techworld-hackathon/functions/internal/interface/handler"
+	"github.com/techworld-hackathon/functions/internal/interface/worker"
 	"github.com/techworld-hackathon/functions/internal/usecase"
 )
 
@@ -32,33 +39,53 @@ func main() {
 	}))
 	slog.SetDefault(logger)
 
-	// Firebase初期化
-	// ローカル開発時は FIRESTORE_EMULATOR_HOST が設定されていると自動でエミュレータに接続
-	projectID := os.Getenv("GCP_PROJECT")
-	if projectID == "" {
-		projectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
-	}
-	if projectID == "" {
-		projectID = "demo-project" // ローカル開発用デフォルト
-	}
+	// リポジトリの初期化
+	// REPOSITORY_BACKEND=inmemory ならFirestoreを使わずメモリ上で動かす（データはプロセス終了で消える）
+	var repos *repositories
+	if os.Getenv("REPOSITORY_BACKEND") == "inmemory" {
+		slog.Info("Using in-memory repositories")
+		repos = newInMemoryRepositories()
+	} else {
+		// Firebase初期化
+		// ローカル開発時は FIRESTORE_EMULATOR_HOST が設定されていると自動でエミュレータに接続
+		app, err := newFirebaseApp(ctx)
+		if err != nil {
+			slog.Error("Failed to initialize Firebase", slog.Any("error", err))
+			os.Exit(1)
+		}
 
-	conf := &firebase.Config{ProjectID: projectID}
-	app, err := firebase.NewApp(ctx, conf)
-	if err != nil {
-		slog.Error("Failed to initialize Firebase", slog.Any("error", err))
-		os.Exit(1)
+		// Firestoreクライアント初期化
+		firestoreClient, err := app.Firestore(ctx)
+		if err != nil {
+			slog.Error("Failed to initialize Firestore", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer firestoreClient.Close()
+
+		repos = newFirestoreRepositories(firestoreClient)
 	}
 
-	// Firestoreクライアント初期化
-	firestoreClient, err := app.Firestore(ctx)
-	if err != nil {
-		slog.Error("Failed to initialize Firestore", slog.Any("error", err))
-		os.Exit(1)
+	// 認証（セッショントークン、FIREBASE_AUTH=true なら Firebase の ID トークンも受け付ける）
+	sessions := authGateway.NewSessionManager(authGateway.SessionConfigFromEnv())
+	tokenVerifier := authGateway.ChainVerifier{sessions}
+	if os.Getenv("FIREBASE_AUTH") == "true" {
+		firebaseVerifier, err := newFirebaseVerifier(ctx)
+		if err != nil {
+			slog.Error("Failed to initialize Firebase Auth", slog.Any("error", err))
+			os.Exit(1)
+		}
+		slog.Info("Firebase ID token verification enabled")
+		tokenVerifier = append(tokenVerifier, firebaseVerifier)
 	}
-	defer firestoreClient.Close()
+
+	// 部屋作成の seed（不具合の再現用）はローカル開発（インメモリ・エミュレータ）でのみ受け付ける
+	allowSeed := os.Getenv("REPOSITORY_BACKEND") == "inmemory" || os.Getenv("FIRESTORE_EMULATOR_HOST") != ""
 
 	// 依存性の注入
-	h := initializeHandler(ctx, firestoreClient)
+	h, runner := initializeHandler(ctx, repos, sessions, tokenVerifier, allowSeed)
+
+	// バックグラウンドのジョブ・定期処理（制限時間切れ・画像のやり直しなど）
+	go runner.Run(ctx)
 
 	// ルーティング設定
 	mux := http.NewServeMux()
@@ -77,16 +104,115 @@ func main() {
 	}
 }
 
-// initializeHandler は依存性を注入してハンドラーを初期化する
-func initializeHandler(ctx context.Context, firestoreClient *firestore.Client) *handler.Handler {
+// newFirebaseApp は Firebase を初期化する
+// プロジェクトIDは GCP_PROJECT、GOOGLE_CLOUD_PROJECT の順に読み、なければローカル開発用の demo-project
+func newFirebaseApp(ctx context.Context) (*firebase.App, error) {
+	projectID := os.Getenv("GCP_PROJECT")
+	if projectID == "" {
+		projectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	if projectID == "" {
+		projectID = "demo-project" // ローカル開発用デフォルト
+	}
+	return firebase.NewApp(ctx, &firebase.Config{ProjectID: projectID})
+}
+
+// newFirebaseVerifier は Firebase の ID トークンを検証する TokenVerifier を作成する
+// FIREBASE_AUTH_EMULATOR_HOST が設定されていれば Auth エミュレーターのトークンを受け付ける
+func newFirebaseVerifier(ctx context.Context) (service.TokenVerifier, error) {
+	app, err := newFirebaseApp(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := app.Auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return authGateway.NewFirebaseVerifier(client), nil
+}
+
+// repositories はバックエンドごとに差し替えるリポジトリ一式
+type repositories struct {
+	room       repository.RoomRepository
+	player     repository.PlayerRepository
+	policy     repository.PolicyRepository
+	ideology   repository.IdeologyRepository
+	roomCode   repository.RoomCodeRepository
+	transactor repository.Transactor
+	jobs       repository.JobQueue
+}
+
+// newFirestoreRepositories は Firestore を使ったリポジトリ一式を作成する
+// ジョブの待ち行列も Firestore に置き、全インスタンスで共有する（JOB_QUEUE=inprocess ならインスタンス内のみ）
+func newFirestoreRepositories(firestoreClient *firestore.Client) *repositories {
+	jobs := firestoreGateway.NewJobQueue(firestoreClient)
+	if os.Getenv("JOB_QUEUE") == "inprocess" {
+		slog.Info("Using in-process job queue")
+		jobs = inmemoryGateway.NewJobQueue()
+	}
+	return &repositories{
+		room:       firestoreGateway.NewRoomRepository(firestoreClient),
+		player:     firestoreGateway.NewPlayerRepository(firestoreClient),
+		policy:     firestoreGateway.NewPolicyRepository(firestoreClient),
+		ideology:   firestoreGateway.NewIdeologyRepository(firestoreClient),
+		roomCode:   firestoreGateway.NewRoomCodeRepository(firestoreClient),
+		transactor: firestoreGateway.NewTransactor(firestoreClient),
+		jobs:       jobs,
+	}
+}
+
+// newInMemoryRepositories はメモリ上のリポジトリ一式を作成する（マスターデータは投入済み）
+func newInMemoryRepositories() *repositories {
+	store := inmemoryGateway.NewStore()
+	return &repositories{
+		room:       inmemoryGateway.NewRoomRepository(store),
+		player:     inmemoryGateway.NewPlayerRepository(store),
+		policy:     inmemoryGateway.NewPolicyRepository(store),
+		ideology:   inmemoryGateway.NewIdeologyRepository(store),
+		roomCode:   inmemoryGateway.NewRoomCodeRepository(store),
+		transactor: inmemoryGateway.NewTransactor(store),
+		jobs:       inmemoryGateway.NewJobQueue(),
+	}
+}
+
+// durationFromEnv は環境変数の時間（例: 500ms, 30s）を返す（未設定・不正なら def）
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		slog.Warn("invalid duration, using default", slog.String("key", key), slog.String("value", v))
+		return def
+	}
+	return d
+}
+
+// initializeHandler は依存性を注入してハンドラーとバックグラウンドのジョブの実行を初期化する
+func initializeHandler(ctx context.Context, repos *repositories, sessionIssuer service.SessionIssuer, tokenVerifier service.TokenVerifier, allowSeed bool) (*handler.Handler, *worker.Runner) {
 	// Repository
-	roomRepo := firestoreGateway.NewRoomRepository(firestoreClient)
-	playerRepo := firestoreGateway.NewPlayerRepository(firestoreClient)
-	policyRepo := firestoreGateway.NewPolicyRepository(firestoreClient)
-	ideologyRepo := firestoreGateway.NewIdeologyRepository(firestoreClient)
+	roomRepo := repos.room
+	playerRepo := repos.player
+	policyRepo := repos.policy
+	ideologyRepo := repos.ideology
+	roomCodeRepo := repos.roomCode
+	transactor := repos.transactor
+	jobQueue := repos.jobs
 
-	// AI Client
-	aiClient := ai.NewSakuraAIClient()
+	// Petition Reviewer（PETITION_REVIEWER=local なら外部APIを使わない規則ベースの審査）
+	var petitionReviewer service.PetitionReviewer
+	if os.Getenv("PETITION_REVIEWER") == "local" {
+		slog.Info("Using local petition reviewer")
+		petitionReviewer = ai.NewLocalReviewer()
+	} else {
+		aiConfig := ai.ConfigFromEnv()
+		slog.Info("Using OpenAI-compatible petition reviewer", slog.String("endpoint", aiConfig.Endpoint), slog.String("model", aiConfig.Model))
+		petitionReviewer = ai.NewOpenAICompatibleClient(aiConfig)
+	}
+
+	// Petition Moderator（AI審査の前後で陳情と生成された政策を検査する）
+	petitionModerator := ai.NewRuleModerator()
 
 	// Image Generator
 	imageGenerator := imageGateway.NewFluxClient()
@@ -105,29 +231,89 @@ func initializeHandler(ctx context.Context, firestoreClient *firestore.Client) *
 		slog.Info("GCS_BUCKET_NAME not set, image storage disabled")
 	}
 
+	// Event Broker（SSE 配信用、インスタンス内のみ）
+	eventBroker := eventGateway.NewBroker()
+
 	// UseCase
-	createRoomUC := usecase.NewCreateRoomUseCase(roomRepo, playerRepo, ideologyRepo)
-	joinRoomUC := usecase.NewJoinRoomUseCase(roomRepo, playerRepo, ideologyRepo)
-	leaveRoomUC := usecase.NewLeaveRoomUseCase(roomRepo, playerRepo)
-	toggleReadyUC := usecase.NewToggleReadyUseCase(roomRepo, playerRepo)
-	startGameUC := usecase.NewStartGameUseCase(roomRepo, playerRepo, policyRepo)
-	voteUC := usecase.NewVoteUseCase(roomRepo, playerRepo, policyRepo, imageGenerator, imageStorage)
-	resolveVoteUC := usecase.NewResolveVoteUseCase(roomRepo, playerRepo, policyRepo, imageGenerator, imageStorage)
-	nextTurnUC := usecase.NewNextTurnUseCase(roomRepo, playerRepo)
-	submitPetitionUC := usecase.NewSubmitPetitionUseCase(roomRepo, playerRepo, policyRepo, aiClient)
+	createRoomUC := usecase.NewCreateRoomUseCase(roomRepo, playerRepo, ideologyRepo, roomCodeRepo, transactor)
+	joinRoomUC := usecase.NewJoinRoomUseCase(roomRepo, playerRepo, ideologyRepo, roomCodeRepo, transactor, eventBroker)
+	rejoinRoomUC := usecase.NewRejoinRoomUseCase(roomRepo, playerRepo, transactor, eventBroker)
+	leaveRoomUC := usecase.NewLeaveRoomUseCase(roomRepo, playerRepo, roomCodeRepo, transactor, imageStorage, jobQueue, eventBroker)
+	toggleReadyUC := usecase.NewToggleReadyUseCase(roomRepo, playerRepo, eventBroker)
+	updateSettingsUC := usecase.NewUpdateRoomSettingsUseCase(roomRepo, playerRepo, transactor, eventBroker)
+	startGameUC := usecase.NewStartGameUseCase(roomRepo, playerRepo, policyRepo, transactor, jobQueue, eventBroker)
+	voteUC := usecase.NewVoteUseCase(roomRepo, playerRepo, policyRepo, transactor, imageGenerator, imageStorage, jobQueue, eventBroker)
+	retractVoteUC := usecase.NewRetractVoteUseCase(roomRepo, playerRepo, transactor, eventBroker)
+	resolveVoteUC := usecase.NewResolveVoteUseCase(roomRepo, playerRepo, policyRepo, transactor, imageGenerator, imageStorage, jobQueue, eventBroker)
+	decideTieUC := usecase.NewDecideTieUseCase(roomRepo, playerRepo, policyRepo, transactor, imageGenerator, imageStorage, jobQueue, eventBroker)
+	nextTurnUC := usecase.NewNextTurnUseCase(roomRepo, playerRepo, transactor, jobQueue, eventBroker)
+	submitPetitionUC := usecase.NewSubmitPetitionUseCase(roomRepo, playerRepo, policyRepo, petitionReviewer, petitionModerator, transactor, eventBroker)
+	getFinalResultUC := usecase.NewGetFinalResultUseCase(roomRepo)
+	getRoomUC := usecase.NewGetRoomUseCase(roomRepo, playerRepo, policyRepo)
+	getPlayersUC := usecase.NewGetPlayersUseCase(roomRepo, playerRepo)
+	getGraveyardUC := usecase.NewGetGraveyardUseCase(roomRepo, policyRepo)
+	findRoomByCodeUC := usecase.NewFindRoomByCodeUseCase(roomCodeRepo, roomRepo, playerRepo)
+	listLobbiesUC := usecase.NewListLobbiesUseCase(roomRepo, playerRepo)
+	quickMatchUC := usecase.NewQuickMatchUseCase(roomRepo, playerRepo, joinRoomUC, createRoomUC)
+	updateAccessUC := usecase.NewUpdateRoomAccessUseCase(roomRepo, transactor)
+	createInviteUC := usecase.NewCreateInviteUseCase(roomRepo, transactor)
+	handleTimeoutsUC := usecase.NewHandleTimeoutsUseCase(roomRepo, playerRepo, policyRepo, transactor, imageGenerator, imageStorage, jobQueue, eventBroker)
+	retryCityImageUC := usecase.NewRetryCityImageUseCase(roomRepo, policyRepo, imageGenerator, imageStorage)
+	cleanupRoomsUC := usecase.NewCleanupRoomsUseCase(roomRepo, roomCodeRepo, transactor, imageStorage, jobQueue)
+	deleteCityImagesUC := usecase.NewDeleteCityImagesUseCase(imageStorage)
+
+	// Worker（JOB_POLL_INTERVAL で待ち行列の確認間隔、JOB_RETENTION で完了したジョブを残す時間、
+	// TIMEOUT_CHECK_INTERVAL で期限切れの一括確認の間隔を変更できる）
+	workerConfig := worker.DefaultConfig()
+	workerConfig.PollInterval = durationFromEnv("JOB_POLL_INTERVAL", workerConfig.PollInterval)
+	workerConfig.Retention = durationFromEnv("JOB_RETENTION", workerConfig.Retention)
+	runner := worker.NewRunner(jobQueue, workerConfig)
+	worker.RegisterGameJobs(runner, handleTimeoutsUC, retryCityImageUC, durationFromEnv("TIMEOUT_CHECK_INTERVAL", 30*time.Second))
+
+	// 操作のない部屋の削除（ROOM_CLEANUP_DRY_RUN=true なら削除せずにログに出すだけ）
+	cleanupConfig := worker.CleanupConfig{
+		Interval: durationFromEnv("ROOM_CLEANUP_INTERVAL", 10*time.Minute),
+		TTL: entity.RoomTTL{
+			Idle:     durationFromEnv("ROOM_IDLE_TTL", entity.DefaultRoomTTL.Idle),
+			Finished: durationFromEnv("ROOM_FINISHED_TTL", entity.DefaultRoomTTL.Finished),
+		},
+		DryRun: os.Getenv("ROOM_CLEANUP_DRY_RUN") == "true",
+	}
+	if cleanupConfig.DryRun {
+		slog.Info("Room cleanup is in dry-run mode")
+	}
+	worker.RegisterRoomCleanup(runner, cleanupRoomsUC, deleteCityImagesUC, cleanupConfig)
 
 	// Handler
-	return handler.NewHandler(
+	h := handler.NewHandler(
 		createRoomUC,
 		joinRoomUC,
+		rejoinRoomUC,
 		leaveRoomUC,
 		toggleReadyUC,
+		updateSettingsUC,
 		startGameUC,
 		voteUC,
+		retractVoteUC,
 		resolveVoteUC,
+		decideTieUC,
 		nextTurnUC,
 		submitPetitionUC,
+		getFinalResultUC,
+		getRoomUC,
+		getPlayersUC,
+		getGraveyardUC,
+		findRoomByCodeUC,
+		listLobbiesUC,
+		quickMatchUC,
+		updateAccessUC,
+		createInviteUC,
+		eventBroker,
+		sessionIssuer,
+		tokenVerifier,
+		allowSeed,
 	)
+	return h, runner
 }
 
 // setupRoutes はルーティングを設定する
@@ -135,15 +321,35 @@ func setupRoutes(mux *http.ServeMux, h *handler.Handler) {
 	// API endpoints
 	// POST /api/rooms              - 部屋作成
 	// POST /api/rooms/{roomId}/join     - 部屋参加
+	// POST /api/rooms/{roomId}/rejoin   - 再参加（再参加コード or セッショントークンで同じ席に戻る）
 	// POST /api/rooms/{roomId}/leave    - 部屋退出
 	// POST /api/rooms/{roomId}/ready    - Ready状態トグル
+	// POST /api/rooms/{roomId}/settings - 部屋設定の変更（ホストのみ、LOBBYのみ）
+	// POST /api/rooms/{roomId}/access   - パスワード・招待のみの設定の変更（ホストのみ、LOBBYのみ）
+	// POST /api/rooms/{roomId}/invites  - 1回だけ使える招待の発行（ホストのみ、LOBBYのみ）
 	// POST /api/rooms/{roomId}/start    - ゲーム開始
 	// POST /api/rooms/{roomId}/vote     - 投票
+	// PUT  /api/rooms/{roomId}/vote     - 投票の変更
+	// DELETE /api/rooms/{roomId}/vote   - 投票の取り消し
+	// POST /api/rooms/{roomId}/lockin   - 投票の確定
 	// POST /api/rooms/{roomId}/resolve  - 投票集計
+	// POST /api/rooms/{roomId}/tiebreak - 同数の政策から可決する政策を選ぶ（ホストのみ、HOST_DECIDES）
 	// POST /api/rooms/{roomId}/next     - 次ターンへ
 	// POST /api/rooms/{roomId}/petition - AI陳情
+	// GET  /api/rooms/{roomId}/result   - 最終結果
+	// GET  /api/rooms/{roomId}          - 部屋情報（秘匿情報は本人分のみ）
+	// GET  /api/rooms/{roomId}/players  - プレイヤー一覧（秘匿情報は本人分のみ）
+	// GET  /api/rooms/{roomId}/graveyard - 可決されなかった政策の一覧
+	// GET  /api/rooms/{roomId}/events   - イベント配信（Server-Sent Events）
+	// GET  /api/codes/{code}            - 部屋コードから部屋を探す
+	// POST /api/codes/{code}/join       - 部屋コードで部屋に参加
+	// GET  /api/lobbies                 - 参加者を募集している公開の部屋の一覧
+	// POST /api/quickmatch              - 公開の部屋に参加（なければ作成）
+	//
+	// 部屋の作成・参加で発行したセッショントークンを Authorization: Bearer で送る（h.Authenticate で検証）
+	// join・rejoin・result・graveyard・部屋コードの検索と参加・ロビー・クイックマッチ 以外の操作はセッションが必要で、リクエストボディの playerId は使わない
 
-	mux.HandleFunc("/api/rooms", func(w http.ResponseWriter, r *http.Request) {
+	mux.Handle("/api/rooms", h.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if handler.HandleCORS(w, r) {
 			return
 		}
@@ -153,9 +359,9 @@ func setupRoutes(mux *http.ServeMux, h *handler.Handler) {
 			return
 		}
 		http.NotFound(w, r)
-	})
+	})))
 
-	mux.HandleFunc("/api/rooms/", func(w http.ResponseWriter, r *http.Request) {
+	mux.Handle("/api/rooms/", h.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if handler.HandleCORS(w, r) {
 			return
 		}
@@ -164,24 +370,78 @@ func setupRoutes(mux *http.ServeMux, h *handler.Handler) {
 		switch {
 		case strings.HasSuffix(path, "/join"):
 			h.JoinRoom(w, r)
+		case strings.HasSuffix(path, "/rejoin"):
+			h.RejoinRoom(w, r)
 		case strings.HasSuffix(path, "/leave"):
 			h.LeaveRoom(w, r)
 		case strings.HasSuffix(path, "/ready"):
 			h.ToggleReady(w, r)
+		case strings.HasSuffix(path, "/settings"):
+			h.UpdateRoomSettings(w, r)
+		case strings.HasSuffix(path, "/access"):
+			h.UpdateRoomAccess(w, r)
+		case strings.HasSuffix(path, "/invites"):
+			h.CreateInvite(w, r)
 		case strings.HasSuffix(path, "/start"):
 			h.StartGame(w, r)
 		case strings.HasSuffix(path, "/vote"):
 			h.Vote(w, r)
+		case strings.HasSuffix(path, "/lockin"):
+			h.LockInVote(w, r)
 		case strings.HasSuffix(path, "/resolve"):
 			h.ResolveVote(w, r)
+		case strings.HasSuffix(path, "/tiebreak"):
+			h.DecideTie(w, r)
 		case strings.HasSuffix(path, "/next"):
 			h.NextTurn(w, r)
 		case strings.HasSuffix(path, "/petition"):
 			h.SubmitPetition(w, r)
+		case strings.HasSuffix(path, "/result"):
+			h.GetFinalResult(w, r)
+		case strings.HasSuffix(path, "/players"):
+			h.GetPlayers(w, r)
+		case strings.HasSuffix(path, "/graveyard"):
+			h.GetGraveyard(w, r)
+		case strings.HasSuffix(path, "/events"):
+			h.RoomEvents(w, r)
+		case !strings.Contains(strings.TrimPrefix(path, "/api/rooms/"), "/"):
+			// /api/rooms/{roomId}（サブパスなし）
+			h.GetRoom(w, r)
 		default:
 			http.NotFound(w, r)
 		}
-	})
+	})))
+
+	mux.Handle("/api/codes/", h.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler.HandleCORS(w, r) {
+			return
+		}
+
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/join"):
+			h.JoinRoomByCode(w, r)
+		case !strings.Contains(strings.TrimPrefix(path, "/api/codes/"), "/"):
+			// /api/codes/{code}（サブパスなし）
+			h.FindRoomByCode(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})))
+
+	mux.Handle("/api/lobbies", h.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler.HandleCORS(w, r) {
+			return
+		}
+		h.ListLobbies(w, r)
+	})))
+
+	mux.Handle("/api/quickmatch", h.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler.HandleCORS(w, r) {
+			return
+		}
+		h.QuickMatch(w, r)
+	})))
 
 	// Health check
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
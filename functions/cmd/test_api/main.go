@@ -37,7 +37,7 @@ func main() {
 	createRoomReq := map[string]interface{}{
 		"displayName": "テストプレイヤー1",
 	}
-	roomResp, err := postJSON(client, "/api/rooms", createRoomReq)
+	roomResp, err := postJSON(client, "/api/rooms", "", createRoomReq)
 	if err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 		return
@@ -46,6 +46,7 @@ func main() {
 
 	roomID, _ := roomResp["roomId"].(string)
 	player1ID, _ := roomResp["playerId"].(string)
+	token1, _ := roomResp["sessionToken"].(string)
 	if roomID == "" {
 		fmt.Println("❌ roomId not found in response")
 		return
@@ -57,23 +58,23 @@ func main() {
 	joinReq := map[string]interface{}{
 		"displayName": "テストプレイヤー2",
 	}
-	joinResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/join", roomID), joinReq)
+	joinResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/join", roomID), "", joinReq)
 	if err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 	} else {
 		fmt.Printf("✅ Joined: %s\n", prettyJSON(joinResp))
 	}
 	player2ID, _ := joinResp["playerId"].(string)
+	token2, _ := joinResp["sessionToken"].(string)
+	fmt.Printf("   playerId: %s\n", player2ID)
 
 	// 4. Ready状態トグル（プレイヤー1）- 初期値trueなので2回トグルしてtrueに戻す
 	fmt.Println("\n=== 4. Toggle Ready (Player 1) ===")
-	readyReq1 := map[string]interface{}{
-		"playerId": player1ID,
-	}
+	readyReq1 := map[string]interface{}{}
 	// 1回目: true -> false
-	postJSON(client, fmt.Sprintf("/api/rooms/%s/ready", roomID), readyReq1)
+	postJSON(client, fmt.Sprintf("/api/rooms/%s/ready", roomID), token1, readyReq1)
 	// 2回目: false -> true
-	readyResp1, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/ready", roomID), readyReq1)
+	readyResp1, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/ready", roomID), token1, readyReq1)
 	if err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 	} else {
@@ -82,13 +83,11 @@ func main() {
 
 	// 5. Ready状態トグル（プレイヤー2）
 	fmt.Println("\n=== 5. Toggle Ready (Player 2) ===")
-	readyReq2 := map[string]interface{}{
-		"playerId": player2ID,
-	}
+	readyReq2 := map[string]interface{}{}
 	// 1回目: true -> false
-	postJSON(client, fmt.Sprintf("/api/rooms/%s/ready", roomID), readyReq2)
+	postJSON(client, fmt.Sprintf("/api/rooms/%s/ready", roomID), token2, readyReq2)
 	// 2回目: false -> true
-	readyResp2, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/ready", roomID), readyReq2)
+	readyResp2, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/ready", roomID), token2, readyReq2)
 	if err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 	} else {
@@ -97,10 +96,8 @@ func main() {
 
 	// 6. ゲーム開始
 	fmt.Println("\n=== 6. Start Game ===")
-	startReq := map[string]interface{}{
-		"playerId": player1ID,
-	}
-	startResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/start", roomID), startReq)
+	startReq := map[string]interface{}{}
+	startResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/start", roomID), token1, startReq)
 	if err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 		fmt.Println("\n=== Test aborted (game not started) ===")
@@ -128,10 +125,9 @@ func main() {
 		// 7. 投票（プレイヤー1）- まだ全員投票完了ではない
 		fmt.Println("\n=== 7. Vote (Player 1) - 自動resolveテスト ===")
 		voteReq := map[string]interface{}{
-			"playerId": player1ID,
 			"policyId": targetPolicyID,
 		}
-		voteResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/vote", roomID), voteReq)
+		voteResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/vote", roomID), token1, voteReq)
 		if err != nil {
 			fmt.Printf("❌ Error: %v\n", err)
 		} else {
@@ -147,10 +143,9 @@ func main() {
 		// 8. 投票（プレイヤー2）- 全員投票完了 → 自動resolve
 		fmt.Println("\n=== 8. Vote (Player 2) - 最後の投票で自動resolve ===")
 		voteReq2 := map[string]interface{}{
-			"playerId": player2ID,
 			"policyId": targetPolicyID,
 		}
-		voteResp2, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/vote", roomID), voteReq2)
+		voteResp2, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/vote", roomID), token2, voteReq2)
 		if err != nil {
 			fmt.Printf("❌ Error: %v\n", err)
 		} else {
@@ -186,7 +181,7 @@ func main() {
 	// 9. 投票集計（後方互換テスト - 既にresolve済みなのでエラーになるはず）
 	fmt.Println("\n=== 9. Resolve Vote (後方互換テスト - 既にRESULT状態) ===")
 	resolveReq := map[string]interface{}{}
-	resolveResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/resolve", roomID), resolveReq)
+	resolveResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/resolve", roomID), token1, resolveReq)
 	if err != nil {
 		fmt.Printf("⚠️  期待通りエラー: %v\n", err)
 		fmt.Println("   （自動resolveにより既にRESULT状態のため）")
@@ -197,7 +192,7 @@ func main() {
 	// 10. 次ターン
 	fmt.Println("\n=== 10. Next Turn ===")
 	nextReq := map[string]interface{}{}
-	nextResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/next", roomID), nextReq)
+	nextResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/next", roomID), token1, nextReq)
 	if err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 	} else {
@@ -210,10 +205,9 @@ func main() {
 		fmt.Println("⏭️  Skipped (SKIP_PETITION=true)")
 	} else {
 		petitionReq := map[string]interface{}{
-			"playerId": player1ID,
-			"text":     "学校への支援を増やしてほしいです",
+			"text": "学校への支援を増やしてほしいです",
 		}
-		petitionResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/petition", roomID), petitionReq)
+		petitionResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/petition", roomID), token1, petitionReq)
 		if err != nil {
 			fmt.Printf("❌ Error: %v\n", err)
 		} else {
@@ -223,10 +217,8 @@ func main() {
 
 	// 12. 退出
 	fmt.Println("\n=== 12. Leave Room ===")
-	leaveReq := map[string]interface{}{
-		"playerId": player2ID,
-	}
-	leaveResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/leave", roomID), leaveReq)
+	leaveReq := map[string]interface{}{}
+	leaveResp, err := postJSON(client, fmt.Sprintf("/api/rooms/%s/leave", roomID), token2, leaveReq)
 	if err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 	} else {
@@ -236,7 +228,8 @@ func main() {
 	fmt.Println("\n=== Test Complete ===")
 }
 
-func postJSON(client *http.Client, path string, data map[string]interface{}) (map[string]interface{}, error) {
+// postJSON は JSON を POST する（token があれば Authorization: Bearer で送る）
+func postJSON(client *http.Client, path, token string, data map[string]interface{}) (map[string]interface{}, error) {
 	body, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
@@ -247,6 +240,9 @@ func postJSON(client *http.Client, path string, data map[string]interface{}) (ma
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
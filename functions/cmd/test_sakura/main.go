@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
 	"github.com/techworld-hackathon/functions/internal/interface/gateway/ai"
 )
 
@@ -77,7 +78,7 @@ func main() {
 		fmt.Printf("期待結果: %s\n", map[bool]string{true: "承認", false: "却下"}[tc.expectApproved])
 		fmt.Println("審査中...")
 
-		petitionCtx := &ai.PetitionContext{
+		petitionCtx := &service.PetitionContext{
 			PetitionText:   tc.petition,
 			PassedPolicies: tc.passedPolicies,
 			CityParams:     tc.cityParams,
@@ -25,36 +25,58 @@ func NewCityParams() CityParams {
 	}
 }
 
-// ApplyEffects は政策の効果を街に適用する
-func (c *CityParams) ApplyEffects(effects map[string]int) {
-	if v, ok := effects["economy"]; ok {
-		c.Economy += v
+// ApplyEffects は政策の効果を rules に従って街に適用する
+// 1. 境界の扱いが DIMINISHING なら、境界に近いほど効果を弱める
+// 2. CLAMP・DIMINISHING なら境界で止める
+// 3. COLLAPSE の境界に達した分野があれば崩壊（PolicyEffectKeys の順で最初の分野を原因とする）
+func (c *CityParams) ApplyEffects(effects map[string]int, rules CityRules) EffectOutcome {
+	outcome := EffectOutcome{Applied: make(map[string]int, len(PolicyEffectKeys))}
+	for _, key := range PolicyEffectKeys {
+		field := c.field(key)
+		delta, ok := effects[key]
+		if !ok {
+			continue
+		}
+		before := *field
+		*field = rules.For(key).apply(before, delta)
+		outcome.Applied[key] = *field - before
 	}
-	if v, ok := effects["welfare"]; ok {
-		c.Welfare += v
-	}
-	if v, ok := effects["education"]; ok {
-		c.Education += v
-	}
-	if v, ok := effects["environment"]; ok {
-		c.Environment += v
-	}
-	if v, ok := effects["security"]; ok {
-		c.Security += v
-	}
-	if v, ok := effects["humanRights"]; ok {
-		c.HumanRights += v
+	outcome.Collapse = c.CollapseCause(rules)
+	return outcome
+}
+
+// CollapseCause は COLLAPSE の境界に達した分野を返す（崩壊していなければ nil）
+func (c *CityParams) CollapseCause(rules CityRules) *CollapseCause {
+	for _, key := range PolicyEffectKeys {
+		value := *c.field(key)
+		rule := rules.For(key)
+		if rule.FloorMode == BoundModeCollapse && value <= rule.Floor {
+			return &CollapseCause{Param: key, Value: value, Bound: CollapseBoundFloor, Limit: rule.Floor}
+		}
+		if rule.CeilingMode == BoundModeCollapse && value >= rule.Ceiling {
+			return &CollapseCause{Param: key, Value: value, Bound: CollapseBoundCeiling, Limit: rule.Ceiling}
+		}
 	}
+	return nil
 }
 
-// IsCollapsed はいずれかのパラメータが0以下になったかを判定する
-func (c *CityParams) IsCollapsed() bool {
-	return c.Economy <= 0 ||
-		c.Welfare <= 0 ||
-		c.Education <= 0 ||
-		c.Environment <= 0 ||
-		c.Security <= 0 ||
-		c.HumanRights <= 0
+// field はキーに対応するパラメータへのポインタを返す
+func (c *CityParams) field(key string) *int {
+	switch key {
+	case "economy":
+		return &c.Economy
+	case "welfare":
+		return &c.Welfare
+	case "education":
+		return &c.Education
+	case "environment":
+		return &c.Environment
+	case "security":
+		return &c.Security
+	case "humanRights":
+		return &c.HumanRights
+	}
+	panic("entity: unknown city param " + key)
 }
 
 // ToMap は CityParams を map に変換する（スコア計算用）
@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+)
+
+// BoundMode はパラメータが下限・上限に達したときの扱いを表す
+type BoundMode string
+
+const (
+	BoundModeCollapse    BoundMode = "COLLAPSE"    // 境界に達したら国家崩壊
+	BoundModeClamp       BoundMode = "CLAMP"       // 境界で止める
+	BoundModeDiminishing BoundMode = "DIMINISHING" // 境界に近いほど効果を弱め、境界で止める
+)
+
+// 下限・上限に指定できる範囲
+const (
+	MinCityBound = -100
+	MaxCityBound = 200
+)
+
+// ParamRule は1分野の下限・上限とその扱いを表す
+type ParamRule struct {
+	Floor       int       `json:"floor" firestore:"floor"`             // 下限
+	Ceiling     int       `json:"ceiling" firestore:"ceiling"`         // 上限
+	FloorMode   BoundMode `json:"floorMode" firestore:"floorMode"`     // 下限に達したときの扱い
+	CeilingMode BoundMode `json:"ceilingMode" firestore:"ceilingMode"` // 上限に達したときの扱い
+}
+
+// DefaultParamRule は 0以下・100以上で崩壊するルールを返す
+func DefaultParamRule() ParamRule {
+	return ParamRule{
+		Floor:       0,
+		Ceiling:     100,
+		FloorMode:   BoundModeCollapse,
+		CeilingMode: BoundModeCollapse,
+	}
+}
+
+// CityRules は分野ごとのルール（キーは PolicyEffectKeys）
+// 指定のない分野は DefaultParamRule に従う
+type CityRules map[string]ParamRule
+
+// DefaultCityRules は全分野が DefaultParamRule のルールを返す
+func DefaultCityRules() CityRules {
+	rules := make(CityRules, len(PolicyEffectKeys))
+	for _, key := range PolicyEffectKeys {
+		rules[key] = DefaultParamRule()
+	}
+	return rules
+}
+
+// For は分野のルールを返す
+func (r CityRules) For(key string) ParamRule {
+	if rule, ok := r[key]; ok {
+		return rule
+	}
+	return DefaultParamRule()
+}
+
+// validate はルールが正しいか、初期値が崩壊・範囲外の状態でないかを確認し、問題点を返す
+func (r CityRules) validate(initial CityParams) []string {
+	var problems []string
+	for key := range r {
+		if !isPolicyEffectKey(key) {
+			problems = append(problems, fmt.Sprintf("cityRules.%s is not a city parameter", key))
+		}
+	}
+	initialValues := initial.ToMap()
+	for _, key := range PolicyEffectKeys {
+		rule := r.For(key)
+		if rule.Floor < MinCityBound || rule.Ceiling > MaxCityBound || rule.Floor >= rule.Ceiling {
+			problems = append(problems, fmt.Sprintf("cityRules.%s must satisfy %d <= floor < ceiling <= %d", key, MinCityBound, MaxCityBound))
+			continue
+		}
+		if !rule.FloorMode.valid() || !rule.CeilingMode.valid() {
+			problems = append(problems, fmt.Sprintf("cityRules.%s modes must be %s, %s or %s", key, BoundModeCollapse, BoundModeClamp, BoundModeDiminishing))
+			continue
+		}
+		if v := initialValues[key]; v <= rule.Floor || v >= rule.Ceiling {
+			problems = append(problems, fmt.Sprintf("initialCityParams.%s must be between %d and %d", key, rule.Floor+1, rule.Ceiling-1))
+		}
+	}
+	return problems
+}
+
+func (m BoundMode) valid() bool {
+	switch m {
+	case BoundModeCollapse, BoundModeClamp, BoundModeDiminishing:
+		return true
+	}
+	return false
+}
+
+func isPolicyEffectKey(key string) bool {
+	for _, k := range PolicyEffectKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// apply は value に delta を加えた値をルールに従って返す
+// DIMINISHING の場合、範囲の中央を越えて境界に向かう効果は境界までの距離に比例して弱める
+// （中央では100%、境界の直前では0%に近づく）
+func (rule ParamRule) apply(value, delta int) int {
+	half := float64(rule.Ceiling-rule.Floor) / 2
+	switch {
+	case delta > 0 && rule.CeilingMode == BoundModeDiminishing:
+		factor := math.Min(1, math.Max(0, float64(rule.Ceiling-value)/half))
+		delta = int(math.Round(float64(delta) * factor))
+	case delta < 0 && rule.FloorMode == BoundModeDiminishing:
+		factor := math.Min(1, math.Max(0, float64(value-rule.Floor)/half))
+		delta = int(math.Round(float64(delta) * factor))
+	}
+
+	result := value + delta
+	if result < rule.Floor && rule.FloorMode != BoundModeCollapse {
+		result = rule.Floor
+	}
+	if result > rule.Ceiling && rule.CeilingMode != BoundModeCollapse {
+		result = rule.Ceiling
+	}
+	return result
+}
+
+// CollapseBound は崩壊の原因となった境界を表す
+type CollapseBound string
+
+const (
+	CollapseBoundFloor   CollapseBound = "FLOOR"   // 下限を下回った
+	CollapseBoundCeiling CollapseBound = "CEILING" // 上限を上回った（過熱）
+)
+
+// CollapseCause は国家崩壊の原因を表す
+type CollapseCause struct {
+	Param string        `json:"param" firestore:"param"` // 原因となった分野（economy など）
+	Value int           `json:"value" firestore:"value"` // 崩壊時の値
+	Bound CollapseBound `json:"bound" firestore:"bound"` // 下限・上限のどちらに達したか
+	Limit int           `json:"limit" firestore:"limit"` // 達した境界の値
+}
+
+// EffectOutcome は政策の効果を適用した結果を表す
+type EffectOutcome struct {
+	Applied  map[string]int // 実際に変化した量（境界で止まった・弱まった分を反映）
+	Collapse *CollapseCause // 崩壊した場合の原因（崩壊していなければ nil）
+}
@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCityParams_ApplyEffects(t *testing.T) {
+	clampRule := ParamRule{Floor: 0, Ceiling: 100, FloorMode: BoundModeClamp, CeilingMode: BoundModeClamp}
+	diminishingRule := ParamRule{Floor: 0, Ceiling: 100, FloorMode: BoundModeDiminishing, CeilingMode: BoundModeDiminishing}
+
+	tests := []struct {
+		name         string
+		start        int // economy の初期値（他の分野は35）
+		rule         ParamRule
+		effects      map[string]int
+		wantEconomy  int
+		wantApplied  map[string]int
+		wantCollapse *CollapseCause
+	}{
+		{
+			name:        "範囲内ならそのまま適用",
+			start:       35,
+			rule:        DefaultParamRule(),
+			effects:     map[string]int{"economy": 20, "welfare": -5},
+			wantEconomy: 55,
+			wantApplied: map[string]int{"economy": 20, "welfare": -5},
+		},
+		{
+			name:         "上限に達すると過熱で崩壊",
+			start:        90,
+			rule:         DefaultParamRule(),
+			effects:      map[string]int{"economy": 15},
+			wantEconomy:  105,
+			wantApplied:  map[string]int{"economy": 15},
+			wantCollapse: &CollapseCause{Param: "economy", Value: 105, Bound: CollapseBoundCeiling, Limit: 100},
+		},
+		{
+			name:         "下限に達すると崩壊",
+			start:        10,
+			rule:         DefaultParamRule(),
+			effects:      map[string]int{"economy": -10},
+			wantEconomy:  0,
+			wantApplied:  map[string]int{"economy": -10},
+			wantCollapse: &CollapseCause{Param: "economy", Value: 0, Bound: CollapseBoundFloor, Limit: 0},
+		},
+		{
+			name:        "CLAMP は上限で止まり崩壊しない",
+			start:       90,
+			rule:        clampRule,
+			effects:     map[string]int{"economy": 30},
+			wantEconomy: 100,
+			wantApplied: map[string]int{"economy": 10},
+		},
+		{
+			name:        "CLAMP は下限で止まり崩壊しない",
+			start:       10,
+			rule:        clampRule,
+			effects:     map[string]int{"economy": -30},
+			wantEconomy: 0,
+			wantApplied: map[string]int{"economy": -10},
+		},
+		{
+			name:        "DIMINISHING は中央より手前では弱めない",
+			start:       35,
+			rule:        diminishingRule,
+			effects:     map[string]int{"economy": 10},
+			wantEconomy: 45,
+			wantApplied: map[string]int{"economy": 10},
+		},
+		{
+			name:        "DIMINISHING は上限に近いほど効果を弱める",
+			start:       80,
+			rule:        diminishingRule,
+			effects:     map[string]int{"economy": 20},
+			wantEconomy: 88, // 残り20 / 半幅50 = 40%
+			wantApplied: map[string]int{"economy": 8},
+		},
+		{
+			name:        "DIMINISHING は下限に近いほど効果を弱める",
+			start:       10,
+			rule:        diminishingRule,
+			effects:     map[string]int{"economy": -30},
+			wantEconomy: 4, // 残り10 / 半幅50 = 20%
+			wantApplied: map[string]int{"economy": -6},
+		},
+		{
+			name:        "DIMINISHING は反対方向の効果を弱めない",
+			start:       90,
+			rule:        diminishingRule,
+			effects:     map[string]int{"economy": -20},
+			wantEconomy: 70,
+			wantApplied: map[string]int{"economy": -20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			city := NewCityParams()
+			city.Economy = tt.start
+			rules := DefaultCityRules()
+			rules["economy"] = tt.rule
+
+			outcome := city.ApplyEffects(tt.effects, rules)
+			if city.Economy != tt.wantEconomy {
+				t.Errorf("economy = %d, want %d", city.Economy, tt.wantEconomy)
+			}
+			if !reflect.DeepEqual(outcome.Applied, tt.wantApplied) {
+				t.Errorf("Applied = %v, want %v", outcome.Applied, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(outcome.Collapse, tt.wantCollapse) {
+				t.Errorf("Collapse = %+v, want %+v", outcome.Collapse, tt.wantCollapse)
+			}
+		})
+	}
+}
+
+func TestCityRules_For_FallsBackToDefault(t *testing.T) {
+	var rules CityRules
+	if got := rules.For("economy"); got != DefaultParamRule() {
+		t.Errorf("For() = %+v, want default", got)
+	}
+}
@@ -0,0 +1,172 @@
+package entity
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// 山札は Room の deckIds（これから提示する政策）と discardIds（提示されたが可決されなかった政策）で管理する
+// 可決された政策は passedPolicyIds に移り、山札には戻らない
+// 可決されなかった政策は設定（recycleRejected）が有効なときだけ discardIds に入り、いずれ山札に戻る
+
+// RejectedPolicy は提示されたが可決されなかった政策の記録を表す（政策の墓場）
+// パス: rooms/{roomId} の rejectedPolicies フィールド
+type RejectedPolicy struct {
+	PolicyID string `json:"policyId" firestore:"policyId"`
+	Turn     int    `json:"turn" firestore:"turn"`         // 提示されたターン
+	Votes    int    `json:"votes" firestore:"votes"`       // 得点（PLURALITY なら得票数。voteCount を参照）
+	Recycled bool   `json:"recycled" firestore:"recycled"` // 捨て札に入り、山札に戻る対象になったか
+}
+
+// ShuffleDeck は政策IDの並びを rng でシャッフルしたコピーを返す（元のスライスは変更しない）
+func ShuffleDeck(rng *rand.Rand, policyIDs []string) []string {
+	deck := append([]string(nil), policyIDs...)
+	rng.Shuffle(len(deck), func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	})
+	return deck
+}
+
+// ResetDeck はゲーム開始時の山札をセットし、捨て札を空にする
+func (r *Room) ResetDeck(deckIDs []string) {
+	r.DeckIDs = deckIDs
+	r.DiscardIDs = make([]string, 0)
+}
+
+// DealPolicies は山札から optionsPerTurn 枚を currentPolicyIds に配る
+// 山札が足りなければ捨て札をシャッフルして山札の下に戻してから配る
+// 山札と捨て札を合わせても足りない場合は残りの枚数だけ配る（0枚なら currentPolicyIds は空になる）
+// 捨て札を戻した場合は true を返す
+func (r *Room) DealPolicies() bool {
+	count := r.Settings.OptionsPerTurn
+	reshuffled := false
+	if len(r.DeckIDs) < count && len(r.DiscardIDs) > 0 {
+		r.DeckIDs = append(r.DeckIDs, ShuffleDeck(r.Rand(), r.DiscardIDs)...)
+		r.DiscardIDs = make([]string, 0)
+		reshuffled = true
+	}
+
+	if len(r.DeckIDs) < count {
+		count = len(r.DeckIDs)
+	}
+	r.CurrentPolicyIDs = append(make([]string, 0, count), r.DeckIDs[:count]...)
+	r.DeckIDs = r.DeckIDs[count:]
+	return reshuffled
+}
+
+// RejectUnpassed は提示中の政策のうち可決されなかったものを得点（集計の最初のラウンド）とともに記録する
+// 設定で再利用が有効なら捨て札にも移す
+// 投票のリセット前に呼び出すこと。currentPolicyIds は結果表示のためそのまま残す
+func (r *Room) RejectUnpassed(passedPolicyID string) {
+	voteCount := r.voteCount()
+	for _, policyID := range r.CurrentPolicyIDs {
+		if policyID == passedPolicyID {
+			continue
+		}
+		r.RejectedPolicies = append(r.RejectedPolicies, RejectedPolicy{
+			PolicyID: policyID,
+			Turn:     r.Turn,
+			Votes:    voteCount[policyID],
+			Recycled: r.Settings.RecycleRejected,
+		})
+		if r.Settings.RecycleRejected {
+			r.DiscardIDs = append(r.DiscardIDs, policyID)
+		}
+	}
+}
+
+// HasPoliciesLeft は次のターンに提示できる政策が残っているかを判定する
+func (r *Room) HasPoliciesLeft() bool {
+	return len(r.DeckIDs)+len(r.DiscardIDs) > 0
+}
+
+// PetitionPlacement は承認された陳情の政策を山札のどこに入れるかを表す
+type PetitionPlacement string
+
+const (
+	PetitionPlacementNextHand       PetitionPlacement = "NEXT_HAND"       // 次のターンの選択肢に必ず入れる
+	PetitionPlacementRandomWithin   PetitionPlacement = "RANDOM_WITHIN"   // 山札の先頭 petitionWindow 枚以内のランダムな位置に入れる
+	PetitionPlacementReplaceCurrent PetitionPlacement = "REPLACE_CURRENT" // 提示中の選択肢のうち最も得票の少ないものと入れ替える
+)
+
+// PetitionPlacementResult は陳情の政策を山札に入れた結果を表す
+type PetitionPlacementResult struct {
+	SurfaceTurn      int      // 選択肢として提示されるターン
+	ReplacedPolicyID string   // REPLACE_CURRENT で入れ替えられた政策（山札の先頭に戻る）
+	ClearedVoters    []string // 入れ替えられた政策に投票していたため投票を取り消したプレイヤー
+}
+
+// PlacePetition は承認された陳情の政策を設定（petitionPlacement）に従って山札に入れる
+// 山札は先頭から optionsPerTurn 枚ずつ配られるため、入れた位置から提示されるターンが決まる
+// （その後の陳情や入れ替えで後ろにずれることはあるが、前にずれることはない）
+// 同数の決着待ちの間は選択肢を変えないよう、REPLACE_CURRENT は NEXT_HAND として扱う
+func (r *Room) PlacePetition(policyID string) PetitionPlacementResult {
+	placement := r.Settings.PetitionPlacement
+	if placement == PetitionPlacementReplaceCurrent && r.PendingTie != nil {
+		placement = PetitionPlacementNextHand
+	}
+	switch placement {
+	case PetitionPlacementReplaceCurrent:
+		return r.replaceCurrentOption(policyID)
+	case PetitionPlacementRandomWithin:
+		limit := r.Settings.PetitionWindow
+		if limit > len(r.DeckIDs) {
+			limit = len(r.DeckIDs)
+		}
+		return r.insertIntoDeck(policyID, r.Rand().Intn(limit+1))
+	default:
+		// 既に次の手札に入れた陳情の後ろに入れる（先に承認された陳情を押し出さない）
+		index := 0
+		for index < len(r.DeckIDs) && r.GetGeneratedPolicy(r.DeckIDs[index]) != nil {
+			index++
+		}
+		return r.insertIntoDeck(policyID, index)
+	}
+}
+
+// insertIntoDeck は山札の index 番目に政策を入れる
+func (r *Room) insertIntoDeck(policyID string, index int) PetitionPlacementResult {
+	deck := make([]string, 0, len(r.DeckIDs)+1)
+	deck = append(deck, r.DeckIDs[:index]...)
+	deck = append(deck, policyID)
+	deck = append(deck, r.DeckIDs[index:]...)
+	r.DeckIDs = deck
+	return PetitionPlacementResult{SurfaceTurn: r.Turn + 1 + index/r.Settings.OptionsPerTurn}
+}
+
+// replaceCurrentOption は提示中の選択肢のうち最も得点の少ないもの（同数なら後に提示されたもの）を政策と入れ替える
+// 入れ替えられた政策は山札の先頭に戻し、その政策を含む票は取り消す（順位・賛成の付け直しが必要なため）
+func (r *Room) replaceCurrentOption(policyID string) PetitionPlacementResult {
+	if len(r.CurrentPolicyIDs) == 0 {
+		return r.insertIntoDeck(policyID, 0)
+	}
+
+	voteCount := r.voteCount()
+	target := len(r.CurrentPolicyIDs) - 1
+	for i := len(r.CurrentPolicyIDs) - 2; i >= 0; i-- {
+		if voteCount[r.CurrentPolicyIDs[i]] < voteCount[r.CurrentPolicyIDs[target]] {
+			target = i
+		}
+	}
+	replaced := r.CurrentPolicyIDs[target]
+
+	current := append([]string(nil), r.CurrentPolicyIDs...)
+	current[target] = policyID
+	r.CurrentPolicyIDs = current
+	r.DeckIDs = append([]string{replaced}, r.DeckIDs...)
+
+	var cleared []string
+	for userID, ballot := range r.CurrentBallots() {
+		if contains(ballot, replaced) {
+			r.ClearBallot(userID)
+			cleared = append(cleared, userID)
+		}
+	}
+	sort.Strings(cleared)
+
+	return PetitionPlacementResult{
+		SurfaceTurn:      r.Turn,
+		ReplacedPolicyID: replaced,
+		ClearedVoters:    cleared,
+	}
+}
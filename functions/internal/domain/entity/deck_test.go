@@ -0,0 +1,208 @@
+package entity
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestRoom_DealPolicies(t *testing.T) {
+	tests := []struct {
+		name           string
+		deck           []string
+		discard        []string
+		wantOptions    []string // nil なら中身は確認しない
+		wantCount      int
+		wantDeck       int
+		wantReshuffled bool
+	}{
+		{
+			name:        "山札の先頭から配る",
+			deck:        []string{"a", "b", "c", "d"},
+			discard:     []string{"x"},
+			wantOptions: []string{"a", "b", "c"},
+			wantCount:   3,
+			wantDeck:    1,
+		},
+		{
+			name:           "足りなければ捨て札を山札の下に戻してから配る",
+			deck:           []string{"a"},
+			discard:        []string{"x", "y", "z"},
+			wantCount:      3,
+			wantDeck:       1,
+			wantReshuffled: true,
+		},
+		{
+			name:           "合わせても足りなければ残りだけ配る",
+			deck:           []string{"a"},
+			discard:        []string{"x"},
+			wantCount:      2,
+			wantDeck:       0,
+			wantReshuffled: true,
+		},
+		{
+			name:      "山札も捨て札も空なら配らない",
+			wantCount: 0,
+			wantDeck:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := NewRoom("host", DefaultRoomSettings())
+			room.DeckIDs = append([]string(nil), tt.deck...)
+			room.DiscardIDs = append([]string(nil), tt.discard...)
+
+			reshuffled := room.DealPolicies()
+			if reshuffled != tt.wantReshuffled {
+				t.Errorf("reshuffled = %v, want %v", reshuffled, tt.wantReshuffled)
+			}
+			if len(room.CurrentPolicyIDs) != tt.wantCount || len(room.DeckIDs) != tt.wantDeck {
+				t.Fatalf("current = %v, deck = %v", room.CurrentPolicyIDs, room.DeckIDs)
+			}
+			if tt.wantOptions != nil {
+				for i, id := range tt.wantOptions {
+					if room.CurrentPolicyIDs[i] != id {
+						t.Errorf("current = %v, want %v", room.CurrentPolicyIDs, tt.wantOptions)
+						break
+					}
+				}
+			}
+			if len(tt.deck) > 0 && room.CurrentPolicyIDs[0] != tt.deck[0] {
+				t.Errorf("山札に残っていた政策が先に配られていない: %v", room.CurrentPolicyIDs)
+			}
+
+			// 政策は失われも重複もしない
+			all := append(append(append([]string(nil), room.CurrentPolicyIDs...), room.DeckIDs...), room.DiscardIDs...)
+			want := append(append([]string(nil), tt.deck...), tt.discard...)
+			sort.Strings(all)
+			sort.Strings(want)
+			if len(all) != len(want) {
+				t.Fatalf("政策 = %v, want %v", all, want)
+			}
+			for i := range all {
+				if all[i] != want[i] {
+					t.Fatalf("政策 = %v, want %v", all, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRoom_RejectUnpassed(t *testing.T) {
+	for _, recycle := range []bool{true, false} {
+		room := NewRoom("host", DefaultRoomSettings())
+		room.Settings.RecycleRejected = recycle
+		room.Turn = 4
+		room.CurrentPolicyIDs = []string{"a", "b", "c"}
+		room.DiscardIDs = []string{"x"}
+		room.Votes = map[string]string{"u1": "b", "u2": "b", "u3": "a"}
+
+		room.RejectUnpassed("b")
+
+		want := []RejectedPolicy{
+			{PolicyID: "a", Turn: 4, Votes: 1, Recycled: recycle},
+			{PolicyID: "c", Turn: 4, Votes: 0, Recycled: recycle},
+		}
+		if len(room.RejectedPolicies) != len(want) {
+			t.Fatalf("recycle=%v: rejectedPolicies = %+v, want %+v", recycle, room.RejectedPolicies, want)
+		}
+		for i := range want {
+			if room.RejectedPolicies[i] != want[i] {
+				t.Errorf("recycle=%v: rejectedPolicies[%d] = %+v, want %+v", recycle, i, room.RejectedPolicies[i], want[i])
+			}
+		}
+
+		wantDiscard := []string{"x"}
+		if recycle {
+			wantDiscard = []string{"x", "a", "c"}
+		}
+		if len(room.DiscardIDs) != len(wantDiscard) {
+			t.Errorf("recycle=%v: discardIds = %v, want %v", recycle, room.DiscardIDs, wantDiscard)
+		}
+		if len(room.CurrentPolicyIDs) != 3 {
+			t.Error("結果表示のため currentPolicyIds は残す")
+		}
+	}
+}
+
+func TestRoom_PlacePetition(t *testing.T) {
+	newRoom := func(placement PetitionPlacement) *Room {
+		room := NewRoom("host", DefaultRoomSettings())
+		room.Settings.PetitionPlacement = placement
+		room.Settings.PetitionWindow = 4
+		room.Turn = 2
+		room.CurrentPolicyIDs = []string{"a", "b", "c"}
+		room.DeckIDs = []string{"d", "e", "f", "g", "h", "i", "j"}
+		room.Votes = map[string]string{"u1": "a", "u2": "c", "u3": "a", "u4": ""}
+		return room
+	}
+	petition := func(room *Room) string {
+		return room.AddGeneratedPolicy(&MasterPolicy{Title: "陳情"})
+	}
+
+	t.Run("NEXT_HAND は先に承認された陳情の後ろに入る", func(t *testing.T) {
+		room := newRoom(PetitionPlacementNextHand)
+		first := petition(room)
+		if got := room.PlacePetition(first); got.SurfaceTurn != 3 || room.DeckIDs[0] != first {
+			t.Fatalf("result = %+v, deck = %v", got, room.DeckIDs)
+		}
+		second := petition(room)
+		if got := room.PlacePetition(second); got.SurfaceTurn != 3 || room.DeckIDs[1] != second {
+			t.Fatalf("result = %+v, deck = %v", got, room.DeckIDs)
+		}
+		if got := room.PlacePetition(petition(room)); got.SurfaceTurn != 3 {
+			t.Fatalf("3件目: result = %+v, want turn 3", got)
+		}
+		// 手札（3枚）からあふれた陳情はその次のターン
+		if got := room.PlacePetition(petition(room)); got.SurfaceTurn != 4 {
+			t.Fatalf("4件目: result = %+v, want turn 4", got)
+		}
+	})
+
+	t.Run("RANDOM_WITHIN は山札の先頭 petitionWindow 枚以内に入る", func(t *testing.T) {
+		for i := 0; i < 50; i++ {
+			room := newRoom(PetitionPlacementRandomWithin)
+			id := petition(room)
+			got := room.PlacePetition(id)
+			index := -1
+			for j, deckID := range room.DeckIDs {
+				if deckID == id {
+					index = j
+				}
+			}
+			if index < 0 || index > 4 {
+				t.Fatalf("index = %d, want 0〜4 (deck = %v)", index, room.DeckIDs)
+			}
+			if want := 3 + index/3; got.SurfaceTurn != want {
+				t.Fatalf("SurfaceTurn = %d, want %d (index %d)", got.SurfaceTurn, want, index)
+			}
+		}
+	})
+
+	t.Run("REPLACE_CURRENT は最も得票の少ない選択肢と入れ替える", func(t *testing.T) {
+		room := newRoom(PetitionPlacementReplaceCurrent)
+		id := petition(room)
+		got := room.PlacePetition(id)
+
+		if got.SurfaceTurn != 2 || got.ReplacedPolicyID != "b" || len(got.ClearedVoters) != 0 {
+			t.Errorf("result = %+v, want turn 2, replaced b", got)
+		}
+		if room.CurrentPolicyIDs[1] != id || room.DeckIDs[0] != "b" {
+			t.Errorf("current = %v, deck = %v", room.CurrentPolicyIDs, room.DeckIDs)
+		}
+	})
+
+	t.Run("REPLACE_CURRENT で入れ替えた選択肢への投票は取り消す", func(t *testing.T) {
+		room := newRoom(PetitionPlacementReplaceCurrent)
+		room.Votes = map[string]string{"u1": "a", "u2": "b", "u3": "c", "u4": "a"}
+		got := room.PlacePetition(petition(room))
+
+		// b と c が1票ずつで同数 → 後に提示された c を入れ替える
+		if got.ReplacedPolicyID != "c" || len(got.ClearedVoters) != 1 || got.ClearedVoters[0] != "u3" {
+			t.Fatalf("result = %+v, want replaced c, cleared [u3]", got)
+		}
+		if room.Votes["u3"] != "" || room.Votes["u2"] != "b" {
+			t.Errorf("votes = %v", room.Votes)
+		}
+	})
+}
@@ -12,12 +12,20 @@ var (
 	ErrNotEnoughPlayers   = errors.New("not enough players to start")
 	ErrNotAllVoted        = errors.New("not all players have voted")
 	ErrNotAllReady        = errors.New("not all players are ready")
+	ErrGameNotFinished    = errors.New("game has not finished yet")
+	ErrInvalidSettings    = errors.New("invalid room settings")
+	ErrInvalidRoomCode    = errors.New("invalid room code")
+	ErrRoomCodeTaken      = errors.New("room code is already reserved")
+	ErrNoRoomCodeLeft     = errors.New("no room code is available")
 
 	// Player errors
 	ErrPlayerNotFound      = errors.New("player not found")
 	ErrPlayerNotInRoom     = errors.New("player is not in this room")
 	ErrPlayerAlreadyInRoom = errors.New("player is already in this room")
 	ErrAlreadyVoted        = errors.New("player has already voted")
+	ErrNotVoted            = errors.New("player has not voted")
+	ErrVoteLockedIn        = errors.New("vote has already been locked in")
+	ErrInvalidBallot       = errors.New("invalid ballot")
 	ErrPetitionUsed        = errors.New("petition has already been used")
 
 	// Policy errors
@@ -28,8 +36,24 @@ var (
 	ErrNoIdeologyAvailable = errors.New("no ideology available")
 
 	// Authorization errors
-	ErrNotHost = errors.New("only host can perform this action")
+	ErrNotHost             = errors.New("only host can perform this action")
+	ErrUnauthenticated     = errors.New("valid session token is required")
+	ErrSessionRoomMismatch = errors.New("session token is not for this room")
+	ErrInvalidRejoinCode   = errors.New("rejoin code does not match any player in this room")
+
+	// Room access errors
+	ErrRoomPasswordRequired = errors.New("room password is required")
+	ErrInvalidRoomPassword  = errors.New("room password is incorrect")
+	ErrInviteRequired       = errors.New("room is invite-only")
+	ErrInvalidInvite        = errors.New("invite is invalid, expired or already used")
+	ErrTooManyJoinAttempts  = errors.New("too many failed attempts to join this room, try again later")
+	ErrTooManyInvites       = errors.New("too many active invites for this room")
+
+	// Job errors
+	ErrJobLeaseLost = errors.New("job lease has expired and was taken by another worker")
 
 	// AI errors
-	ErrPetitionRejected = errors.New("petition was rejected by AI")
+	ErrPetitionRejected  = errors.New("petition was rejected by AI")
+	ErrInvalidAIResponse = errors.New("AI returned an invalid response")
+	ErrPetitionBlocked   = errors.New("petition was blocked by moderation")
 )
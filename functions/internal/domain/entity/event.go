@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// RoomEventType は部屋で発生したイベントの種類を表す
+type RoomEventType string
+
+const (
+	RoomEventPlayerJoined      RoomEventType = "PLAYER_JOINED"      // プレイヤー参加
+	RoomEventPlayerRejoined    RoomEventType = "PLAYER_REJOINED"    // プレイヤーの再接続（同じ席に戻った）
+	RoomEventPlayerLeft        RoomEventType = "PLAYER_LEFT"        // プレイヤー退出
+	RoomEventReadyToggled      RoomEventType = "READY_TOGGLED"      // Ready状態変更
+	RoomEventSettingsUpdated   RoomEventType = "SETTINGS_UPDATED"   // 部屋設定の変更
+	RoomEventGameStarted       RoomEventType = "GAME_STARTED"       // ゲーム開始
+	RoomEventVoteCast          RoomEventType = "VOTE_CAST"          // 投票・投票の変更（誰が投票したかのみ、投票先は含めない）
+	RoomEventVoteRetracted     RoomEventType = "VOTE_RETRACTED"     // 投票の取り消し
+	RoomEventVoteLockedIn      RoomEventType = "VOTE_LOCKED_IN"     // 投票の確定
+	RoomEventTurnResolved      RoomEventType = "TURN_RESOLVED"      // 投票集計完了
+	RoomEventTurnAdvanced      RoomEventType = "TURN_ADVANCED"      // 次ターンへ
+	RoomEventGameFinished      RoomEventType = "GAME_FINISHED"      // ゲーム終了
+	RoomEventPetitionSubmitted RoomEventType = "PETITION_SUBMITTED" // 陳情の審査完了
+	RoomEventOptionsReplaced   RoomEventType = "OPTIONS_REPLACED"   // 陳情の政策で提示中の選択肢を入れ替え
+	RoomEventTiePending        RoomEventType = "TIE_PENDING"        // 同数で決着待ち（ホストの選択 or 再投票）
+	RoomEventPhaseTimedOut     RoomEventType = "PHASE_TIMED_OUT"    // 制限時間切れでサーバーが進行させた（VOTING・RESULT）
+)
+
+// RoomEvent は部屋で発生したイベントを表す
+// GET /api/rooms/{roomId}/events でクライアントに配信する
+// ⚠️ Data には秘匿情報（思想・投票先・effects など）を含めない
+type RoomEvent struct {
+	Type       RoomEventType          `json:"type"`
+	RoomID     string                 `json:"roomId"`
+	PlayerID   string                 `json:"playerId,omitempty"` // イベントを起こしたプレイヤー
+	Turn       int                    `json:"turn"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	OccurredAt time.Time              `json:"occurredAt"`
+}
+
+// NewRoomEvent は新しいイベントを作成する
+func NewRoomEvent(eventType RoomEventType, roomID string, room *Room) RoomEvent {
+	event := RoomEvent{
+		Type:       eventType,
+		RoomID:     roomID,
+		Data:       make(map[string]interface{}),
+		OccurredAt: time.Now(),
+	}
+	if room != nil {
+		event.Turn = room.Turn
+	}
+	return event
+}
@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// プレイヤーが操作するたびに lastActivityAt を更新し、一定時間操作のない部屋はサーバーが削除する
+// （タブを閉じたまま放置された部屋・プレイヤー・街の画像が残り続けないようにする）
+
+// RoomTTL は操作のない部屋を残しておく時間を表す
+type RoomTTL struct {
+	Idle     time.Duration // LOBBY・VOTING・RESULT の部屋
+	Finished time.Duration // FINISHED の部屋（最終結果を見られるように長めに残す）
+}
+
+// DefaultRoomTTL は部屋を残しておく時間のデフォルト
+var DefaultRoomTTL = RoomTTL{
+	Idle:     2 * time.Hour,
+	Finished: 24 * time.Hour,
+}
+
+// Min は短い方の時間を返す（削除の候補を探す範囲に使う）
+func (t RoomTTL) Min() time.Duration {
+	if t.Finished < t.Idle {
+		return t.Finished
+	}
+	return t.Idle
+}
+
+// Touch はプレイヤーが操作した日時を記録する
+func (r *Room) Touch(now time.Time) {
+	r.LastActivityAt = now
+}
+
+// LastActive はプレイヤーが最後に操作した日時を返す
+// lastActivityAt がない（記録する前に作られた）部屋は作成日時を使う
+func (r *Room) LastActive() time.Time {
+	if r.LastActivityAt.IsZero() {
+		return r.CreatedAt
+	}
+	return r.LastActivityAt
+}
+
+// ExpiresAt は部屋を削除してよくなる日時を返す
+func (r *Room) ExpiresAt(ttl RoomTTL) time.Time {
+	if r.Status == RoomStatusFinished {
+		return r.LastActive().Add(ttl.Finished)
+	}
+	return r.LastActive().Add(ttl.Idle)
+}
+
+// Expired は部屋を残しておく時間を過ぎたかを判定する
+func (r *Room) Expired(now time.Time, ttl RoomTTL) bool {
+	return !now.Before(r.ExpiresAt(ttl))
+}
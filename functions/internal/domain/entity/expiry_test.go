@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoom_Expired(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ttl := RoomTTL{Idle: time.Hour, Finished: 24 * time.Hour}
+	tests := []struct {
+		name         string
+		status       RoomStatus
+		createdAt    time.Time
+		lastActivity time.Time
+		want         bool
+	}{
+		{name: "操作から Idle 経っていない", status: RoomStatusVoting, lastActivity: now.Add(-59 * time.Minute), want: false},
+		{name: "操作から Idle 経った", status: RoomStatusLobby, lastActivity: now.Add(-time.Hour), want: true},
+		{name: "FINISHED は Finished まで残す", status: RoomStatusFinished, lastActivity: now.Add(-2 * time.Hour), want: false},
+		{name: "FINISHED で Finished 経った", status: RoomStatusFinished, lastActivity: now.Add(-25 * time.Hour), want: true},
+		{name: "lastActivityAt がなければ作成日時から数える", status: RoomStatusResult, createdAt: now.Add(-2 * time.Hour), want: true},
+		{name: "lastActivityAt は作成日時より優先する", status: RoomStatusResult, createdAt: now.Add(-2 * time.Hour), lastActivity: now.Add(-time.Minute), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &Room{Status: tt.status, CreatedAt: tt.createdAt, LastActivityAt: tt.lastActivity}
+			if got := room.Expired(now, ttl); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoomTTL_Min(t *testing.T) {
+	if got := (RoomTTL{Idle: time.Hour, Finished: 24 * time.Hour}).Min(); got != time.Hour {
+		t.Errorf("Min() = %v, want 1h", got)
+	}
+	if got := (RoomTTL{Idle: time.Hour, Finished: time.Minute}).Min(); got != time.Minute {
+		t.Errorf("Min() = %v, want 1m", got)
+	}
+}
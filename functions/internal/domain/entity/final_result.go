@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// GameEndReason はゲーム終了の理由を表す
+type GameEndReason string
+
+const (
+	GameEndReasonMaxTurns      GameEndReason = "MAX_TURNS"      // 最終ターンまで完了
+	GameEndReasonCollapsed     GameEndReason = "COLLAPSED"      // 街が崩壊
+	GameEndReasonDeckExhausted GameEndReason = "DECK_EXHAUSTED" // 提示できる政策が尽きた
+)
+
+// FinalResult はゲーム終了時の最終結果を表す
+// パス: rooms/{roomId} の finalResult フィールド（FINISHED 時のみ）
+type FinalResult struct {
+	Reason     GameEndReason  `json:"reason" firestore:"reason"`
+	CityParams CityParams     `json:"cityParams" firestore:"cityParams"` // 最終的な街のパラメータ
+	Rankings   []PlayerResult `json:"rankings" firestore:"rankings"`     // 順位順
+	FinishedAt time.Time      `json:"finishedAt" firestore:"finishedAt"`
+}
+
+// PlayerResult はプレイヤーごとの最終スコアを表す
+// ゲーム終了後は思想を公開する
+type PlayerResult struct {
+	PlayerID    string          `json:"playerId" firestore:"playerId"`
+	DisplayName string          `json:"displayName" firestore:"displayName"`
+	Ideology    *MasterIdeology `json:"ideology" firestore:"ideology"`
+	Score       int             `json:"score" firestore:"score"`
+	Rank        int             `json:"rank" firestore:"rank"` // 同点は同順位（1, 1, 3, ...）
+	IsWinner    bool            `json:"isWinner" firestore:"isWinner"`
+}
+
+// NewFinalResult は最終的な街の状態から全プレイヤーのスコアを計算し、順位付けする
+// players は { userId: Player }
+func NewFinalResult(reason GameEndReason, cityParams CityParams, players map[string]*Player) *FinalResult {
+	rankings := make([]PlayerResult, 0, len(players))
+	for playerID, p := range players {
+		rankings = append(rankings, PlayerResult{
+			PlayerID:    playerID,
+			DisplayName: p.DisplayName,
+			Ideology:    p.Ideology,
+			Score:       p.CalculateScore(&cityParams),
+		})
+	}
+
+	// スコア降順、同点は表示名・IDの順で並べて結果を安定させる
+	sort.Slice(rankings, func(i, j int) bool {
+		if rankings[i].Score != rankings[j].Score {
+			return rankings[i].Score > rankings[j].Score
+		}
+		if rankings[i].DisplayName != rankings[j].DisplayName {
+			return rankings[i].DisplayName < rankings[j].DisplayName
+		}
+		return rankings[i].PlayerID < rankings[j].PlayerID
+	})
+
+	for i := range rankings {
+		if i > 0 && rankings[i].Score == rankings[i-1].Score {
+			rankings[i].Rank = rankings[i-1].Rank
+		} else {
+			rankings[i].Rank = i + 1
+		}
+		rankings[i].IsWinner = rankings[i].Rank == 1
+	}
+
+	return &FinalResult{
+		Reason:     reason,
+		CityParams: cityParams,
+		Rankings:   rankings,
+		FinishedAt: time.Now(),
+	}
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "testing"
+
+func TestNewFinalResult_Rankings(t *testing.T) {
+	economist := &MasterIdeology{IdeologyID: "eco", Coefficients: map[string]float64{"economy": 1}}
+	ecologist := &MasterIdeology{IdeologyID: "env", Coefficients: map[string]float64{"environment": 1}}
+	city := NewCityParams()
+	city.Economy = 80
+	city.Environment = 20
+
+	players := map[string]*Player{
+		"u1": {DisplayName: "Bob", Ideology: economist},
+		"u2": {DisplayName: "Alice", Ideology: economist},
+		"u3": {DisplayName: "Carol", Ideology: ecologist},
+	}
+
+	result := NewFinalResult(GameEndReasonMaxTurns, city, players)
+
+	want := []struct {
+		playerID string
+		rank     int
+		winner   bool
+	}{
+		{"u2", 1, true}, // 同点は表示名順
+		{"u1", 1, true},
+		{"u3", 3, false},
+	}
+	if len(result.Rankings) != len(want) {
+		t.Fatalf("rankings = %d人, want %d人", len(result.Rankings), len(want))
+	}
+	for i, w := range want {
+		got := result.Rankings[i]
+		if got.PlayerID != w.playerID || got.Rank != w.rank || got.IsWinner != w.winner {
+			t.Errorf("rankings[%d] = {%s rank=%d winner=%v}, want {%s rank=%d winner=%v}",
+				i, got.PlayerID, got.Rank, got.IsWinner, w.playerID, w.rank, w.winner)
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"crypto/rand"
+	"math/big"
+)
+
 // MasterIdeology は思想マスターを表す
 // パス: master_ideologies/{ideologyId}
 // IdeologyID はドキュメントIDと同一
@@ -22,6 +27,19 @@ func (i *MasterIdeology) CalculateScore(cityParams *CityParams) int {
 	return int(score)
 }
 
+// PickIdeology は思想をランダムに1つ選ぶ
+// 思想は秘匿情報のため、部屋の seed から再計算できないよう部屋の乱数ではなく crypto/rand を使う
+func PickIdeology(ideologies []MasterIdeology) (MasterIdeology, error) {
+	if len(ideologies) == 0 {
+		return MasterIdeology{}, ErrNoIdeologyAvailable
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(ideologies))))
+	if err != nil {
+		return MasterIdeology{}, err
+	}
+	return ideologies[n.Int64()], nil
+}
+
 // GetDefaultIdeologies はデフォルトの思想マスターを返す
 // 係数設計: 最重視 +2.0, 重視 +1.0, やや重視 +0.5, 中立 0.0, 対立 -0.5~-1.0
 // 全思想の係数合計を約3.0〜3.5に統一してバランスを取る
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPickIdeology(t *testing.T) {
+	if _, err := PickIdeology(nil); !errors.Is(err, ErrNoIdeologyAvailable) {
+		t.Errorf("PickIdeology(nil) error = %v, want ErrNoIdeologyAvailable", err)
+	}
+
+	ideologies := GetDefaultIdeologies()
+	seen := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		got, err := PickIdeology(ideologies)
+		if err != nil {
+			t.Fatalf("PickIdeology: %v", err)
+		}
+		seen[got.IdeologyID] = true
+	}
+	if len(seen) != len(ideologies) {
+		t.Errorf("選ばれた思想 = %d種類, want %d種類", len(seen), len(ideologies))
+	}
+}
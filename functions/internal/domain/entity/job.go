@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// JobType はバックグラウンドで実行するジョブの種類を表す
+type JobType string
+
+const (
+	JobTypeRoomDeadline JobType = "ROOM_DEADLINE"      // 部屋のフェーズの期限切れの処理（payload: roomId）
+	JobTypeCityImage    JobType = "CITY_IMAGE"         // 街の画像の生成・アップロードのやり直し（payload: roomId, turn）
+	JobTypeDeleteImages JobType = "DELETE_CITY_IMAGES" // 削除した部屋の街の画像の削除のやり直し（payload: roomId）
+)
+
+// JobStatus はジョブの状態を表す
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "PENDING" // 実行待ち（runAt 以降に実行する）
+	JobStatusRunning JobStatus = "RUNNING" // 実行中（runAt はリースの期限。過ぎたら実行したインスタンスが落ちたとみなして再び実行する）
+	JobStatusDone    JobStatus = "DONE"    // 完了
+	JobStatusFailed  JobStatus = "FAILED"  // 最大回数まで失敗した
+)
+
+// DefaultJobMaxAttempts はジョブを実行する最大回数のデフォルト
+const DefaultJobMaxAttempts = 5
+
+// Job はバックグラウンドで実行するジョブを表す
+// パス: jobs/{jobId}
+// ID は冪等キーを兼ね、同じIDのジョブは1度しか登録されない
+// 実行が重複しても（リース切れ・再試行）結果が変わらないよう、ハンドラーは冪等に作ること
+type Job struct {
+	ID          string            `json:"id" firestore:"-"`
+	Type        JobType           `json:"type" firestore:"type"`
+	Payload     map[string]string `json:"payload" firestore:"payload"`
+	Status      JobStatus         `json:"status" firestore:"status"`
+	RunAt       time.Time         `json:"runAt" firestore:"runAt"`             // 実行予定時刻（RUNNING の間はリースの期限）
+	Attempts    int               `json:"attempts" firestore:"attempts"`       // これまでに実行した回数
+	MaxAttempts int               `json:"maxAttempts" firestore:"maxAttempts"` // 実行する最大回数
+	LastError   string            `json:"lastError" firestore:"lastError"`     // 最後に失敗したときのエラー
+	CreatedAt   time.Time         `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// NewJob は runAt 以降に実行するジョブを作成する
+func NewJob(id string, jobType JobType, payload map[string]string, runAt time.Time) *Job {
+	now := time.Now()
+	return &Job{
+		ID:          id,
+		Type:        jobType,
+		Payload:     payload,
+		Status:      JobStatusPending,
+		RunAt:       runAt,
+		Attempts:    0,
+		MaxAttempts: DefaultJobMaxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// NewRoomDeadlineJob は部屋の現在のフェーズの期限に実行するジョブを作成する（期限がなければ nil）
+// ID に期限を含めるため、同じ期限のジョブは1度しか登録されない
+func NewRoomDeadlineJob(roomID string, room *Room) *Job {
+	if room.Deadline == nil {
+		return nil
+	}
+	id := fmt.Sprintf("deadline_%s_%d", roomID, room.Deadline.UnixNano())
+	return NewJob(id, JobTypeRoomDeadline, map[string]string{"roomId": roomID}, *room.Deadline)
+}
+
+// NewCityImageJob は街の画像の生成・アップロードをやり直すジョブを作成する
+func NewCityImageJob(roomID string, turn int, runAt time.Time) *Job {
+	id := fmt.Sprintf("city_image_%s_%d", roomID, turn)
+	return NewJob(id, JobTypeCityImage, map[string]string{"roomId": roomID, "turn": fmt.Sprint(turn)}, runAt)
+}
+
+// NewDeleteCityImagesJob は削除した部屋の街の画像の削除をやり直すジョブを作成する
+func NewDeleteCityImagesJob(roomID string, runAt time.Time) *Job {
+	return NewJob("delete_city_images_"+roomID, JobTypeDeleteImages, map[string]string{"roomId": roomID}, runAt)
+}
+
+// Due は now の時点で実行できるか（実行待ちで予定時刻を過ぎたか、実行中でリースが切れたか）を判定する
+func (j *Job) Due(now time.Time) bool {
+	return (j.Status == JobStatusPending || j.Status == JobStatusRunning) && !now.Before(j.RunAt)
+}
+
+// Claim は now から lease の間、ジョブを実行中にする（実行回数を1つ進める）
+func (j *Job) Claim(now time.Time, lease time.Duration) {
+	j.Status = JobStatusRunning
+	j.RunAt = now.Add(lease)
+	j.Attempts++
+	j.UpdatedAt = now
+}
+
+// HeldBy は保存されているジョブが、claimed を取り出したときのリースのままかを判定する
+// リースが切れて他のインスタンスが取り出し直すと attempts が進むため、実行回数でリースを見分ける
+func (j *Job) HeldBy(claimed *Job) bool {
+	return j.Status == JobStatusRunning && j.Attempts == claimed.Attempts
+}
+
+// Finished は完了・失敗したジョブ（これ以上実行しない）かを判定する
+func (j *Job) Finished() bool {
+	return j.Status == JobStatusDone || j.Status == JobStatusFailed
+}
+
+// Exhausted は最大回数まで実行したかを判定する
+func (j *Job) Exhausted() bool {
+	return j.Attempts >= j.MaxAttempts
+}
+
+// Backoff は失敗後に再び実行するまでの待ち時間を返す（base を実行回数ごとに2倍にし、max で打ち止め）
+func (j *Job) Backoff(base, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < j.Attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
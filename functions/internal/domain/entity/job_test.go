@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJob_Backoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 5, want: 10 * time.Second}, // 上限で打ち止め
+		{attempts: 50, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		job := &Job{Attempts: tt.attempts}
+		if got := job.Backoff(time.Second, 10*time.Second); got != tt.want {
+			t.Errorf("attempts = %d: backoff = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestNewRoomDeadlineJob(t *testing.T) {
+	if job := NewRoomDeadlineJob("r1", &Room{}); job != nil {
+		t.Errorf("期限のない部屋でジョブが作られた: %+v", job)
+	}
+
+	deadline := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	room := &Room{Deadline: &deadline}
+	job := NewRoomDeadlineJob("r1", room)
+	if job == nil || !job.RunAt.Equal(deadline) || job.Payload["roomId"] != "r1" {
+		t.Fatalf("job = %+v, want r1 の期限に実行", job)
+	}
+	// 同じ期限なら同じID、期限が変われば別のID
+	if again := NewRoomDeadlineJob("r1", room); again.ID != job.ID {
+		t.Errorf("同じ期限でIDが異なる: %s, %s", job.ID, again.ID)
+	}
+	next := deadline.Add(time.Minute)
+	if other := NewRoomDeadlineJob("r1", &Room{Deadline: &next}); other.ID == job.ID {
+		t.Errorf("期限が変わってもIDが同じ: %s", job.ID)
+	}
+}
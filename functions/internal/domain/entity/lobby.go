@@ -0,0 +1,31 @@
+package entity
+
+import "fmt"
+
+// RoomVisibility は部屋の公開範囲を表す（作成時に決まり、後から変更しない）
+type RoomVisibility string
+
+const (
+	RoomVisibilityPrivate RoomVisibility = "PRIVATE" // 部屋ID・部屋コードを知っている人だけが参加できる
+	RoomVisibilityPublic  RoomVisibility = "PUBLIC"  // ロビーの一覧に表示し、クイックマッチで相手を探す
+)
+
+// ParseRoomVisibility は指定された公開範囲を返す（空なら PRIVATE）
+func ParseRoomVisibility(v string) (RoomVisibility, error) {
+	switch RoomVisibility(v) {
+	case "":
+		return RoomVisibilityPrivate, nil
+	case RoomVisibilityPrivate, RoomVisibilityPublic:
+		return RoomVisibility(v), nil
+	}
+	return "", fmt.Errorf("%w: visibility must be %s or %s", ErrInvalidSettings, RoomVisibilityPublic, RoomVisibilityPrivate)
+}
+
+// IsOpenLobby は公開ロビーとして他のプレイヤーを待っているかを判定する
+// playerCount は部屋の現在の参加人数
+func (r *Room) IsOpenLobby(playerCount int) bool {
+	return r.Visibility == RoomVisibilityPublic &&
+		!r.InviteOnly &&
+		r.Status == RoomStatusLobby &&
+		playerCount < r.Settings.MaxPlayers
+}
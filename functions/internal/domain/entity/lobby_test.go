@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRoomVisibility(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    RoomVisibility
+		wantErr bool
+	}{
+		{"", RoomVisibilityPrivate, false},
+		{"PRIVATE", RoomVisibilityPrivate, false},
+		{"PUBLIC", RoomVisibilityPublic, false},
+		{"public", "", true},
+		{"FRIENDS", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRoomVisibility(tt.input)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidSettings) {
+				t.Errorf("ParseRoomVisibility(%q) error = %v, want ErrInvalidSettings", tt.input, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRoomVisibility(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestRoom_IsOpenLobby(t *testing.T) {
+	settings := DefaultRoomSettings()
+	public := NewRoom("host", settings)
+	public.Visibility = RoomVisibilityPublic
+
+	if !public.IsOpenLobby(1) {
+		t.Error("公開の LOBBY が一覧に出ない")
+	}
+	if public.IsOpenLobby(settings.MaxPlayers) {
+		t.Error("満員の部屋が一覧に出る")
+	}
+
+	private := NewRoom("host", settings)
+	if private.IsOpenLobby(1) {
+		t.Error("非公開の部屋が一覧に出る")
+	}
+
+	public.Start()
+	if public.IsOpenLobby(1) {
+		t.Error("ゲーム中の部屋が一覧に出る")
+	}
+}
@@ -3,18 +3,23 @@ package entity
 // Player はプレイヤーを表す
 // パス: rooms/{roomId}/players/{userId}
 //
-// ⚠️ ideology, currentVote は Security Rules で本人以外読み取り禁止
+// ⚠️ ideology, currentVote, currentBallot は Security Rules で本人以外読み取り禁止
 // 投票状態は Room.Votes の keys で判断可能
 type Player struct {
 	// 🌐 公開情報
 	DisplayName    string `json:"displayName" firestore:"displayName"`
 	IsHost         bool   `json:"isHost" firestore:"isHost"`
 	IsReady        bool   `json:"isReady" firestore:"isReady"`
-	IsPetitionUsed bool   `json:"isPetitionUsed" firestore:"isPetitionUsed"`
+	IsPetitionUsed bool   `json:"isPetitionUsed" firestore:"isPetitionUsed"` // 陳情を使い切ったか
+	PetitionsUsed  int    `json:"petitionsUsed" firestore:"petitionsUsed"`   // 陳情した回数
 
 	// 🔒 秘匿情報（本人のみ読み取り可）
-	Ideology    *MasterIdeology `json:"ideology" firestore:"ideology"`
-	CurrentVote string          `json:"currentVote" firestore:"currentVote"`
+	Ideology      *MasterIdeology `json:"ideology" firestore:"ideology"`
+	CurrentVote   string          `json:"currentVote" firestore:"currentVote"`     // 第1希望
+	CurrentBallot []string        `json:"currentBallot" firestore:"currentBallot"` // 票の全体（希望順・賛成した政策の全て）
+
+	// 🔒 サーバーのみ（PlayerResponse に含めない）
+	RejoinCodeHash string `json:"rejoinCodeHash,omitempty" firestore:"rejoinCodeHash,omitempty"` // 再参加コードのハッシュ
 }
 
 // NewPlayer は新しいプレイヤーを作成する
@@ -30,13 +35,34 @@ func NewPlayer(displayName string, isHost bool, ideology *MasterIdeology) *Playe
 }
 
 // Vote は投票を行う
-func (p *Player) Vote(policyID string) {
-	p.CurrentVote = policyID
+func (p *Player) Vote(ballot []string) {
+	p.CurrentVote = FirstChoice(ballot)
+	p.CurrentBallot = ballot
 }
 
 // ClearVote は投票をクリアする（次のターン用）
 func (p *Player) ClearVote() {
 	p.CurrentVote = ""
+	p.CurrentBallot = nil
+}
+
+// CanPetition は陳情できる回数が残っているかを判定する
+func (p *Player) CanPetition(limit int) bool {
+	return !p.IsPetitionUsed && p.PetitionsUsed < limit
+}
+
+// UsePetition は陳情を1回消費する（上限に達したら isPetitionUsed を true に）
+func (p *Player) UsePetition(limit int) {
+	p.PetitionsUsed++
+	p.IsPetitionUsed = p.PetitionsUsed >= limit
+}
+
+// RemainingPetitions は残りの陳情回数を返す
+func (p *Player) RemainingPetitions(limit int) int {
+	if p.IsPetitionUsed || p.PetitionsUsed >= limit {
+		return 0
+	}
+	return limit - p.PetitionsUsed
 }
 
 // CalculateScore はスコアを計算する
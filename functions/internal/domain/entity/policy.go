@@ -11,6 +11,17 @@ type MasterPolicy struct {
 	Effects     map[string]int `json:"effects" firestore:"effects"` // ⚠️ クライアントに直接渡さない
 }
 
+// AI陳情で生成される政策の制約
+const (
+	GeneratedPolicyEffectMin      = -20 // 1分野あたりの効果の下限
+	GeneratedPolicyEffectMax      = 20  // 1分野あたりの効果の上限
+	GeneratedPolicyTitleMaxLength = 20  // タイトルの最大文字数
+	GeneratedPolicyNewsMaxLength  = 100 // ニュース速報の最大文字数
+)
+
+// PolicyEffectKeys は政策の effects に使う分野のキー（CityParams の JSON キーと同じ）
+var PolicyEffectKeys = []string{"economy", "welfare", "education", "environment", "security", "humanRights"}
+
 // PolicyOption はクライアントに渡す政策情報（effects を除外）
 // Room.CurrentOptions で使用
 type PolicyOption struct {
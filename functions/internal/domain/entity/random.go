@@ -0,0 +1,28 @@
+package entity
+
+import "math/rand"
+
+// 部屋の乱数は seed（部屋の作成時に決める）と randCount（これまでに乱数を使った回数）から決まる
+// 同じ seed の部屋で同じ操作を同じ順に行えば、山札の並び・同数時の選択なども同じになる
+// （不具合の報告を受けたときに seed を指定して部屋を作り直せば再現できる。seed の指定はローカル開発のみ）
+// 思想の割り当ては秘匿情報のため部屋の乱数を使わない（PickIdeology を参照）
+
+// NewSeed は新しい部屋の seed を返す
+func NewSeed() int64 {
+	return rand.Int63()
+}
+
+// Rand は部屋の次の乱数生成器を返す（呼び出すたびに randCount を1つ進める）
+// randCount は部屋と一緒に保存されるため、Rand を呼んだ後は部屋を保存すること
+func (r *Room) Rand() *rand.Rand {
+	r.RandCount++
+	return rand.New(rand.NewSource(mixSeed(r.Seed, r.RandCount)))
+}
+
+// mixSeed は seed と回数から乱数生成器の種を作る（splitmix64 で隣り合う回数の種が似ないようにする）
+func mixSeed(seed, count int64) int64 {
+	z := uint64(seed) + uint64(count)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
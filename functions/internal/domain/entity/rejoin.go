@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// 再参加コードは部屋の参加時にプレイヤーへ1度だけ見せるコードで、
+// セッショントークンを失った（別の端末・ストレージの消去など）プレイヤーが同じ席に戻るために使う
+// 部屋コードと同じ文字を使い、保存するのはハッシュのみ
+
+// RejoinCodeLength は再参加コードの文字数
+const RejoinCodeLength = 8
+
+// NewRejoinCode は新しい再参加コードを返す
+func NewRejoinCode() (string, error) {
+	return randomCode(RejoinCodeLength)
+}
+
+// NormalizeRejoinCode は入力された再参加コードを比較できる形にする（大文字に揃え、空白・ハイフンを除く）
+func NormalizeRejoinCode(code string) string {
+	return normalizeCode(code)
+}
+
+// hashRejoinCode は再参加コードのハッシュを返す
+func hashRejoinCode(code string) string {
+	sum := sha256.Sum256([]byte(NormalizeRejoinCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// SetRejoinCode はプレイヤーの再参加コードを設定する（ハッシュのみ保存する）
+func (p *Player) SetRejoinCode(code string) {
+	p.RejoinCodeHash = hashRejoinCode(code)
+}
+
+// MatchesRejoinCode は再参加コードがプレイヤーのものかを判定する
+func (p *Player) MatchesRejoinCode(code string) bool {
+	if p.RejoinCodeHash == "" || NormalizeRejoinCode(code) == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(p.RejoinCodeHash), []byte(hashRejoinCode(code))) == 1
+}
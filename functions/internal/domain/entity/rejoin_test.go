@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewRejoinCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := NewRejoinCode()
+		if err != nil {
+			t.Fatalf("NewRejoinCode: %v", err)
+		}
+		if len(code) != RejoinCodeLength {
+			t.Fatalf("len(%q) = %d, want %d", code, len(code), RejoinCodeLength)
+		}
+		for _, c := range code {
+			if !strings.ContainsRune(codeAlphabet, c) {
+				t.Fatalf("%q contains %q outside the alphabet", code, c)
+			}
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestPlayer_MatchesRejoinCode(t *testing.T) {
+	p := NewPlayer("p", false, nil)
+	if p.MatchesRejoinCode("") {
+		t.Error("コード未設定のプレイヤーに空のコードが一致した")
+	}
+
+	p.SetRejoinCode("ABCD2345")
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"ABCD2345", true},
+		{"abcd2345", true},
+		{"ABCD-2345", true},
+		{" ABCD 2345 ", true},
+		{"ABCD2346", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := p.MatchesRejoinCode(tt.code); got != tt.want {
+			t.Errorf("MatchesRejoinCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
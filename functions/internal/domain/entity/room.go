@@ -2,6 +2,7 @@ package entity
 
 import (
 	"math/rand"
+	"sort"
 	"time"
 )
 
@@ -18,51 +19,96 @@ const (
 // Room はゲームルームを表す
 // パス: rooms/{roomId}
 type Room struct {
+	Code              string                   `json:"code" firestore:"code,omitempty"` // 部屋コード（room_code.go を参照）
 	HostID            string                   `json:"hostId" firestore:"hostId"`
+	Visibility        RoomVisibility           `json:"visibility" firestore:"visibility"`                         // 公開範囲（lobby.go を参照）
+	InviteOnly        bool                     `json:"inviteOnly" firestore:"inviteOnly"`                         // 招待がないと参加できない（room_access.go を参照）
+	PasswordHash      string                   `json:"passwordHash,omitempty" firestore:"passwordHash,omitempty"` // 🔒 パスワードのハッシュ（APIのレスポンスに含めない）
+	Invites           map[string]*RoomInvite   `json:"invites,omitempty" firestore:"invites,omitempty"`           // 🔒 { 招待トークンのハッシュ: 招待 }（使ったら削除）
+	JoinFailures      *JoinFailures            `json:"joinFailures,omitempty" firestore:"joinFailures,omitempty"` // 🔒 参加に失敗した回数
 	Status            RoomStatus               `json:"status" firestore:"status"`
 	Turn              int                      `json:"turn" firestore:"turn"`
-	MaxTurns          int                      `json:"maxTurns" firestore:"maxTurns"`
+	MaxTurns          int                      `json:"maxTurns" firestore:"maxTurns"` // Settings.MaxTurns と同じ値（既存クライアント向け）
+	Settings          RoomSettings             `json:"settings" firestore:"settings"`
+	Seed              int64                    `json:"seed" firestore:"seed"`           // 部屋の乱数の種（random.go を参照）
+	RandCount         int64                    `json:"randCount" firestore:"randCount"` // これまでに乱数を使った回数
 	CreatedAt         time.Time                `json:"createdAt" firestore:"createdAt"`
+	LastActivityAt    time.Time                `json:"lastActivityAt" firestore:"lastActivityAt"` // プレイヤーが最後に操作した日時（expiry.go を参照）
 	CityParams        CityParams               `json:"cityParams" firestore:"cityParams"`
 	IsCollapsed       bool                     `json:"isCollapsed" firestore:"isCollapsed"`
 	CurrentPolicyIDs  []string                 `json:"currentPolicyIds" firestore:"currentPolicyIds"` // IDのみ
 	DeckIDs           []string                 `json:"deckIds" firestore:"deckIds"`                   // 山札
+	DiscardIDs        []string                 `json:"discardIds" firestore:"discardIds"`             // 捨て札（提示されたが可決されなかった政策）
 	PassedPolicyIDs   []string                 `json:"passedPolicyIds" firestore:"passedPolicyIds"`   // 可決された政策の履歴
-	Votes             map[string]string        `json:"votes" firestore:"votes"`                       // { userId: policyId }
+	RejectedPolicies  []RejectedPolicy         `json:"rejectedPolicies" firestore:"rejectedPolicies"` // 可決されなかった政策の記録（政策の墓場）
+	Votes             map[string]string        `json:"votes" firestore:"votes"`                       // { userId: policyId }（第1希望。未投票なら空文字）
+	Ballots           map[string][]string      `json:"ballots" firestore:"ballots"`                   // { userId: [policyId, ...] }（希望順・賛成した政策の全て）
+	LockedIn          map[string]bool          `json:"lockedIn" firestore:"lockedIn"`                 // { userId: true }（投票を確定したプレイヤー。確定後は変更・取り消しできない）
+	PendingTie        *PendingTie              `json:"pendingTie" firestore:"pendingTie"`             // 同数で決着待ち（HOST_DECIDES・REVOTE のみ）
+	Deadline          *time.Time               `json:"deadline" firestore:"deadline"`                 // 現在のフェーズ（VOTING・RESULT）の期限（制限時間なしなら nil。timer.go を参照）
 	LastResult        *VoteResult              `json:"lastResult" firestore:"lastResult"`
 	GeneratedPolicies map[string]*MasterPolicy `json:"generatedPolicies" firestore:"generatedPolicies"` // AI陳情で生成された政策
+	FinalResult       *FinalResult             `json:"finalResult" firestore:"finalResult"`             // 最終結果（FINISHED 時のみ）
 }
 
 // VoteResult は投票結果を表す（RESULT フェーズで使用）
 type VoteResult struct {
-	PassedPolicyID    string            `json:"passedPolicyId" firestore:"passedPolicyId"`
-	PassedPolicyTitle string            `json:"passedPolicyTitle" firestore:"passedPolicyTitle"`
-	ActualEffects     map[string]int    `json:"actualEffects" firestore:"actualEffects"`
-	NewsFlash         string            `json:"newsFlash" firestore:"newsFlash"`
-	VoteDetails       map[string]string `json:"voteDetails" firestore:"voteDetails"`
-	CityImage         string            `json:"cityImage,omitempty" firestore:"-"`               // Base64エンコードされた街の画像（Firestoreには保存しない）
-	CityImageURL      string            `json:"cityImageUrl,omitempty" firestore:"cityImageUrl"` // GCSにアップロードされた画像のsigned URL
+	PassedPolicyID    string              `json:"passedPolicyId" firestore:"passedPolicyId"`
+	PassedPolicyTitle string              `json:"passedPolicyTitle" firestore:"passedPolicyTitle"`
+	ActualEffects     map[string]int      `json:"actualEffects" firestore:"actualEffects"`
+	NewsFlash         string              `json:"newsFlash" firestore:"newsFlash"`
+	VotingMethod      VotingMethod        `json:"votingMethod" firestore:"votingMethod"`             // 集計方式
+	VoteDetails       map[string][]string `json:"voteDetails" firestore:"voteDetails"`               // { userId: [policyId, ...] }（各プレイヤーの票の全体）
+	Rounds            []VoteRound         `json:"rounds" firestore:"rounds"`                         // 集計のラウンド（INSTANT_RUNOFF では脱落の経過）
+	CityImage         string              `json:"cityImage,omitempty" firestore:"-"`                 // Base64エンコードされた街の画像（Firestoreには保存しない）
+	CityImageURL      string              `json:"cityImageUrl,omitempty" firestore:"cityImageUrl"`   // GCSにアップロードされた画像のsigned URL
+	CollapseCause     *CollapseCause      `json:"collapseCause,omitempty" firestore:"collapseCause"` // 国家崩壊の原因（崩壊した場合のみ）
 }
 
-// NewRoom は新しい部屋を作成する
-func NewRoom(hostID string) *Room {
+// NewRoom は指定した設定で新しい部屋を作成する（設定は検証済みであること）
+func NewRoom(hostID string, settings RoomSettings) *Room {
+	now := time.Now()
 	return &Room{
 		HostID:            hostID,
+		Visibility:        RoomVisibilityPrivate,
 		Status:            RoomStatusLobby,
 		Turn:              0,
-		MaxTurns:          10,
-		CreatedAt:         time.Now(),
-		CityParams:        NewCityParams(),
+		MaxTurns:          settings.MaxTurns,
+		Settings:          settings,
+		Seed:              NewSeed(),
+		CreatedAt:         now,
+		LastActivityAt:    now,
+		CityParams:        settings.InitialCityParams,
 		IsCollapsed:       false,
 		CurrentPolicyIDs:  make([]string, 0),
 		DeckIDs:           make([]string, 0),
+		DiscardIDs:        make([]string, 0),
 		PassedPolicyIDs:   make([]string, 0),
+		RejectedPolicies:  make([]RejectedPolicy, 0),
 		Votes:             make(map[string]string),
+		Ballots:           make(map[string][]string),
+		LockedIn:          make(map[string]bool),
 		LastResult:        nil,
 		GeneratedPolicies: make(map[string]*MasterPolicy),
+		FinalResult:       nil,
 	}
 }
 
+// UpdateSettings は部屋の設定を変更する（LOBBY のみ）
+// 街パラメータは新しい初期値に置き換える
+func (r *Room) UpdateSettings(settings RoomSettings) error {
+	if r.Status != RoomStatusLobby {
+		return ErrInvalidPhase
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	r.Settings = settings
+	r.MaxTurns = settings.MaxTurns
+	r.CityParams = settings.InitialCityParams
+	return nil
+}
+
 // CanStart はゲームを開始できるかどうかを判定する
 func (r *Room) CanStart(playerCount int) bool {
 	return playerCount >= 2 && r.Status == RoomStatusLobby
@@ -75,20 +121,33 @@ func (r *Room) Start() {
 }
 
 // IsGameOver はゲーム終了条件を満たしているかを判定する
-// turn >= maxTurns（最終ターン完了後）または街が崩壊した場合に終了
+// turn >= maxTurns（最終ターン完了後）、街が崩壊した場合、または次に提示できる政策が残っていない場合に終了
 func (r *Room) IsGameOver() bool {
-	return r.Turn >= r.MaxTurns || r.IsCollapsed
+	return r.Turn >= r.MaxTurns || r.IsCollapsed || !r.HasPoliciesLeft()
 }
 
-// Finish はゲームを終了する
+// Finish はゲームを終了する（フェーズの期限もなくなる）
 func (r *Room) Finish() {
 	r.Status = RoomStatusFinished
+	r.Deadline = nil
 }
 
-// ApplyPolicyEffects は政策の効果を適用する
-func (r *Room) ApplyPolicyEffects(effects map[string]int) {
-	r.CityParams.ApplyEffects(effects)
-	r.IsCollapsed = r.CityParams.IsCollapsed()
+// EndReason はゲーム終了の理由を返す
+func (r *Room) EndReason() GameEndReason {
+	if r.IsCollapsed {
+		return GameEndReasonCollapsed
+	}
+	if r.Turn < r.MaxTurns && !r.HasPoliciesLeft() {
+		return GameEndReasonDeckExhausted
+	}
+	return GameEndReasonMaxTurns
+}
+
+// ApplyPolicyEffects は政策の効果を部屋の設定（cityRules）に従って適用する
+func (r *Room) ApplyPolicyEffects(effects map[string]int) EffectOutcome {
+	outcome := r.CityParams.ApplyEffects(effects, r.Settings.CityRules)
+	r.IsCollapsed = outcome.Collapse != nil
+	return outcome
 }
 
 // NextTurn は次のターンに進める
@@ -97,6 +156,7 @@ func (r *Room) NextTurn() {
 	r.Status = RoomStatusVoting
 	r.CurrentPolicyIDs = make([]string, 0)
 	r.Votes = make(map[string]string) // 投票リセット
+	r.Ballots = make(map[string][]string)
 	// LastResult は次の投票結果が出るまで保持する
 }
 
@@ -125,57 +185,182 @@ func (r *Room) AddGeneratedPolicy(policy *MasterPolicy) string {
 		r.GeneratedPolicies = make(map[string]*MasterPolicy)
 	}
 	// ユニークなIDを生成（generated_から始まる）
-	policyID := "generated_" + randString(8)
+	policyID := "generated_" + randString(r.Rand(), 8)
 	policy.PolicyID = policyID
 	r.GeneratedPolicies[policyID] = policy
 	return policyID
 }
 
+// contains は list に s が含まれるかを判定する
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // randString はランダムな文字列を生成する
-func randString(n int) string {
+func randString(rng *rand.Rand, n int) string {
 	const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
 	b := make([]byte, n)
 	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+		b[i] = letters[rng.Intn(len(letters))]
 	}
 	return string(b)
 }
 
-// CountVotes は投票を集計し、最多得票の政策IDを返す
-// 同数の場合はランダムに選択
-func (r *Room) CountVotes() string {
-	voteCount := make(map[string]int)
-	for _, policyID := range r.Votes {
-		if policyID != "" {
-			voteCount[policyID]++
+// ValidateBallot は票が投票できる政策だけからなり、集計方式（votingMethod）に合った形かを確認する
+// 同数による再投票中は同数の政策だけに投票できる
+func (r *Room) ValidateBallot(ballot []string) error {
+	options := r.votableOptions()
+	for _, policyID := range ballot {
+		if !contains(options, policyID) {
+			return ErrInvalidPolicy
 		}
 	}
+	return r.Settings.VotingMethod.validateBallot(ballot, len(options))
+}
+
+// CastBallot はプレイヤーの票を記録する（votes には第1希望を入れる）
+func (r *Room) CastBallot(userID string, ballot []string) {
+	if r.Votes == nil {
+		r.Votes = make(map[string]string)
+	}
+	if r.Ballots == nil {
+		r.Ballots = make(map[string][]string)
+	}
+	r.Votes[userID] = FirstChoice(ballot)
+	r.Ballots[userID] = ballot
+}
+
+// ClearBallot はプレイヤーの票を取り消す（未投票に戻し、確定も解除する）
+func (r *Room) ClearBallot(userID string) {
+	r.Votes[userID] = ""
+	delete(r.Ballots, userID)
+	delete(r.LockedIn, userID)
+}
+
+// ResetVotes は全員を未投票に戻す（キーは残す）
+func (r *Room) ResetVotes() {
+	for userID := range r.Votes {
+		r.Votes[userID] = ""
+	}
+	r.Ballots = make(map[string][]string)
+	r.LockedIn = make(map[string]bool)
+}
+
+// HasVoted はプレイヤーが投票済みかを判定する
+func (r *Room) HasVoted(userID string) bool {
+	return r.Votes[userID] != ""
+}
+
+// LockIn はプレイヤーの投票を確定する
+func (r *Room) LockIn(userID string) {
+	if r.LockedIn == nil {
+		r.LockedIn = make(map[string]bool)
+	}
+	r.LockedIn[userID] = true
+}
+
+// IsLockedIn はプレイヤーが投票を確定したかを判定する
+func (r *Room) IsLockedIn(userID string) bool {
+	return r.LockedIn[userID]
+}
 
-	// 最多得票数を求める
-	maxVotes := 0
-	for _, count := range voteCount {
-		if count > maxVotes {
-			maxVotes = count
+// Undecided はまだ決めていないプレイヤーの数を返す
+// 未投票のプレイヤーに加え、requireLockIn の場合は投票を確定していないプレイヤーも数える
+func (r *Room) Undecided(playerCount int) int {
+	decided := 0
+	for userID, vote := range r.Votes {
+		if vote != "" && (!r.Settings.RequireLockIn || r.LockedIn[userID]) {
+			decided++
 		}
 	}
+	if decided >= playerCount {
+		return 0
+	}
+	return playerCount - decided
+}
 
-	// 最多得票の政策を全て集める
-	var candidates []string
-	for policyID, count := range voteCount {
-		if count == maxVotes {
-			candidates = append(candidates, policyID)
+// ReadyToResolve は集計できるか（全員が投票し、requireLockIn の場合は全員が確定したか）を判定する
+func (r *Room) ReadyToResolve(playerCount int) bool {
+	return r.Undecided(playerCount) == 0
+}
+
+// FirstChoice は票の第1希望を返す（空の票なら空文字）
+func FirstChoice(ballot []string) string {
+	if len(ballot) == 0 {
+		return ""
+	}
+	return ballot[0]
+}
+
+// CurrentBallots は投票済みプレイヤーの票を返す（{ userId: [policyId, ...] }）
+// ballots がない投票（votes のみ）は第1希望だけの票として扱う
+func (r *Room) CurrentBallots() map[string][]string {
+	ballots := make(map[string][]string, len(r.Votes))
+	for userID, policyID := range r.Votes {
+		if policyID == "" {
+			continue
+		}
+		if ballot := r.Ballots[userID]; len(ballot) > 0 {
+			ballots[userID] = append([]string(nil), ballot...)
+		} else {
+			ballots[userID] = []string{policyID}
 		}
 	}
+	return ballots
+}
 
-	// 候補がない場合は空文字を返す
-	if len(candidates) == 0 {
+// TallyVotes は設定の集計方式（votingMethod）で票を集計する
+func (r *Room) TallyVotes() VoteTally {
+	ballots := r.CurrentBallots()
+	userIDs := make([]string, 0, len(ballots))
+	for userID := range ballots {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+	ordered := make([][]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ordered = append(ordered, ballots[userID])
+	}
+	return NewVoteCounter(r.Settings.VotingMethod).Count(ordered, r.votableOptions())
+}
+
+// CountVotes は投票を集計し、選ばれた政策IDを返す
+// 同数の場合は PickWinner に従う
+func (r *Room) CountVotes() string {
+	return r.PickWinner(r.TallyVotes())
+}
+
+// PickWinner は集計結果の勝者を1つに決める（勝者がいなければ空文字）
+// 同数の場合、FIRST_OPTION なら提示順で先の政策、それ以外は部屋の乱数でランダムに選ぶ
+// （LOWEST_MAGNITUDE・HOST_DECIDES・REVOTE は政策の効果やプレイヤーの操作が必要なためユースケースで扱い、決着がつかなければここに来る）
+func (r *Room) PickWinner(tally VoteTally) string {
+	switch len(tally.Winners) {
+	case 0:
 		return ""
+	case 1:
+		return tally.Winners[0]
 	}
 
-	// 同数の場合はランダムに選択
-	if len(candidates) == 1 {
-		return candidates[0]
+	// 同数の場合、提示順で先の政策を選ぶ（Winners は提示順に並んでいる）
+	if r.Settings.TieBreakRule == TieBreakFirstOption {
+		return tally.Winners[0]
 	}
 
-	return candidates[rand.Intn(len(candidates))]
+	// 同数の場合はランダムに選択
+	return tally.Winners[r.Rand().Intn(len(tally.Winners))]
+}
+
+// voteCount は政策ごとの得点（集計の最初のラウンド）を返す
+// PLURALITY・INSTANT_RUNOFF では第1希望の得票数、APPROVAL では賛成数、BORDA ではボルダ点
+// 同数による再投票中は、同数になった最初の投票の得点を返す
+func (r *Room) voteCount() map[string]int {
+	if r.PendingTie != nil && len(r.PendingTie.Rounds) > 0 {
+		return r.PendingTie.Rounds[0].Scores
+	}
+	return r.TallyVotes().Rounds[0].Scores
 }
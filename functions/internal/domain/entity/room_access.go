@@ -0,0 +1,229 @@
+package entity
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 部屋への参加の制限
+// - パスワード: 設定した部屋は、招待なしで参加するときにパスワードが必要（ハッシュのみ保存する）
+// - 招待: ホストが発行する1回だけ使える期限付きのトークン。パスワード・招待のみの制限より優先する
+// - 招待のみ: 招待がないと参加できない（ロビーの一覧・クイックマッチにも出さない）
+// パスワード・招待・再参加コードが一致しなかった回数を部屋ごとに数え、多すぎる場合はしばらく参加を受け付けない
+
+// パスワードの文字数
+const (
+	MinRoomPasswordLength = 4
+	MaxRoomPasswordLength = 64
+)
+
+// パスワードのハッシュ（PBKDF2-SHA256）の設定
+const (
+	passwordHashScheme     = "pbkdf2-sha256"
+	passwordHashIterations = 100000
+	passwordSaltLength     = 16
+	passwordKeyLength      = 32
+)
+
+// 招待の設定
+const (
+	InviteTokenLength = 16 // 招待トークンの文字数（部屋コードと同じ文字を使う）
+	MaxActiveInvites  = 20 // 部屋ごとの有効な招待の数の上限
+)
+
+// 招待の有効期限
+var (
+	DefaultInviteTTL = time.Hour
+	MinInviteTTL     = time.Minute
+	MaxInviteTTL     = 24 * time.Hour
+)
+
+// 参加に失敗した回数の制限（JoinFailureWindow の間に MaxJoinFailures 回失敗したら、その間は参加を受け付けない）
+const MaxJoinFailures = 5
+
+// JoinFailureWindow は参加に失敗した回数を数える期間
+var JoinFailureWindow = time.Minute
+
+// RoomInvite は部屋への招待（トークン自体は保存しない）
+type RoomInvite struct {
+	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt" firestore:"expiresAt"`
+}
+
+// JoinFailures は部屋への参加に失敗した回数（パスワード・招待・再参加コードの不一致）
+type JoinFailures struct {
+	Count       int       `json:"count" firestore:"count"`
+	WindowStart time.Time `json:"windowStart" firestore:"windowStart"` // 数え始めた日時
+}
+
+// SetPassword は部屋のパスワードを設定する（空なら解除する）
+func (r *Room) SetPassword(password string) error {
+	if password == "" {
+		r.PasswordHash = ""
+		return nil
+	}
+	if n := utf8.RuneCountInString(password); n < MinRoomPasswordLength || n > MaxRoomPasswordLength {
+		return fmt.Errorf("%w: password must be %d-%d characters", ErrInvalidSettings, MinRoomPasswordLength, MaxRoomPasswordLength)
+	}
+
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordHashIterations, passwordKeyLength)
+	if err != nil {
+		return err
+	}
+	r.PasswordHash = strings.Join([]string{
+		passwordHashScheme,
+		strconv.Itoa(passwordHashIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$")
+	return nil
+}
+
+// HasPassword はパスワードが設定されているかを返す
+func (r *Room) HasPassword() bool {
+	return r.PasswordHash != ""
+}
+
+// CheckPassword はパスワードが一致するかを判定する（設定されていなければ常に false）
+func (r *Room) CheckPassword(password string) bool {
+	parts := strings.Split(r.PasswordHash, "$")
+	if len(parts) != 4 || parts[0] != passwordHashScheme || password == "" {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// hashInviteToken は招待トークンのハッシュを返す（Room.Invites のキー）
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(normalizeCode(token)))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateInvite は1回だけ使える招待を発行し、招待トークンを返す（期限切れの招待は削除する）
+// 有効な招待が MaxActiveInvites 個あれば ErrTooManyInvites
+func (r *Room) CreateInvite(now time.Time, ttl time.Duration) (string, *RoomInvite, error) {
+	if ttl < MinInviteTTL || ttl > MaxInviteTTL {
+		return "", nil, fmt.Errorf("%w: invite ttl must be between %s and %s", ErrInvalidSettings, MinInviteTTL, MaxInviteTTL)
+	}
+	r.pruneInvites(now)
+	if len(r.Invites) >= MaxActiveInvites {
+		return "", nil, ErrTooManyInvites
+	}
+
+	token, err := randomCode(InviteTokenLength)
+	if err != nil {
+		return "", nil, err
+	}
+	invite := &RoomInvite{CreatedAt: now, ExpiresAt: now.Add(ttl)}
+	if r.Invites == nil {
+		r.Invites = make(map[string]*RoomInvite)
+	}
+	r.Invites[hashInviteToken(token)] = invite
+	return token, invite, nil
+}
+
+// UseInvite は招待を使う（使った招待は期限にかかわらず削除する）
+// 招待がない・期限切れなら false
+func (r *Room) UseInvite(token string, now time.Time) bool {
+	key := hashInviteToken(token)
+	invite, ok := r.Invites[key]
+	if !ok {
+		return false
+	}
+	delete(r.Invites, key)
+	return now.Before(invite.ExpiresAt)
+}
+
+// pruneInvites は期限切れの招待を削除する
+func (r *Room) pruneInvites(now time.Time) {
+	for key, invite := range r.Invites {
+		if !now.Before(invite.ExpiresAt) {
+			delete(r.Invites, key)
+		}
+	}
+}
+
+// JoinLockedOut は参加に失敗した回数が多すぎて、参加を受け付けない期間かを判定する
+func (r *Room) JoinLockedOut(now time.Time) bool {
+	f := r.JoinFailures
+	return f != nil && f.Count >= MaxJoinFailures && now.Before(f.WindowStart.Add(JoinFailureWindow))
+}
+
+// RecordJoinFailure は参加に失敗したことを記録する（数える期間を過ぎていれば数え直す）
+func (r *Room) RecordJoinFailure(now time.Time) {
+	f := r.JoinFailures
+	if f == nil || !now.Before(f.WindowStart.Add(JoinFailureWindow)) {
+		r.JoinFailures = &JoinFailures{Count: 1, WindowStart: now}
+		return
+	}
+	f.Count++
+}
+
+// CheckJoinAccess は招待なし・招待ありの参加を許可するかを判定する
+// 招待を使った場合は Invites から削除する（呼び出し側で部屋を保存すること）
+// 失敗の記録は部屋ごとのため、有効な招待は参加を受け付けない期間でも使える
+// （誰かがパスワードを間違え続けても、招待されたプレイヤーは参加できる。招待トークンは推測できない長さ）
+// 返すエラーのうち ErrInvalidRoomPassword・ErrInvalidInvite は失敗として数える（IsJoinFailure）
+func (r *Room) CheckJoinAccess(password, inviteToken string, now time.Time) error {
+	lockedOut := r.JoinLockedOut(now)
+	if inviteToken != "" {
+		if r.UseInvite(inviteToken, now) {
+			return nil
+		}
+		if lockedOut {
+			return ErrTooManyJoinAttempts
+		}
+		return ErrInvalidInvite
+	}
+	if lockedOut {
+		return ErrTooManyJoinAttempts
+	}
+	if r.InviteOnly {
+		return ErrInviteRequired
+	}
+	if !r.HasPassword() {
+		return nil
+	}
+	if password == "" {
+		return ErrRoomPasswordRequired
+	}
+	if !r.CheckPassword(password) {
+		return ErrInvalidRoomPassword
+	}
+	return nil
+}
+
+// IsJoinFailure は参加に失敗した回数として数えるエラー（推測による失敗）かを判定する
+func IsJoinFailure(err error) bool {
+	return errors.Is(err, ErrInvalidRoomPassword) || errors.Is(err, ErrInvalidInvite)
+}
@@ -0,0 +1,188 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoom_Password(t *testing.T) {
+	room := NewRoom("host", DefaultRoomSettings())
+	if room.HasPassword() || room.CheckPassword("") {
+		t.Fatal("パスワードのない部屋でパスワードが一致した")
+	}
+
+	if err := room.SetPassword("abc"); !errors.Is(err, ErrInvalidSettings) {
+		t.Errorf("SetPassword(短すぎる) error = %v, want ErrInvalidSettings", err)
+	}
+	if err := room.SetPassword("ひみつの合言葉"); err != nil {
+		t.Fatalf("SetPassword: %v", err)
+	}
+	if strings.Contains(room.PasswordHash, "ひみつ") {
+		t.Error("パスワードがそのまま保存されている")
+	}
+	if !room.CheckPassword("ひみつの合言葉") {
+		t.Error("正しいパスワードが一致しない")
+	}
+	if room.CheckPassword("ひみつの合言") || room.CheckPassword("") {
+		t.Error("違うパスワードが一致した")
+	}
+
+	// 同じパスワードでもソルトが違うためハッシュは変わる
+	other := NewRoom("host", DefaultRoomSettings())
+	_ = other.SetPassword("ひみつの合言葉")
+	if other.PasswordHash == room.PasswordHash {
+		t.Error("ソルトが使われていない")
+	}
+
+	if err := room.SetPassword(""); err != nil || room.HasPassword() {
+		t.Errorf("SetPassword(\"\") = %v, HasPassword = %v, want cleared", err, room.HasPassword())
+	}
+}
+
+func TestRoom_Invite(t *testing.T) {
+	now := time.Now()
+	room := NewRoom("host", DefaultRoomSettings())
+
+	if _, _, err := room.CreateInvite(now, time.Second); !errors.Is(err, ErrInvalidSettings) {
+		t.Errorf("CreateInvite(1s) error = %v, want ErrInvalidSettings", err)
+	}
+
+	token, invite, err := room.CreateInvite(now, time.Hour)
+	if err != nil {
+		t.Fatalf("CreateInvite: %v", err)
+	}
+	if len(token) != InviteTokenLength || !invite.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("token = %q, expiresAt = %v", token, invite.ExpiresAt)
+	}
+	if _, ok := room.Invites[token]; ok {
+		t.Error("招待トークンがそのまま保存されている")
+	}
+
+	if !room.UseInvite(strings.ToLower(token), now) {
+		t.Fatal("発行した招待が使えない")
+	}
+	if room.UseInvite(token, now) {
+		t.Error("同じ招待を2回使えた")
+	}
+
+	expired, _, _ := room.CreateInvite(now, time.Minute)
+	if room.UseInvite(expired, now.Add(time.Minute)) {
+		t.Error("期限切れの招待が使えた")
+	}
+	if len(room.Invites) != 0 {
+		t.Errorf("使った招待が残っている: %v", room.Invites)
+	}
+}
+
+func TestRoom_CreateInvite_Limit(t *testing.T) {
+	now := time.Now()
+	room := NewRoom("host", DefaultRoomSettings())
+	for i := 0; i < MaxActiveInvites; i++ {
+		if _, _, err := room.CreateInvite(now, time.Minute); err != nil {
+			t.Fatalf("CreateInvite(%d): %v", i, err)
+		}
+	}
+	if _, _, err := room.CreateInvite(now, time.Minute); !errors.Is(err, ErrTooManyInvites) {
+		t.Errorf("error = %v, want ErrTooManyInvites", err)
+	}
+	// 期限切れの招待は数えない
+	if _, _, err := room.CreateInvite(now.Add(time.Minute), time.Minute); err != nil {
+		t.Errorf("期限切れの後の CreateInvite: %v", err)
+	}
+}
+
+func TestRoom_CheckJoinAccess(t *testing.T) {
+	now := time.Now()
+	newRoom := func(password string, inviteOnly bool) (*Room, string) {
+		room := NewRoom("host", DefaultRoomSettings())
+		room.InviteOnly = inviteOnly
+		if err := room.SetPassword(password); err != nil {
+			t.Fatalf("SetPassword: %v", err)
+		}
+		token, _, err := room.CreateInvite(now, time.Hour)
+		if err != nil {
+			t.Fatalf("CreateInvite: %v", err)
+		}
+		return room, token
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		inviteOnly bool
+		input      string
+		useInvite  bool
+		badInvite  bool
+		wantErr    error
+	}{
+		{name: "制限なし"},
+		{name: "パスワード一致", password: "secret", input: "secret"},
+		{name: "パスワードなし", password: "secret", wantErr: ErrRoomPasswordRequired},
+		{name: "パスワード不一致", password: "secret", input: "wrong!", wantErr: ErrInvalidRoomPassword},
+		{name: "招待はパスワードより優先", password: "secret", useInvite: true},
+		{name: "招待のみ・招待なし", inviteOnly: true, wantErr: ErrInviteRequired},
+		{name: "招待のみ・パスワードでは入れない", password: "secret", inviteOnly: true, input: "secret", wantErr: ErrInviteRequired},
+		{name: "招待のみ・招待あり", inviteOnly: true, useInvite: true},
+		{name: "不正な招待", inviteOnly: true, badInvite: true, wantErr: ErrInvalidInvite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room, token := newRoom(tt.password, tt.inviteOnly)
+			invite := ""
+			if tt.useInvite {
+				invite = token
+			}
+			if tt.badInvite {
+				invite = "AAAAAAAAAAAAAAAA"
+			}
+			err := room.CheckJoinAccess(tt.input, invite, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := IsJoinFailure(err); got != (errors.Is(err, ErrInvalidRoomPassword) || errors.Is(err, ErrInvalidInvite)) {
+				t.Errorf("IsJoinFailure(%v) = %v", err, got)
+			}
+		})
+	}
+}
+
+func TestRoom_JoinFailures(t *testing.T) {
+	now := time.Now()
+	room := NewRoom("host", DefaultRoomSettings())
+	for i := 0; i < MaxJoinFailures; i++ {
+		if room.JoinLockedOut(now) {
+			t.Fatalf("%d 回の失敗で参加を止めた", i)
+		}
+		room.RecordJoinFailure(now.Add(time.Duration(i) * time.Second))
+	}
+	if !room.JoinLockedOut(now.Add(10 * time.Second)) {
+		t.Fatal("失敗が多すぎても参加を止めない")
+	}
+	if err := room.CheckJoinAccess("", "", now.Add(10*time.Second)); !errors.Is(err, ErrTooManyJoinAttempts) {
+		t.Errorf("CheckJoinAccess error = %v, want ErrTooManyJoinAttempts", err)
+	}
+
+	// 参加を受け付けない期間でも、有効な招待は使える（不正な招待は数えずに止める）
+	token, _, err := room.CreateInvite(now, time.Hour)
+	if err != nil {
+		t.Fatalf("CreateInvite: %v", err)
+	}
+	if err := room.CheckJoinAccess("", "AAAAAAAAAAAAAAAA", now.Add(10*time.Second)); !errors.Is(err, ErrTooManyJoinAttempts) {
+		t.Errorf("CheckJoinAccess(不正な招待) error = %v, want ErrTooManyJoinAttempts", err)
+	}
+	if err := room.CheckJoinAccess("", token, now.Add(10*time.Second)); err != nil {
+		t.Errorf("CheckJoinAccess(招待) error = %v, want nil", err)
+	}
+
+	// 数える期間を過ぎたら数え直す
+	later := now.Add(JoinFailureWindow)
+	if room.JoinLockedOut(later) {
+		t.Error("期間を過ぎても参加を止めている")
+	}
+	room.RecordJoinFailure(later)
+	if room.JoinFailures.Count != 1 {
+		t.Errorf("Count = %d, want 1", room.JoinFailures.Count)
+	}
+}
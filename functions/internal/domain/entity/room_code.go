@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// 部屋コードは口頭で伝えやすい4〜6文字のコードで、部屋IDの代わりに参加・検索に使う
+// 部屋がある間は予約し（room_codes/{code}）、部屋を削除したら解放して再利用する
+
+// codeAlphabet は部屋コード・再参加コードに使う文字（読み間違えやすい 0/O, 1/I/L は使わない）
+const codeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// RoomCodeLengths は部屋コードの文字数（短い順に試し、埋まっていれば長くする）
+var RoomCodeLengths = []int{4, 5, 6}
+
+// NewRoomCode は length 文字の部屋コードを返す
+func NewRoomCode(length int) (string, error) {
+	return randomCode(length)
+}
+
+// NormalizeRoomCode は入力された部屋コードを比較できる形にする（大文字に揃え、空白・ハイフンを除く）
+func NormalizeRoomCode(code string) string {
+	return normalizeCode(code)
+}
+
+// ValidRoomCode は正規化した部屋コードの形式が正しいかを判定する
+func ValidRoomCode(code string) bool {
+	if len(code) < RoomCodeLengths[0] || len(code) > RoomCodeLengths[len(RoomCodeLengths)-1] {
+		return false
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(codeAlphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// randomCode は codeAlphabet から length 文字のコードを返す（推測されないよう crypto/rand を使う）
+func randomCode(length int) (string, error) {
+	// 文字数の倍数未満のバイトだけを使い、文字の出現に偏りが出ないようにする
+	limit := byte(256 / len(codeAlphabet) * len(codeAlphabet))
+	code := make([]byte, 0, length)
+	buf := make([]byte, length)
+	for len(code) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if b < limit && len(code) < length {
+				code = append(code, codeAlphabet[int(b)%len(codeAlphabet)])
+			}
+		}
+	}
+	return string(code), nil
+}
+
+// normalizeCode は入力されたコードを大文字に揃え、空白・ハイフンを除く
+func normalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
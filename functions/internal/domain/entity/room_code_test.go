@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewRoomCode(t *testing.T) {
+	for _, length := range RoomCodeLengths {
+		code, err := NewRoomCode(length)
+		if err != nil {
+			t.Fatalf("NewRoomCode(%d): %v", length, err)
+		}
+		if len(code) != length {
+			t.Fatalf("len(%q) = %d, want %d", code, len(code), length)
+		}
+		if !ValidRoomCode(code) {
+			t.Errorf("ValidRoomCode(%q) = false", code)
+		}
+	}
+}
+
+func TestCodeAlphabet_NoAmbiguousCharacters(t *testing.T) {
+	for _, c := range "01OIL" {
+		if strings.ContainsRune(codeAlphabet, c) {
+			t.Errorf("codeAlphabet contains ambiguous %q", c)
+		}
+	}
+}
+
+func TestValidRoomCode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"ABCD", true},
+		{"abcd", true},
+		{"AB-CD", true},
+		{"A B C D E F", true},
+		{"ABC", false},
+		{"ABCDEFG", false},
+		{"ABC0", false}, // 0 は O と紛らわしいため使わない
+		{"ABCI", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidRoomCode(NormalizeRoomCode(tt.input)); got != tt.want {
+			t.Errorf("ValidRoomCode(NormalizeRoomCode(%q)) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
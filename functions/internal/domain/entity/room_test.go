@@ -0,0 +1,156 @@
+package entity
+
+import "testing"
+
+func TestRoom_CountVotes(t *testing.T) {
+	tests := []struct {
+		name       string
+		votes      map[string]string
+		candidates []string // 選ばれうる政策（1つなら決定的）
+	}{
+		{
+			name:       "最多得票の政策が選ばれる",
+			votes:      map[string]string{"a": "p1", "b": "p1", "c": "p2"},
+			candidates: []string{"p1"},
+		},
+		{
+			name:       "未投票は集計しない",
+			votes:      map[string]string{"a": "p2", "b": "", "c": ""},
+			candidates: []string{"p2"},
+		},
+		{
+			name:       "同数の場合は同数の政策からランダムに選ぶ",
+			votes:      map[string]string{"a": "p1", "b": "p2", "c": "p3", "d": "p3", "e": "p1"},
+			candidates: []string{"p1", "p3"},
+		},
+		{
+			name:       "誰も投票していなければ空文字",
+			votes:      map[string]string{"a": "", "b": ""},
+			candidates: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &Room{Votes: tt.votes}
+			seen := make(map[string]bool)
+			// 同数時の選択はランダムなので複数回集計して候補外が出ないこと、候補が全て出ることを確認する
+			for i := 0; i < 200; i++ {
+				got := room.CountVotes()
+				if !contains(tt.candidates, got) {
+					t.Fatalf("CountVotes() = %q, want one of %v", got, tt.candidates)
+				}
+				seen[got] = true
+			}
+			if len(seen) != len(tt.candidates) {
+				t.Errorf("選ばれた政策 = %v, want 全ての候補 %v", seen, tt.candidates)
+			}
+		})
+	}
+}
+
+func TestRoom_CountVotes_FirstOptionTieBreak(t *testing.T) {
+	room := &Room{
+		Settings:         RoomSettings{TieBreakRule: TieBreakFirstOption},
+		CurrentPolicyIDs: []string{"p1", "p2", "p3"},
+		Votes:            map[string]string{"a": "p3", "b": "p2", "c": "p3", "d": "p2"},
+	}
+	for i := 0; i < 50; i++ {
+		if got := room.CountVotes(); got != "p2" {
+			t.Fatalf("CountVotes() = %q, want 提示順で先の p2", got)
+		}
+	}
+}
+
+func TestRoom_AllPlayersVoted(t *testing.T) {
+	room := &Room{Votes: map[string]string{"a": "p1", "b": ""}}
+	if room.AllPlayersVoted(2) {
+		t.Error("未投票者がいるのに全員投票済みと判定された")
+	}
+	room.Votes["b"] = "p2"
+	if !room.AllPlayersVoted(2) {
+		t.Error("全員投票済みと判定されない")
+	}
+}
+
+func TestRoom_Undecided(t *testing.T) {
+	room := &Room{
+		Votes:    map[string]string{"a": "p1", "b": "p2", "c": ""},
+		LockedIn: map[string]bool{"a": true},
+	}
+	if got := room.Undecided(3); got != 1 {
+		t.Errorf("Undecided = %d, want 未投票の1人", got)
+	}
+
+	// requireLockIn なら確定していないプレイヤーも決めていないと数える
+	room.Settings.RequireLockIn = true
+	if got := room.Undecided(3); got != 2 {
+		t.Errorf("Undecided = %d, want 未投票と未確定の2人", got)
+	}
+	room.ClearBallot("a")
+	if room.IsLockedIn("a") || room.HasVoted("a") {
+		t.Error("票を取り消しても確定が残っている")
+	}
+	if room.ReadyToResolve(3) {
+		t.Error("確定していないのに集計できると判定された")
+	}
+}
+
+func TestRoom_ApplyPolicyEffects_Collapse(t *testing.T) {
+	tests := []struct {
+		name          string
+		effects       map[string]int
+		wantCollapsed bool
+	}{
+		{name: "0より大きければ崩壊しない", effects: map[string]int{"economy": -34}, wantCollapsed: false},
+		{name: "0ちょうどで崩壊する", effects: map[string]int{"welfare": -35}, wantCollapsed: true},
+		{name: "負になると崩壊する", effects: map[string]int{"humanRights": -50}, wantCollapsed: true},
+		{name: "100未満の上昇では崩壊しない", effects: map[string]int{"security": 64}, wantCollapsed: false},
+		{name: "100ちょうどで過熱により崩壊する", effects: map[string]int{"security": 65}, wantCollapsed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := NewRoom("host", DefaultRoomSettings())
+			room.ApplyPolicyEffects(tt.effects)
+			if room.IsCollapsed != tt.wantCollapsed {
+				t.Errorf("IsCollapsed = %v, want %v (cityParams = %+v)", room.IsCollapsed, tt.wantCollapsed, room.CityParams)
+			}
+		})
+	}
+}
+
+func TestRoom_IsGameOverAndEndReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		turn       int
+		collapsed  bool
+		noPolicies bool // 山札も捨て札も空
+		wantOver   bool
+		wantReason GameEndReason
+	}{
+		{name: "途中のターン", turn: 3, wantOver: false},
+		{name: "最終ターン", turn: 10, wantOver: true, wantReason: GameEndReasonMaxTurns},
+		{name: "崩壊", turn: 3, collapsed: true, wantOver: true, wantReason: GameEndReasonCollapsed},
+		{name: "最終ターンでの崩壊は崩壊が優先", turn: 10, collapsed: true, wantOver: true, wantReason: GameEndReasonCollapsed},
+		{name: "山札も捨て札も尽きた", turn: 3, noPolicies: true, wantOver: true, wantReason: GameEndReasonDeckExhausted},
+		{name: "最終ターンで尽きた場合は最終ターンまで完了", turn: 10, noPolicies: true, wantOver: true, wantReason: GameEndReasonMaxTurns},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := NewRoom("host", DefaultRoomSettings())
+			room.Turn = tt.turn
+			room.IsCollapsed = tt.collapsed
+			if !tt.noPolicies {
+				room.DiscardIDs = []string{"policy_001"}
+			}
+			if got := room.IsGameOver(); got != tt.wantOver {
+				t.Fatalf("IsGameOver() = %v, want %v", got, tt.wantOver)
+			}
+			if tt.wantOver && room.EndReason() != tt.wantReason {
+				t.Errorf("EndReason() = %s, want %s", room.EndReason(), tt.wantReason)
+			}
+		})
+	}
+}
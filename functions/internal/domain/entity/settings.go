@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TieBreakRule は最多得票が同数だった場合の決め方を表す
+type TieBreakRule string
+
+const (
+	TieBreakRandom          TieBreakRule = "RANDOM"           // 同数の政策から部屋の乱数でランダムに選ぶ
+	TieBreakFirstOption     TieBreakRule = "FIRST_OPTION"     // 同数の政策のうち提示順が先のものを選ぶ
+	TieBreakHostDecides     TieBreakRule = "HOST_DECIDES"     // ホストが同数の政策から選ぶ
+	TieBreakLowestMagnitude TieBreakRule = "LOWEST_MAGNITUDE" // 効果の大きさ（各分野の変化量の絶対値の平均）が最も小さい政策を選ぶ
+	TieBreakRevote          TieBreakRule = "REVOTE"           // 同数の政策だけで再投票する（再投票でも同数ならランダム）
+)
+
+// TieBreakRules は指定できる同数時の決め方の一覧
+var TieBreakRules = []TieBreakRule{TieBreakRandom, TieBreakFirstOption, TieBreakHostDecides, TieBreakLowestMagnitude, TieBreakRevote}
+
+// 部屋の設定で指定できる範囲
+const (
+	MinMaxTurns           = 1
+	MaxMaxTurns           = 30
+	MinMaxPlayers         = 2
+	MaxMaxPlayers         = 6 // 思想マスターの数（プレイヤーごとに異なる思想を割り当てるため）
+	MinOptionsPerTurn     = 2
+	MaxOptionsPerTurn     = 5
+	MinPetitionsPerPlayer = 0 // 0 なら陳情なし
+	MaxPetitionsPerPlayer = 3
+	MinPetitionWindow     = 1
+	MaxPetitionWindow     = 15
+	MinVotingTimeLimit    = 10 // 秒。0 なら制限時間なし
+	MaxVotingTimeLimit    = 600
+	MinResultTimeLimit    = 5 // 秒。0 なら制限時間なし（クライアントが /next を呼ぶまで待つ）
+	MaxResultTimeLimit    = 120
+)
+
+// RoomSettings はホストが変更できる部屋の設定を表す
+// パス: rooms/{roomId} の settings フィールド
+type RoomSettings struct {
+	MaxTurns           int               `json:"maxTurns" firestore:"maxTurns"`                     // ターン数
+	MaxPlayers         int               `json:"maxPlayers" firestore:"maxPlayers"`                 // 参加できる最大人数
+	OptionsPerTurn     int               `json:"optionsPerTurn" firestore:"optionsPerTurn"`         // 1ターンに提示する政策の数
+	InitialCityParams  CityParams        `json:"initialCityParams" firestore:"initialCityParams"`   // 開始時の街パラメータ
+	CityRules          CityRules         `json:"cityRules" firestore:"cityRules"`                   // 分野ごとの下限・上限とその扱い
+	PetitionsPerPlayer int               `json:"petitionsPerPlayer" firestore:"petitionsPerPlayer"` // 1人が陳情できる回数
+	VotingMethod       VotingMethod      `json:"votingMethod" firestore:"votingMethod"`             // 投票の集計方式
+	TieBreakRule       TieBreakRule      `json:"tieBreakRule" firestore:"tieBreakRule"`             // 同数時の決め方
+	RequireLockIn      bool              `json:"requireLockIn" firestore:"requireLockIn"`           // 全員が投票を確定するまで集計しないか
+	VotingTimeLimit    int               `json:"votingTimeLimit" firestore:"votingTimeLimit"`       // 投票の制限時間（秒、0 なら制限なし）
+	ResultTimeLimit    int               `json:"resultTimeLimit" firestore:"resultTimeLimit"`       // 結果発表から次のターンに自動で進むまでの時間（秒、0 なら自動で進まない）
+	TimeoutVote        TimeoutVote       `json:"timeoutVote" firestore:"timeoutVote"`               // 制限時間までに投票しなかったプレイヤーの扱い
+	RecycleRejected    bool              `json:"recycleRejected" firestore:"recycleRejected"`       // 可決されなかった政策を後で山札に戻すか
+	PetitionPlacement  PetitionPlacement `json:"petitionPlacement" firestore:"petitionPlacement"`   // 承認された陳情の政策を入れる位置
+	PetitionWindow     int               `json:"petitionWindow" firestore:"petitionWindow"`         // RANDOM_WITHIN で入れる範囲（山札の先頭から何枚以内か）
+}
+
+// DefaultRoomSettings はデフォルトの部屋設定を返す
+// 10ターン・4人・3択・各35・陳情1回・単純多数決・同数はランダム・確定なしで集計・制限時間なし（時間切れは棄権）・
+// 否決された政策は再利用・陳情は次の手札に入れる
+func DefaultRoomSettings() RoomSettings {
+	return RoomSettings{
+		MaxTurns:           10,
+		MaxPlayers:         4,
+		OptionsPerTurn:     3,
+		InitialCityParams:  NewCityParams(),
+		CityRules:          DefaultCityRules(),
+		PetitionsPerPlayer: 1,
+		VotingMethod:       VotingMethodPlurality,
+		TieBreakRule:       TieBreakRandom,
+		RequireLockIn:      false,
+		VotingTimeLimit:    0,
+		ResultTimeLimit:    0,
+		TimeoutVote:        TimeoutVoteAbstain,
+		RecycleRejected:    true,
+		PetitionPlacement:  PetitionPlacementNextHand,
+		PetitionWindow:     6,
+	}
+}
+
+// Validate は設定が指定できる範囲に収まっているかを確認する
+func (s RoomSettings) Validate() error {
+	var problems []string
+	checkRange := func(name string, v, min, max int) {
+		if v < min || v > max {
+			problems = append(problems, fmt.Sprintf("%s must be between %d and %d", name, min, max))
+		}
+	}
+
+	checkRange("maxTurns", s.MaxTurns, MinMaxTurns, MaxMaxTurns)
+	checkRange("maxPlayers", s.MaxPlayers, MinMaxPlayers, MaxMaxPlayers)
+	checkRange("optionsPerTurn", s.OptionsPerTurn, MinOptionsPerTurn, MaxOptionsPerTurn)
+	checkRange("petitionsPerPlayer", s.PetitionsPerPlayer, MinPetitionsPerPlayer, MaxPetitionsPerPlayer)
+	checkRange("petitionWindow", s.PetitionWindow, MinPetitionWindow, MaxPetitionWindow)
+	checkTimeLimit := func(name string, v, min, max int) {
+		if v != 0 && (v < min || v > max) {
+			problems = append(problems, fmt.Sprintf("%s must be 0 (no limit) or between %d and %d", name, min, max))
+		}
+	}
+	checkTimeLimit("votingTimeLimit", s.VotingTimeLimit, MinVotingTimeLimit, MaxVotingTimeLimit)
+	checkTimeLimit("resultTimeLimit", s.ResultTimeLimit, MinResultTimeLimit, MaxResultTimeLimit)
+	problems = append(problems, s.CityRules.validate(s.InitialCityParams)...)
+
+	if !s.VotingMethod.valid() {
+		problems = append(problems, fmt.Sprintf("votingMethod must be %s, %s, %s or %s", VotingMethodPlurality, VotingMethodApproval, VotingMethodInstantRunoff, VotingMethodBorda))
+	}
+
+	if !contains(tieBreakRuleNames(), string(s.TieBreakRule)) {
+		problems = append(problems, fmt.Sprintf("tieBreakRule must be one of %s", strings.Join(tieBreakRuleNames(), ", ")))
+	}
+
+	switch s.TimeoutVote {
+	case TimeoutVoteAbstain, TimeoutVoteRandom:
+	default:
+		problems = append(problems, fmt.Sprintf("timeoutVote must be %s or %s", TimeoutVoteAbstain, TimeoutVoteRandom))
+	}
+
+	switch s.PetitionPlacement {
+	case PetitionPlacementNextHand, PetitionPlacementRandomWithin, PetitionPlacementReplaceCurrent:
+	default:
+		problems = append(problems, fmt.Sprintf("petitionPlacement must be %s, %s or %s", PetitionPlacementNextHand, PetitionPlacementRandomWithin, PetitionPlacementReplaceCurrent))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidSettings, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func tieBreakRuleNames() []string {
+	names := make([]string, 0, len(TieBreakRules))
+	for _, rule := range TieBreakRules {
+		names = append(names, string(rule))
+	}
+	return names
+}
@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoomSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(s *RoomSettings)
+		wantErr bool
+	}{
+		{name: "デフォルト値", mutate: func(s *RoomSettings) {}},
+		{name: "陳情なし", mutate: func(s *RoomSettings) { s.PetitionsPerPlayer = 0 }},
+		{name: "同数は提示順", mutate: func(s *RoomSettings) { s.TieBreakRule = TieBreakFirstOption }},
+		{name: "ターン数が0", mutate: func(s *RoomSettings) { s.MaxTurns = 0 }, wantErr: true},
+		{name: "最大人数が思想の数を超える", mutate: func(s *RoomSettings) { s.MaxPlayers = MaxMaxPlayers + 1 }, wantErr: true},
+		{name: "選択肢が1つ", mutate: func(s *RoomSettings) { s.OptionsPerTurn = 1 }, wantErr: true},
+		{name: "初期値が崩壊状態", mutate: func(s *RoomSettings) { s.InitialCityParams.Security = 0 }, wantErr: true},
+		{name: "上限を広げれば初期値も上げられる", mutate: func(s *RoomSettings) {
+			s.CityRules["economy"] = ParamRule{Floor: 0, Ceiling: 150, FloorMode: BoundModeCollapse, CeilingMode: BoundModeClamp}
+			s.InitialCityParams.Economy = 120
+		}},
+		{name: "初期値が過熱状態", mutate: func(s *RoomSettings) { s.InitialCityParams.Economy = 100 }, wantErr: true},
+		{name: "下限が上限以上", mutate: func(s *RoomSettings) {
+			s.CityRules["welfare"] = ParamRule{Floor: 50, Ceiling: 50, FloorMode: BoundModeClamp, CeilingMode: BoundModeClamp}
+		}, wantErr: true},
+		{name: "不明な分野のルール", mutate: func(s *RoomSettings) { s.CityRules["happiness"] = DefaultParamRule() }, wantErr: true},
+		{name: "不明な境界の扱い", mutate: func(s *RoomSettings) {
+			s.CityRules["security"] = ParamRule{Floor: 0, Ceiling: 100, FloorMode: "BOUNCE", CeilingMode: BoundModeClamp}
+		}, wantErr: true},
+		{name: "同数はホストが選ぶ", mutate: func(s *RoomSettings) { s.TieBreakRule = TieBreakHostDecides }},
+		{name: "同数は再投票", mutate: func(s *RoomSettings) { s.TieBreakRule = TieBreakRevote }},
+		{name: "不明な同数ルール", mutate: func(s *RoomSettings) { s.TieBreakRule = "COIN_TOSS" }, wantErr: true},
+		{name: "ボルダ得点で集計", mutate: func(s *RoomSettings) { s.VotingMethod = VotingMethodBorda }},
+		{name: "不明な集計方式", mutate: func(s *RoomSettings) { s.VotingMethod = "RANGE" }, wantErr: true},
+		{name: "投票の制限時間あり", mutate: func(s *RoomSettings) { s.VotingTimeLimit = 60; s.TimeoutVote = TimeoutVoteRandom }},
+		{name: "投票の制限時間が短すぎる", mutate: func(s *RoomSettings) { s.VotingTimeLimit = MinVotingTimeLimit - 1 }, wantErr: true},
+		{name: "結果発表の制限時間が長すぎる", mutate: func(s *RoomSettings) { s.ResultTimeLimit = MaxResultTimeLimit + 1 }, wantErr: true},
+		{name: "不明な時間切れの扱い", mutate: func(s *RoomSettings) { s.TimeoutVote = "KICK" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultRoomSettings()
+			tt.mutate(&settings)
+			err := settings.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSettings) {
+				t.Errorf("error = %v, want ErrInvalidSettings", err)
+			}
+		})
+	}
+}
+
+func TestRoom_UpdateSettings(t *testing.T) {
+	room := NewRoom("host", DefaultRoomSettings())
+
+	settings := DefaultRoomSettings()
+	settings.MaxTurns = 5
+	settings.InitialCityParams = CityParams{Economy: 50, Welfare: 50, Education: 50, Environment: 50, Security: 50, HumanRights: 50}
+	if err := room.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if room.MaxTurns != 5 || room.Settings.MaxTurns != 5 {
+		t.Errorf("MaxTurns = %d / %d, want 5", room.MaxTurns, room.Settings.MaxTurns)
+	}
+	if room.CityParams != settings.InitialCityParams {
+		t.Errorf("CityParams = %+v, want 初期値に置き換わる", room.CityParams)
+	}
+
+	room.Start()
+	if err := room.UpdateSettings(DefaultRoomSettings()); !errors.Is(err, ErrInvalidPhase) {
+		t.Errorf("ゲーム開始後の変更: error = %v, want ErrInvalidPhase", err)
+	}
+}
+
+func TestPlayer_Petitions(t *testing.T) {
+	player := NewPlayer("p", false, nil)
+	const limit = 2
+
+	for i := 0; i < limit; i++ {
+		if !player.CanPetition(limit) {
+			t.Fatalf("%d回目の陳情ができない", i+1)
+		}
+		player.UsePetition(limit)
+	}
+	if player.CanPetition(limit) || !player.IsPetitionUsed || player.RemainingPetitions(limit) != 0 {
+		t.Errorf("上限に達した後: CanPetition = %v, IsPetitionUsed = %v, remaining = %d",
+			player.CanPetition(limit), player.IsPetitionUsed, player.RemainingPetitions(limit))
+	}
+
+	if NewPlayer("q", false, nil).CanPetition(0) {
+		t.Error("陳情回数0でも陳情できる")
+	}
+}
@@ -0,0 +1,66 @@
+package entity
+
+import "math"
+
+// PendingTie は最も得点の高い政策が同数で、決着を待っている状態を表す
+// tieBreakRule が HOST_DECIDES（ホストが選ぶまで待つ）か REVOTE（同数の政策だけで再投票する）のときだけ設定する
+// パス: rooms/{roomId} の pendingTie フィールド
+type PendingTie struct {
+	PolicyIDs []string     `json:"policyIds" firestore:"policyIds"` // 同数の政策（提示順）
+	Rule      TieBreakRule `json:"rule" firestore:"rule"`           // 決着のつけ方
+	Rounds    []VoteRound  `json:"rounds" firestore:"rounds"`       // 同数になった投票の集計
+}
+
+// StartTieBreak は同数の政策について決着待ちにする
+// REVOTE の場合は全員を未投票に戻す（プレイヤーの currentVote は呼び出し元でリセットする）
+func (r *Room) StartTieBreak(tally VoteTally) {
+	r.PendingTie = &PendingTie{
+		PolicyIDs: tally.Winners,
+		Rule:      r.Settings.TieBreakRule,
+		Rounds:    tally.Rounds,
+	}
+	if r.PendingTie.Rule == TieBreakRevote {
+		r.ResetVotes()
+	}
+}
+
+// AwaitingHost はホストが同数の政策から選ぶのを待っているかを判定する
+func (r *Room) AwaitingHost() bool {
+	return r.PendingTie != nil && r.PendingTie.Rule == TieBreakHostDecides
+}
+
+// Revoting は同数の政策だけで再投票しているかを判定する
+func (r *Room) Revoting() bool {
+	return r.PendingTie != nil && r.PendingTie.Rule == TieBreakRevote
+}
+
+// votableOptions は投票できる政策を返す（再投票中は同数の政策だけ）
+func (r *Room) votableOptions() []string {
+	if r.Revoting() {
+		return r.PendingTie.PolicyIDs
+	}
+	return r.CurrentPolicyIDs
+}
+
+// EffectMagnitude は政策の効果の大きさ（各分野の変化量の絶対値の平均）を返す
+func (p *MasterPolicy) EffectMagnitude() float64 {
+	total := 0.0
+	for _, key := range PolicyEffectKeys {
+		total += math.Abs(float64(p.Effects[key]))
+	}
+	return total / float64(len(PolicyEffectKeys))
+}
+
+// LeastImpactful は効果の大きさが最も小さい政策のIDを返す（同じ大きさなら先にあるもの）
+func LeastImpactful(policies []*MasterPolicy) string {
+	var best *MasterPolicy
+	for _, policy := range policies {
+		if best == nil || policy.EffectMagnitude() < best.EffectMagnitude() {
+			best = policy
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.PolicyID
+}
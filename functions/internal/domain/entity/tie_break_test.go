@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoom_Rand_ReproducibleBySeed(t *testing.T) {
+	ids := []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8"}
+	a := &Room{Seed: 42}
+	b := &Room{Seed: 42}
+
+	first := ShuffleDeck(a.Rand(), ids)
+	if got := ShuffleDeck(b.Rand(), ids); !reflect.DeepEqual(got, first) {
+		t.Errorf("同じ seed で並びが異なる: %v, %v", first, got)
+	}
+	if a.RandCount != 1 {
+		t.Errorf("randCount = %d, want 1", a.RandCount)
+	}
+	// 次の乱数は前回と異なる並びになる
+	if got := ShuffleDeck(a.Rand(), ids); reflect.DeepEqual(got, first) {
+		t.Errorf("2回目の並びが1回目と同じ: %v", got)
+	}
+	if got := ShuffleDeck((&Room{Seed: 7}).Rand(), ids); reflect.DeepEqual(got, first) {
+		t.Errorf("異なる seed で並びが同じ: %v", got)
+	}
+}
+
+func TestRoom_StartTieBreak(t *testing.T) {
+	tally := VoteTally{
+		Rounds:  []VoteRound{{Scores: map[string]int{"a": 1, "b": 1, "c": 0}}},
+		Winners: []string{"a", "b"},
+	}
+
+	t.Run("REVOTE は同数の政策だけに投票し直す", func(t *testing.T) {
+		room := &Room{
+			Settings:         RoomSettings{TieBreakRule: TieBreakRevote},
+			CurrentPolicyIDs: []string{"a", "b", "c"},
+			Votes:            map[string]string{"u1": "a", "u2": "b"},
+		}
+		room.StartTieBreak(tally)
+
+		if !room.Revoting() || room.AwaitingHost() {
+			t.Fatalf("pendingTie = %+v, want 再投票中", room.PendingTie)
+		}
+		if room.Votes["u1"] != "" || room.Votes["u2"] != "" {
+			t.Errorf("votes = %v, want リセット", room.Votes)
+		}
+		if err := room.ValidateBallot([]string{"c"}); err != ErrInvalidPolicy {
+			t.Errorf("同数でない政策への投票 = %v, want ErrInvalidPolicy", err)
+		}
+		if err := room.ValidateBallot([]string{"b"}); err != nil {
+			t.Errorf("同数の政策への投票 = %v", err)
+		}
+		// 墓場に記録する得点は同数になった最初の投票のもの
+		if got := room.voteCount()["a"]; got != 1 {
+			t.Errorf("voteCount[a] = %d, want 1", got)
+		}
+	})
+
+	t.Run("HOST_DECIDES は投票を残したままホストを待つ", func(t *testing.T) {
+		room := &Room{
+			Settings:         RoomSettings{TieBreakRule: TieBreakHostDecides},
+			CurrentPolicyIDs: []string{"a", "b", "c"},
+			Votes:            map[string]string{"u1": "a", "u2": "b"},
+		}
+		room.StartTieBreak(tally)
+
+		if !room.AwaitingHost() || room.Revoting() {
+			t.Fatalf("pendingTie = %+v, want ホスト待ち", room.PendingTie)
+		}
+		if room.Votes["u1"] != "a" {
+			t.Errorf("votes = %v, want そのまま", room.Votes)
+		}
+	})
+}
+
+func TestLeastImpactful(t *testing.T) {
+	policies := []*MasterPolicy{
+		{PolicyID: "big", Effects: map[string]int{"economy": 20, "welfare": -20}},
+		{PolicyID: "small", Effects: map[string]int{"economy": -5, "security": 5}},
+		{PolicyID: "same", Effects: map[string]int{"education": 10}},
+	}
+	if got := LeastImpactful(policies); got != "small" {
+		t.Errorf("LeastImpactful() = %q, want small", got)
+	}
+	// 同じ大きさなら先にあるもの
+	if got := LeastImpactful(policies[1:]); got != "small" {
+		t.Errorf("LeastImpactful() = %q, want 先にある small", got)
+	}
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// 制限時間（settings.votingTimeLimit・settings.resultTimeLimit）が設定されている場合、
+// VOTING・RESULT に入るたびに deadline を設定し、期限を過ぎたらサーバーが集計・次のターンへの移行を行う
+// （1人が離席しても進行が止まらないようにする）
+
+// TimeoutVote は制限時間までに投票しなかったプレイヤーの扱いを表す
+type TimeoutVote string
+
+const (
+	TimeoutVoteAbstain TimeoutVote = "ABSTAIN" // 棄権（票に数えない）
+	TimeoutVoteRandom  TimeoutVote = "RANDOM"  // 投票できる政策にランダムに1票を投じたとみなす
+)
+
+// ScheduleDeadline は現在のフェーズの期限を設定する
+// VOTING なら votingTimeLimit、RESULT なら resultTimeLimit 後。制限時間がない・それ以外のフェーズなら期限なし
+func (r *Room) ScheduleDeadline(now time.Time) {
+	var limit int
+	switch r.Status {
+	case RoomStatusVoting:
+		limit = r.Settings.VotingTimeLimit
+	case RoomStatusResult:
+		limit = r.Settings.ResultTimeLimit
+	}
+	if limit <= 0 {
+		r.Deadline = nil
+		return
+	}
+	deadline := now.Add(time.Duration(limit) * time.Second)
+	r.Deadline = &deadline
+}
+
+// DeadlinePassed は現在のフェーズの期限を過ぎたかを判定する
+func (r *Room) DeadlinePassed(now time.Time) bool {
+	return r.Deadline != nil && !now.Before(*r.Deadline)
+}
+
+// FillMissingVotes は未投票のプレイヤーが投票できる政策にランダムに投票したことにする（timeoutVote が RANDOM の場合のみ）
+// 投票を補ったプレイヤーのIDをID順で返す
+func (r *Room) FillMissingVotes(userIDs []string) []string {
+	options := r.votableOptions()
+	if r.Settings.TimeoutVote != TimeoutVoteRandom || len(options) == 0 {
+		return nil
+	}
+
+	sorted := append([]string(nil), userIDs...)
+	sort.Strings(sorted)
+	rng := r.Rand()
+	var filled []string
+	for _, userID := range sorted {
+		if r.HasVoted(userID) {
+			continue
+		}
+		r.CastBallot(userID, []string{options[rng.Intn(len(options))]})
+		filled = append(filled, userID)
+	}
+	return filled
+}
+
+// TallyOnTimeout は制限時間切れの集計を行う
+// 誰も投票していなければ、投票できる政策全てを同数として扱う（tieBreakRule で決める）
+func (r *Room) TallyOnTimeout() VoteTally {
+	tally := r.TallyVotes()
+	if len(tally.Winners) == 0 {
+		tally.Winners = append([]string(nil), r.votableOptions()...)
+	}
+	return tally
+}
@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoom_ScheduleDeadline(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status RoomStatus
+		voting int
+		result int
+		want   time.Duration // 0 なら期限なし
+	}{
+		{name: "VOTING は votingTimeLimit 後", status: RoomStatusVoting, voting: 60, result: 10, want: 60 * time.Second},
+		{name: "RESULT は resultTimeLimit 後", status: RoomStatusResult, voting: 60, result: 10, want: 10 * time.Second},
+		{name: "制限時間なし", status: RoomStatusVoting},
+		{name: "LOBBY には期限がない", status: RoomStatusLobby, voting: 60, result: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &Room{Status: tt.status, Settings: RoomSettings{VotingTimeLimit: tt.voting, ResultTimeLimit: tt.result}}
+			room.ScheduleDeadline(now)
+
+			if tt.want == 0 {
+				if room.Deadline != nil {
+					t.Errorf("deadline = %v, want nil", room.Deadline)
+				}
+				return
+			}
+			if room.Deadline == nil || !room.Deadline.Equal(now.Add(tt.want)) {
+				t.Fatalf("deadline = %v, want %v", room.Deadline, now.Add(tt.want))
+			}
+			if room.DeadlinePassed(now.Add(tt.want - time.Second)) {
+				t.Error("期限前なのに期限切れと判定された")
+			}
+			if !room.DeadlinePassed(now.Add(tt.want)) {
+				t.Error("期限ちょうどで期限切れと判定されない")
+			}
+		})
+	}
+}
+
+func TestRoom_FillMissingVotes(t *testing.T) {
+	newRoom := func(mode TimeoutVote) *Room {
+		return &Room{
+			Seed:             1,
+			Settings:         RoomSettings{VotingMethod: VotingMethodPlurality, TimeoutVote: mode},
+			CurrentPolicyIDs: []string{"a", "b", "c"},
+			Votes:            map[string]string{"u1": "a", "u2": "", "u3": ""},
+		}
+	}
+
+	t.Run("ABSTAIN なら補わない", func(t *testing.T) {
+		room := newRoom(TimeoutVoteAbstain)
+		if filled := room.FillMissingVotes([]string{"u1", "u2", "u3"}); len(filled) != 0 {
+			t.Errorf("filled = %v, want なし", filled)
+		}
+		if room.HasVoted("u2") {
+			t.Error("未投票のプレイヤーに投票が入った")
+		}
+	})
+
+	t.Run("RANDOM なら未投票のプレイヤーだけ提示中の政策に投票する", func(t *testing.T) {
+		room := newRoom(TimeoutVoteRandom)
+		filled := room.FillMissingVotes([]string{"u3", "u2", "u1"})
+		if !reflect.DeepEqual(filled, []string{"u2", "u3"}) {
+			t.Fatalf("filled = %v, want [u2 u3]", filled)
+		}
+		if room.Votes["u1"] != "a" {
+			t.Errorf("投票済みの票が変わった: %s", room.Votes["u1"])
+		}
+		for _, userID := range filled {
+			if !contains(room.CurrentPolicyIDs, room.Votes[userID]) {
+				t.Errorf("votes[%s] = %q, want 提示中の政策", userID, room.Votes[userID])
+			}
+		}
+		// 同じ seed なら同じ投票になる
+		again := newRoom(TimeoutVoteRandom)
+		again.FillMissingVotes([]string{"u1", "u2", "u3"})
+		if !reflect.DeepEqual(again.Votes, room.Votes) {
+			t.Errorf("同じ seed で投票が異なる: %v, %v", room.Votes, again.Votes)
+		}
+	})
+}
+
+func TestRoom_TallyOnTimeout_NoVotes(t *testing.T) {
+	room := &Room{
+		Settings:         RoomSettings{VotingMethod: VotingMethodPlurality},
+		CurrentPolicyIDs: []string{"a", "b"},
+		Votes:            map[string]string{"u1": "", "u2": ""},
+	}
+	if got := room.TallyOnTimeout().Winners; !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("winners = %v, want 全ての政策が同数", got)
+	}
+}
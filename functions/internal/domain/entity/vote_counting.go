@@ -0,0 +1,240 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+)
+
+// VotingMethod は投票の集計方式を表す
+type VotingMethod string
+
+const (
+	VotingMethodPlurality     VotingMethod = "PLURALITY"      // 1人1票、最多得票の政策を選ぶ
+	VotingMethodApproval      VotingMethod = "APPROVAL"       // 賛成する政策をいくつでも選び、賛成の最も多い政策を選ぶ
+	VotingMethodInstantRunoff VotingMethod = "INSTANT_RUNOFF" // 順位を付けて投票し、過半数に達するまで最下位を落として票を移す
+	VotingMethodBorda         VotingMethod = "BORDA"          // 順位に応じた点数（n択なら1位 n-1点 … n位 0点）の合計で選ぶ
+)
+
+// VotingMethods は指定できる集計方式の一覧
+var VotingMethods = []VotingMethod{VotingMethodPlurality, VotingMethodApproval, VotingMethodInstantRunoff, VotingMethodBorda}
+
+func (m VotingMethod) valid() bool {
+	for _, method := range VotingMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Ranked は票に順位の意味があるか（INSTANT_RUNOFF, BORDA）を返す
+func (m VotingMethod) Ranked() bool {
+	return m == VotingMethodInstantRunoff || m == VotingMethodBorda
+}
+
+// validateBallot は票の形が集計方式に合っているかを確認する
+// PLURALITY（未設定を含む）は1つだけ、それ以外は1つ以上・選択肢の数以下（同じ政策を重ねて選ぶことはできない）
+func (m VotingMethod) validateBallot(ballot []string, optionCount int) error {
+	if len(ballot) == 0 {
+		return fmt.Errorf("%w: at least one policy is required", ErrInvalidBallot)
+	}
+	if m != VotingMethodApproval && !m.Ranked() && len(ballot) > 1 {
+		return fmt.Errorf("%w: %s accepts only one policy", ErrInvalidBallot, m)
+	}
+	if len(ballot) > optionCount {
+		return fmt.Errorf("%w: at most %d policies can be selected", ErrInvalidBallot, optionCount)
+	}
+	seen := make(map[string]bool, len(ballot))
+	for _, policyID := range ballot {
+		if seen[policyID] {
+			return fmt.Errorf("%w: %s is selected more than once", ErrInvalidBallot, policyID)
+		}
+		seen[policyID] = true
+	}
+	return nil
+}
+
+// VoteRound は集計の1ラウンドを表す（INSTANT_RUNOFF 以外は1ラウンドのみ）
+type VoteRound struct {
+	Scores     map[string]int `json:"scores" firestore:"scores"`                             // 政策ごとの得点（得票数・賛成数・ボルダ点）
+	Eliminated []string       `json:"eliminated,omitempty" firestore:"eliminated,omitempty"` // このラウンドで脱落した政策
+}
+
+// VoteTally は集計結果を表す
+type VoteTally struct {
+	Rounds  []VoteRound
+	Winners []string // 最終ラウンドで最も得点の高い政策（同数なら複数、提示順）。誰も投票していなければ空
+}
+
+// VoteCounter は票を集計するドメインインターフェース
+// ballots は1人1票の並び（各票は政策IDを希望順に並べたもの）、options は提示中の政策（提示順）
+type VoteCounter interface {
+	Count(ballots [][]string, options []string) VoteTally
+}
+
+// NewVoteCounter は集計方式に対応する VoteCounter を返す（未設定なら PLURALITY）
+func NewVoteCounter(method VotingMethod) VoteCounter {
+	switch method {
+	case VotingMethodApproval:
+		return approvalCounter{}
+	case VotingMethodInstantRunoff:
+		return instantRunoffCounter{}
+	case VotingMethodBorda:
+		return bordaCounter{}
+	default:
+		return pluralityCounter{}
+	}
+}
+
+// pluralityCounter は各票の第1希望だけを数える
+type pluralityCounter struct{}
+
+func (pluralityCounter) Count(ballots [][]string, options []string) VoteTally {
+	candidates := tallyCandidates(ballots, options)
+	scores := zeroScores(candidates)
+	for _, ballot := range ballots {
+		scores[ballot[0]]++
+	}
+	return singleRound(scores, candidates, len(ballots) > 0)
+}
+
+// approvalCounter は票に含まれる政策それぞれに1点を加える
+type approvalCounter struct{}
+
+func (approvalCounter) Count(ballots [][]string, options []string) VoteTally {
+	candidates := tallyCandidates(ballots, options)
+	scores := zeroScores(candidates)
+	for _, ballot := range ballots {
+		for _, policyID := range ballot {
+			scores[policyID]++
+		}
+	}
+	return singleRound(scores, candidates, len(ballots) > 0)
+}
+
+// bordaCounter は n 択の k 位（0始まり）に n-1-k 点を加える（順位を付けなかった政策は0点）
+type bordaCounter struct{}
+
+func (bordaCounter) Count(ballots [][]string, options []string) VoteTally {
+	candidates := tallyCandidates(ballots, options)
+	scores := zeroScores(candidates)
+	for _, ballot := range ballots {
+		for rank, policyID := range ballot {
+			scores[policyID] += len(candidates) - 1 - rank
+		}
+	}
+	return singleRound(scores, candidates, len(ballots) > 0)
+}
+
+// instantRunoffCounter は残っている政策のうち各票の最上位を数え、
+// 有効票（残っている政策を1つでも含む票）の過半数に達した政策があれば選ぶ
+// 達しなければ最下位（同数なら全て）を脱落させて次のラウンドへ進む
+// 残り全てが同数で脱落させられない場合は、それらを同数の勝者とする
+type instantRunoffCounter struct{}
+
+func (instantRunoffCounter) Count(ballots [][]string, options []string) VoteTally {
+	candidates := tallyCandidates(ballots, options)
+	if len(ballots) == 0 {
+		return VoteTally{Rounds: []VoteRound{{Scores: zeroScores(candidates)}}}
+	}
+
+	remaining := candidates
+	var tally VoteTally
+	for {
+		scores := zeroScores(remaining)
+		active := 0
+		for _, ballot := range ballots {
+			for _, policyID := range ballot {
+				if _, ok := scores[policyID]; ok {
+					scores[policyID]++
+					active++
+					break
+				}
+			}
+		}
+
+		top := topScorers(scores, remaining)
+		if active == 0 || scores[top[0]]*2 > active || len(top) == len(remaining) {
+			tally.Rounds = append(tally.Rounds, VoteRound{Scores: scores})
+			tally.Winners = top
+			return tally
+		}
+
+		lowest := minScore(scores)
+		var eliminated, next []string
+		for _, policyID := range remaining {
+			if scores[policyID] == lowest {
+				eliminated = append(eliminated, policyID)
+			} else {
+				next = append(next, policyID)
+			}
+		}
+		tally.Rounds = append(tally.Rounds, VoteRound{Scores: scores, Eliminated: eliminated})
+		remaining = next
+	}
+}
+
+// tallyCandidates は集計対象の政策を提示順で返す
+// 提示中でない政策に投票されていた場合（入れ替え直後など）は、ID順で後ろに加える
+func tallyCandidates(ballots [][]string, options []string) []string {
+	candidates := append([]string(nil), options...)
+	known := make(map[string]bool, len(options))
+	for _, policyID := range options {
+		known[policyID] = true
+	}
+	var extra []string
+	for _, ballot := range ballots {
+		for _, policyID := range ballot {
+			if !known[policyID] {
+				known[policyID] = true
+				extra = append(extra, policyID)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(candidates, extra...)
+}
+
+func zeroScores(candidates []string) map[string]int {
+	scores := make(map[string]int, len(candidates))
+	for _, policyID := range candidates {
+		scores[policyID] = 0
+	}
+	return scores
+}
+
+// singleRound は1ラウンドだけの集計結果を返す（誰も投票していなければ勝者なし）
+func singleRound(scores map[string]int, candidates []string, voted bool) VoteTally {
+	tally := VoteTally{Rounds: []VoteRound{{Scores: scores}}}
+	if voted {
+		tally.Winners = topScorers(scores, candidates)
+	}
+	return tally
+}
+
+// topScorers は最も得点の高い政策を candidates の順で返す
+func topScorers(scores map[string]int, candidates []string) []string {
+	best := 0
+	for _, policyID := range candidates {
+		if scores[policyID] > best {
+			best = scores[policyID]
+		}
+	}
+	var top []string
+	for _, policyID := range candidates {
+		if scores[policyID] == best {
+			top = append(top, policyID)
+		}
+	}
+	return top
+}
+
+func minScore(scores map[string]int) int {
+	lowest := -1
+	for _, score := range scores {
+		if lowest < 0 || score < lowest {
+			lowest = score
+		}
+	}
+	return lowest
+}
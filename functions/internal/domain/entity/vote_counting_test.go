@@ -0,0 +1,143 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVoteCounter_Count(t *testing.T) {
+	options := []string{"a", "b", "c"}
+	tests := []struct {
+		name        string
+		method      VotingMethod
+		ballots     [][]string
+		wantScores  []map[string]int // ラウンドごとの得点
+		wantElim    [][]string       // ラウンドごとの脱落
+		wantWinners []string
+	}{
+		{
+			name:        "PLURALITY は第1希望だけを数える",
+			method:      VotingMethodPlurality,
+			ballots:     [][]string{{"a"}, {"b"}, {"b"}},
+			wantScores:  []map[string]int{{"a": 1, "b": 2, "c": 0}},
+			wantElim:    [][]string{nil},
+			wantWinners: []string{"b"},
+		},
+		{
+			name:        "APPROVAL は賛成した政策全てに1点",
+			method:      VotingMethodApproval,
+			ballots:     [][]string{{"a", "c"}, {"b"}, {"c", "b"}},
+			wantScores:  []map[string]int{{"a": 1, "b": 2, "c": 2}},
+			wantElim:    [][]string{nil},
+			wantWinners: []string{"b", "c"},
+		},
+		{
+			name:        "BORDA は順位に応じた点の合計",
+			method:      VotingMethodBorda,
+			ballots:     [][]string{{"a", "b", "c"}, {"b", "c", "a"}, {"c", "b"}},
+			wantScores:  []map[string]int{{"a": 2, "b": 4, "c": 3}},
+			wantElim:    [][]string{nil},
+			wantWinners: []string{"b"},
+		},
+		{
+			name:   "INSTANT_RUNOFF は過半数に達するまで最下位の票を移す",
+			method: VotingMethodInstantRunoff,
+			// 第1希望は a:2 b:2 c:1 → c が脱落し、c の票が b に移る
+			ballots: [][]string{{"a"}, {"a", "c"}, {"b"}, {"b", "a"}, {"c", "b"}},
+			wantScores: []map[string]int{
+				{"a": 2, "b": 2, "c": 1},
+				{"a": 2, "b": 3},
+			},
+			wantElim:    [][]string{{"c"}, nil},
+			wantWinners: []string{"b"},
+		},
+		{
+			name:   "INSTANT_RUNOFF で残り全てが同数なら同数の勝者",
+			method: VotingMethodInstantRunoff,
+			// 第1希望に c がないので c が脱落、a と b が同数で残る
+			ballots: [][]string{{"a", "c"}, {"b"}},
+			wantScores: []map[string]int{
+				{"a": 1, "b": 1, "c": 0},
+				{"a": 1, "b": 1},
+			},
+			wantElim:    [][]string{{"c"}, nil},
+			wantWinners: []string{"a", "b"},
+		},
+		{
+			name:        "誰も投票していなければ勝者なし",
+			method:      VotingMethodInstantRunoff,
+			wantScores:  []map[string]int{{"a": 0, "b": 0, "c": 0}},
+			wantElim:    [][]string{nil},
+			wantWinners: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tally := NewVoteCounter(tt.method).Count(tt.ballots, options)
+
+			if len(tally.Rounds) != len(tt.wantScores) {
+				t.Fatalf("rounds = %+v, want %dラウンド", tally.Rounds, len(tt.wantScores))
+			}
+			for i, round := range tally.Rounds {
+				if !reflect.DeepEqual(round.Scores, tt.wantScores[i]) {
+					t.Errorf("rounds[%d].scores = %v, want %v", i, round.Scores, tt.wantScores[i])
+				}
+				if !reflect.DeepEqual(round.Eliminated, tt.wantElim[i]) {
+					t.Errorf("rounds[%d].eliminated = %v, want %v", i, round.Eliminated, tt.wantElim[i])
+				}
+			}
+			if !reflect.DeepEqual(tally.Winners, tt.wantWinners) {
+				t.Errorf("winners = %v, want %v", tally.Winners, tt.wantWinners)
+			}
+		})
+	}
+}
+
+func TestRoom_ValidateBallot(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  VotingMethod
+		ballot  []string
+		wantErr error
+	}{
+		{name: "PLURALITY で1つ", method: VotingMethodPlurality, ballot: []string{"a"}},
+		{name: "PLURALITY で複数", method: VotingMethodPlurality, ballot: []string{"a", "b"}, wantErr: ErrInvalidBallot},
+		{name: "未設定は PLURALITY と同じ", method: "", ballot: []string{"a", "b"}, wantErr: ErrInvalidBallot},
+		{name: "APPROVAL で複数", method: VotingMethodApproval, ballot: []string{"c", "a"}},
+		{name: "INSTANT_RUNOFF で一部だけ順位付け", method: VotingMethodInstantRunoff, ballot: []string{"b", "a"}},
+		{name: "同じ政策を重ねて選ぶ", method: VotingMethodBorda, ballot: []string{"a", "a"}, wantErr: ErrInvalidBallot},
+		{name: "空の票", method: VotingMethodApproval, ballot: nil, wantErr: ErrInvalidBallot},
+		{name: "選択肢にない政策", method: VotingMethodApproval, ballot: []string{"a", "x"}, wantErr: ErrInvalidPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &Room{
+				Settings:         RoomSettings{VotingMethod: tt.method},
+				CurrentPolicyIDs: []string{"a", "b", "c"},
+			}
+			if err := room.ValidateBallot(tt.ballot); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateBallot(%v) = %v, want %v", tt.ballot, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRoom_TallyVotes_FallsBackToVotes(t *testing.T) {
+	// ballots のない投票は第1希望だけの票として数える
+	room := &Room{
+		Settings:         RoomSettings{VotingMethod: VotingMethodApproval},
+		CurrentPolicyIDs: []string{"a", "b"},
+		Votes:            map[string]string{"u1": "a", "u2": "b", "u3": ""},
+		Ballots:          map[string][]string{"u2": {"b", "a"}},
+	}
+	tally := room.TallyVotes()
+	if want := map[string]int{"a": 2, "b": 1}; !reflect.DeepEqual(tally.Rounds[0].Scores, want) {
+		t.Errorf("scores = %v, want %v", tally.Rounds[0].Scores, want)
+	}
+	if got := room.CountVotes(); got != "a" {
+		t.Errorf("CountVotes() = %q, want a", got)
+	}
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+// JobQueue はバックグラウンドジョブの待ち行列を担当するインターフェース
+// パス: jobs/{jobId}
+// 実装はインスタンス内のメモリ（ローカル開発用）と Firestore（複数インスタンス用）
+type JobQueue interface {
+	// Enqueue はジョブを登録する
+	// 同じIDのジョブが既にあれば（完了・失敗したものを含む）何もしない
+	Enqueue(ctx context.Context, job *entity.Job) error
+
+	// Claim は now の時点で実行できるジョブを最大 limit 件取り出し、lease の間は実行中にする
+	// 複数のインスタンスが同時に呼んでも、同じジョブはリースが切れるまで1つのインスタンスにしか渡さない
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Job, error)
+
+	// Complete は Claim で取り出したジョブを完了にする
+	// リースが切れて他のインスタンスが取り出し直していれば何もせず entity.ErrJobLeaseLost を返す（Retry・Fail も同じ）
+	Complete(ctx context.Context, job *entity.Job) error
+
+	// Retry はジョブを runAt に再び実行する（失敗の理由を記録する）
+	Retry(ctx context.Context, job *entity.Job, runAt time.Time, lastError string) error
+
+	// Fail はジョブを失敗にする（これ以上実行しない）
+	Fail(ctx context.Context, job *entity.Job, lastError string) error
+
+	// Purge は完了・失敗してから before まで更新のないジョブを最大 limit 件削除し、削除した数を返す
+	// 削除したジョブと同じIDのジョブは再び登録できる
+	Purge(ctx context.Context, before time.Time, limit int) (int, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// RoomCodeRepository は部屋コードの予約を担当するインターフェース
+// パス: room_codes/{code}
+// 部屋がある間は部屋コードを予約しておき、同じコードが2つの部屋に割り当てられないようにする
+type RoomCodeRepository interface {
+	// Reserve は部屋コードを部屋に予約する（既に予約されていれば entity.ErrRoomCodeTaken）
+	// 予約は作成のみで行うため、同時に同じコードを予約しても1つだけが成功する
+	Reserve(ctx context.Context, code, roomID string, at time.Time) error
+
+	// FindRoomID は部屋コードを予約している部屋のIDを返す（予約されていなければ空文字）
+	FindRoomID(ctx context.Context, code string) (string, error)
+
+	// Release は部屋コードの予約を解除する（予約されていなければ何もしない）
+	Release(ctx context.Context, code string) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 )
@@ -12,16 +13,43 @@ type RoomRepository interface {
 	// FindByID は指定されたIDの部屋を取得する
 	FindByID(ctx context.Context, roomID string) (*entity.Room, error)
 
+	// FindIDsPastDeadline は deadline が now 以前の部屋のIDを返す（制限時間切れの処理に使う）
+	FindIDsPastDeadline(ctx context.Context, now time.Time) ([]string, error)
+
+	// FindIDsInactiveSince は before 以前から操作のない部屋のIDを返す（操作のない部屋の削除に使う）
+	// lastActivityAt がない部屋は createdAt で判定する
+	FindIDsInactiveSince(ctx context.Context, before time.Time) ([]string, error)
+
+	// FindPublicLobbies は公開（visibility が PUBLIC）で LOBBY の部屋を作成の新しい順に最大 limit 件返す
+	// 満員かどうかは判定しない
+	FindPublicLobbies(ctx context.Context, limit int) ([]*RoomWithID, error)
+
 	// Create は新しい部屋を作成する
 	Create(ctx context.Context, room *entity.Room) (string, error)
 
-	// Update は部屋の情報を更新する
+	// Update は部屋の情報を更新する（ドキュメント全体を上書き）
 	Update(ctx context.Context, roomID string, room *entity.Room) error
 
+	// UpdateVote は votes.{userId}（第1希望）・ballots.{userId}・lockedIn.{userId}・lastActivityAt のみを更新する
+	// ballot が空なら未投票に戻す
+	UpdateVote(ctx context.Context, roomID, userID string, ballot []string, lockedIn bool, at time.Time) error
+
+	// Touch は lastActivityAt のみを更新する（部屋を書き換えない操作を記録する）
+	Touch(ctx context.Context, roomID string, at time.Time) error
+
+	// UpdateCityImageURL は lastResult.cityImageUrl のみを更新する
+	UpdateCityImageURL(ctx context.Context, roomID, url string) error
+
 	// Delete は部屋を削除する
 	Delete(ctx context.Context, roomID string) error
 }
 
+// RoomWithID は部屋とそのIDをセットにした構造体
+type RoomWithID struct {
+	RoomID string
+	Room   *entity.Room
+}
+
 // PlayerWithID はプレイヤーとそのIDをセットにした構造体
 type PlayerWithID struct {
 	UserID string
@@ -46,6 +74,9 @@ type PlayerRepository interface {
 	// Update はプレイヤー情報を更新する
 	Update(ctx context.Context, roomID, userID string, player *entity.Player) error
 
+	// UpdateCurrentVote は currentVote（第1希望）と currentBallot のみを更新する
+	UpdateCurrentVote(ctx context.Context, roomID, userID string, ballot []string) error
+
 	// Delete はプレイヤーを削除する
 	Delete(ctx context.Context, roomID, userID string) error
 
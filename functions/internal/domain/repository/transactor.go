@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+)
+
+// Transactor はトランザクション（Unit of Work）の境界を提供するインターフェース
+type Transactor interface {
+	// RunInTransaction は fn をトランザクション内で実行する
+	// fn に渡された ctx を使ったリポジトリ操作はトランザクションに参加し、まとめてコミットされる
+	// 競合時は fn が再実行されるため、fn 内で外部API呼び出しなどの副作用を起こさないこと
+	// ⚠️ Firestore の制約により、読み取りは全て書き込みより前に行うこと
+	RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
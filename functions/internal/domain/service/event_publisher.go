@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+// EventPublisher は部屋のイベントを配信するインターフェース
+type EventPublisher interface {
+	// Publish はイベントを購読者に配信する（ベストエフォート、配信失敗はユースケースを失敗させない）
+	Publish(ctx context.Context, event entity.RoomEvent)
+}
+
+// EventSubscriber は部屋のイベントを購読するインターフェース
+type EventSubscriber interface {
+	// Subscribe は指定された部屋のイベントを購読する
+	// 戻り値の関数で購読を解除する（解除後にチャネルは閉じられる）
+	Subscribe(ctx context.Context, roomID string) (<-chan entity.RoomEvent, func())
+}
@@ -9,4 +9,7 @@ type ImageStorage interface {
 	// UploadCityImage は街の画像をアップロードし、signed URLを返す
 	// roomID と turn を使ってユニークなパスを生成する
 	UploadCityImage(ctx context.Context, roomID string, turn int, imageData []byte) (signedURL string, err error)
+
+	// DeleteCityImages は部屋の街の画像を全て削除する（画像がなければ何もしない）
+	DeleteCityImages(ctx context.Context, roomID string) error
 }
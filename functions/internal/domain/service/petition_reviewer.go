@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+// PetitionContext は陳情審査のコンテキスト情報
+type PetitionContext struct {
+	PetitionText   string                 // 陳情テキスト
+	PassedPolicies []*entity.MasterPolicy // これまで採用された政策
+	CityParams     entity.CityParams      // 現在の国のパラメータ
+	CityRules      entity.CityRules       // 各分野の下限・上限とその扱い
+}
+
+// PetitionResult は陳情審査の結果
+type PetitionResult struct {
+	Approved bool
+	Policy   *entity.MasterPolicy // 承認時のみ
+	Reason   string               // 却下時のみ
+}
+
+// PetitionReviewer は陳情を審査するインターフェース
+type PetitionReviewer interface {
+	// ReviewPetition は陳情を審査し、承認された場合は政策カードを生成する
+	ReviewPetition(ctx context.Context, petitionCtx *PetitionContext) (*PetitionResult, error)
+}
+
+// ModerationResult は陳情の事前チェックの結果
+type ModerationResult struct {
+	Allowed bool
+	Reason  string // 不許可の理由（ユーザーに表示できる文言）
+}
+
+// PetitionModerator は陳情をAI審査の前後でチェックするインターフェース
+type PetitionModerator interface {
+	// Moderate は陳情文をAI審査に渡してよいか判定する（長さ・不適切な表現・プロンプトインジェクション）
+	Moderate(ctx context.Context, petitionText string) (*ModerationResult, error)
+
+	// CheckRelevance は生成された政策を陳情の結果として採用してよいか判定する
+	// 採用しない場合は Allowed を false にして理由を返す（判定できなかった場合のみエラーを返す）
+	CheckRelevance(ctx context.Context, petitionText string, policy *entity.MasterPolicy) (*ModerationResult, error)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// Identity は認証されたリクエスト元のプレイヤーを表す
+type Identity struct {
+	UserID string // プレイヤーID（rooms/{roomId}/players/{userId} のドキュメントID）
+	RoomID string // セッションを発行した部屋（Firebase の ID トークンでは空で、どの部屋にも使える）
+}
+
+// CanActIn はリクエスト元が指定された部屋で操作できるかを判定する
+func (i *Identity) CanActIn(roomID string) bool {
+	return i.RoomID == "" || i.RoomID == roomID
+}
+
+// SessionToken は発行したセッショントークン
+type SessionToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// SessionIssuer は部屋を作成・参加したプレイヤーにセッショントークンを発行するインターフェース
+type SessionIssuer interface {
+	// IssueSession は指定された部屋のプレイヤーとして操作できるトークンを発行する
+	IssueSession(roomID, userID string) (*SessionToken, error)
+}
+
+// TokenVerifier はリクエストのトークンを検証するインターフェース
+type TokenVerifier interface {
+	// VerifyToken はトークンを検証してリクエスト元を返す
+	// トークンが不正・期限切れなら entity.ErrUnauthenticated を返す
+	VerifyToken(ctx context.Context, token string) (*Identity, error)
+}
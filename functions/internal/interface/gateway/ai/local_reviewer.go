@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+const (
+	localMainEffect      = 15 // 陳情の主目的となる分野への効果
+	localTradeOffEffect  = -5 // トレードオフとなる分野への効果
+	localMinPetitionRune = 5  // これより短い陳情は却下する
+)
+
+// localRule はキーワードと分野の対応（上から順に評価する）
+type localRule struct {
+	param    string   // 主目的の分野
+	tradeOff string   // トレードオフとなる分野
+	keywords []string // 陳情に含まれていれば一致とみなす語
+}
+
+var localRules = []localRule{
+	{param: "economy", tradeOff: "environment", keywords: []string{"経済", "景気", "雇用", "企業", "産業", "減税", "税"}},
+	{param: "welfare", tradeOff: "economy", keywords: []string{"福祉", "医療", "年金", "介護", "子育て", "保育"}},
+	{param: "education", tradeOff: "economy", keywords: []string{"教育", "学校", "大学", "研究", "図書館", "学び"}},
+	{param: "environment", tradeOff: "economy", keywords: []string{"環境", "自然", "森", "緑", "エネルギー", "リサイクル"}},
+	{param: "security", tradeOff: "humanRights", keywords: []string{"治安", "警察", "防犯", "防災", "軍", "監視"}},
+	{param: "humanRights", tradeOff: "security", keywords: []string{"人権", "自由", "平等", "差別", "プライバシー"}},
+}
+
+// LocalReviewer はキーワードの規則だけで陳情を審査する PetitionReviewer
+// 外部APIを呼ばず、同じ入力には常に同じ結果を返す（オフライン開発・CI用）
+type LocalReviewer struct{}
+
+// インターフェースの実装を保証
+var _ service.PetitionReviewer = (*LocalReviewer)(nil)
+
+// NewLocalReviewer は LocalReviewer を作成する
+func NewLocalReviewer() *LocalReviewer {
+	return &LocalReviewer{}
+}
+
+// ReviewPetition は陳情を審査する
+// 1. 短すぎる陳情は却下
+// 2. いずれかの分野のキーワードを含めば承認し、その分野を +15、トレードオフの分野を -5 とする
+// 3. どの分野にも当てはまらなければ却下
+func (r *LocalReviewer) ReviewPetition(ctx context.Context, petitionCtx *service.PetitionContext) (*service.PetitionResult, error) {
+	text := strings.TrimSpace(petitionCtx.PetitionText)
+	if utf8.RuneCountInString(text) < localMinPetitionRune {
+		return &service.PetitionResult{
+			Approved: false,
+			Reason:   "提案の内容が具体的でないため、審議を見送ります。",
+		}, nil
+	}
+
+	rule := matchLocalRule(text)
+	if rule == nil {
+		return &service.PetitionResult{
+			Approved: false,
+			Reason:   "現時点では国政の優先課題との関連が認められないため、採択を見送ります。",
+		}, nil
+	}
+
+	effects := make(map[string]int, len(entity.PolicyEffectKeys))
+	for _, key := range entity.PolicyEffectKeys {
+		effects[key] = 0
+	}
+	effects[rule.param] = localMainEffect
+	effects[rule.tradeOff] = localTradeOffEffect
+
+	title := truncateRunes(text, entity.GeneratedPolicyTitleMaxLength)
+	return &service.PetitionResult{
+		Approved: true,
+		Policy: &entity.MasterPolicy{
+			Title:       title,
+			Description: text,
+			NewsFlash:   "【速報】市民の提案「" + title + "」が政策として採択！",
+			Effects:     effects,
+		},
+	}, nil
+}
+
+// matchLocalRule は陳情に最初に一致した規則を返す（一致しなければ nil）
+func matchLocalRule(text string) *localRule {
+	for i := range localRules {
+		for _, keyword := range localRules[i].keywords {
+			if strings.Contains(text, keyword) {
+				return &localRules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// truncateRunes は文字数（rune）で切り詰める
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+func TestLocalReviewer_ReviewPetition(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantApproved bool
+		wantMain     string
+		wantTradeOff string
+	}{
+		{name: "短すぎる陳情は却下", text: "税", wantApproved: false},
+		{name: "どの分野にも当てはまらなければ却下", text: "毎日晴れにしてほしいです", wantApproved: false},
+		{name: "教育の陳情", text: "図書館を24時間開館にしてほしい", wantApproved: true, wantMain: "education", wantTradeOff: "economy"},
+		{name: "治安の陳情は人権とトレードオフ", text: "駅前に防犯カメラを増やしてください", wantApproved: true, wantMain: "security", wantTradeOff: "humanRights"},
+	}
+
+	reviewer := NewLocalReviewer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			petitionCtx := &service.PetitionContext{PetitionText: tt.text}
+			got, err := reviewer.ReviewPetition(context.Background(), petitionCtx)
+			if err != nil {
+				t.Fatalf("ReviewPetition: %v", err)
+			}
+			if got.Approved != tt.wantApproved {
+				t.Fatalf("Approved = %v, want %v (reason: %s)", got.Approved, tt.wantApproved, got.Reason)
+			}
+			if !tt.wantApproved {
+				if got.Reason == "" {
+					t.Error("却下理由が空")
+				}
+				return
+			}
+
+			effects := got.Policy.Effects
+			if len(effects) != 6 {
+				t.Errorf("effects = %v, want 6分野", effects)
+			}
+			if effects[tt.wantMain] != localMainEffect || effects[tt.wantTradeOff] != localTradeOffEffect {
+				t.Errorf("effects = %v, want %s:+%d %s:%d", effects, tt.wantMain, localMainEffect, tt.wantTradeOff, localTradeOffEffect)
+			}
+
+			// 同じ入力には同じ結果を返す
+			again, _ := reviewer.ReviewPetition(context.Background(), petitionCtx)
+			if !reflect.DeepEqual(got, again) {
+				t.Error("同じ陳情で結果が変わった")
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+const (
+	defaultPetitionMaxLength = 200 // 陳情文の最大文字数
+	minRelevantBigrams       = 2   // 陳情と政策で共通すべき2文字の組の最小数
+)
+
+// defaultBlocklist は陳情に含めてはいけない語（ニュース速報として全員に表示されるため）
+var defaultBlocklist = []string{
+	"死ね", "殺す", "殺せ", "クズ", "ゴミ人間", "キチガイ", "ガイジ",
+	"fuck", "shit", "bitch", "kill yourself",
+}
+
+// injectionPatterns は審査の指示を書き換えようとする表現
+var injectionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)ignore\s+(all\s+|any\s+)?(the\s+)?(previous|prior|above|earlier)\s+(instructions?|prompts?|rules?)`),
+	regexp.MustCompile(`(?i)disregard\s+.{0,20}(instructions?|rules?)`),
+	regexp.MustCompile(`(?i)(system|developer)\s*prompt`),
+	regexp.MustCompile(`(?i)"?approved"?\s*[:=]\s*true`),
+	regexp.MustCompile(`(?i)you\s+are\s+now`),
+	regexp.MustCompile(`(以前|前|上|これまで)の(指示|命令|ルール|プロンプト).{0,10}(無視|忘れ)`),
+	regexp.MustCompile(`システムプロンプト`),
+	regexp.MustCompile(`(必ず|絶対に?)(承認|可決)`),
+	regexp.MustCompile(`(全て|すべて|全部|全パラメータ).{0,10}[+＋]\s*20`),
+}
+
+// RuleModerator は文字数・禁止語・パターンによる規則ベースの PetitionModerator
+type RuleModerator struct {
+	maxLength      int
+	blocklist      []string
+	strictRelevant bool // true なら陳情と政策に共通する語がない政策も採用しない
+}
+
+// インターフェースの実装を保証
+var _ service.PetitionModerator = (*RuleModerator)(nil)
+
+// NewRuleModerator は RuleModerator を作成する
+// 環境変数 PETITION_BLOCKLIST（カンマ区切り）で禁止語を追加できる
+// 環境変数 PETITION_STRICT_RELEVANCE=true で、陳情と政策に共通する語があるかも確認する
+// （言い換えた政策も弾いてしまうため、デフォルトでは確認しない）
+func NewRuleModerator() *RuleModerator {
+	blocklist := append([]string(nil), defaultBlocklist...)
+	for _, word := range strings.Split(os.Getenv("PETITION_BLOCKLIST"), ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			blocklist = append(blocklist, word)
+		}
+	}
+	return &RuleModerator{
+		maxLength:      defaultPetitionMaxLength,
+		blocklist:      blocklist,
+		strictRelevant: os.Getenv("PETITION_STRICT_RELEVANCE") == "true",
+	}
+}
+
+// Moderate は陳情文をAI審査に渡してよいか判定する
+// 1. 空でないこと、文字数の上限を超えていないこと
+// 2. 禁止語を含まないこと
+// 3. 審査の指示を書き換えようとする表現を含まないこと
+func (m *RuleModerator) Moderate(ctx context.Context, petitionText string) (*service.ModerationResult, error) {
+	text := strings.TrimSpace(petitionText)
+	if text == "" {
+		return &service.ModerationResult{Allowed: false, Reason: "提案内容が空です"}, nil
+	}
+	if utf8.RuneCountInString(text) > m.maxLength {
+		return &service.ModerationResult{Allowed: false, Reason: fmt.Sprintf("提案は%d文字以内で入力してください", m.maxLength)}, nil
+	}
+
+	lower := strings.ToLower(text)
+	for _, word := range m.blocklist {
+		if strings.Contains(lower, strings.ToLower(word)) {
+			return &service.ModerationResult{Allowed: false, Reason: "不適切な表現が含まれています"}, nil
+		}
+	}
+
+	for _, pattern := range injectionPatterns {
+		if pattern.MatchString(text) {
+			return &service.ModerationResult{Allowed: false, Reason: "審査への指示と解釈される表現は使えません"}, nil
+		}
+	}
+
+	return &service.ModerationResult{Allowed: true}, nil
+}
+
+// CheckRelevance は生成された政策を陳情の結果として採用してよいか判定する
+// 3分野以上が上限まで上がるような不自然な効果は、指示の上書きで作らせた政策とみなして採用しない
+// strictRelevant なら、陳情文と政策（タイトル・説明・ニュース速報）に共通する2文字の組がない政策も採用しない
+func (m *RuleModerator) CheckRelevance(ctx context.Context, petitionText string, policy *entity.MasterPolicy) (*service.ModerationResult, error) {
+	if policy == nil {
+		return &service.ModerationResult{Allowed: false, Reason: "政策を作成できませんでした"}, nil
+	}
+
+	maxed := 0
+	for _, v := range policy.Effects {
+		if v >= entity.GeneratedPolicyEffectMax {
+			maxed++
+		}
+	}
+	if maxed >= 3 {
+		return &service.ModerationResult{Allowed: false, Reason: "効果が大きすぎる政策は採用できません"}, nil
+	}
+
+	if m.strictRelevant && !sharesWords(petitionText, policy) {
+		return &service.ModerationResult{Allowed: false, Reason: "提案の内容と関係のない政策になりました"}, nil
+	}
+	return &service.ModerationResult{Allowed: true}, nil
+}
+
+// sharesWords は陳情文と政策に共通する2文字の組が十分にあるかを判定する
+func sharesWords(petitionText string, policy *entity.MasterPolicy) bool {
+	petitionBigrams := bigrams(petitionText)
+	if len(petitionBigrams) == 0 {
+		return false
+	}
+	policyBigrams := bigrams(policy.Title + " " + policy.Description + " " + policy.NewsFlash)
+
+	shared := 0
+	for b := range petitionBigrams {
+		if policyBigrams[b] {
+			shared++
+		}
+	}
+	required := minRelevantBigrams
+	if len(petitionBigrams) < required {
+		required = len(petitionBigrams)
+	}
+	return shared >= required
+}
+
+// bigrams は記号・空白・ひらがなを除いた文字列から連続する2文字の組を作る
+// ひらがなは助詞や語尾が多く、内容と関係なく一致してしまうため除外する
+func bigrams(s string) map[string]bool {
+	var runes []rune
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			runes = append(runes, r)
+		} else {
+			runes = append(runes, ' ')
+		}
+	}
+
+	result := make(map[string]bool)
+	for i := 0; i+1 < len(runes); i++ {
+		a, b := runes[i], runes[i+1]
+		if a == ' ' || b == ' ' || unicode.In(a, unicode.Hiragana) || unicode.In(b, unicode.Hiragana) {
+			continue
+		}
+		result[string([]rune{a, b})] = true
+	}
+	return result
+}
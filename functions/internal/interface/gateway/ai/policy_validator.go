@@ -0,0 +1,182 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// aiResponse は AI が返す審査結果の JSON
+// 型の誤りを検出するため、値は json.RawMessage のまま受け取って個別に検証する
+type aiResponse struct {
+	Approved    json.RawMessage            `json:"approved"`
+	Title       json.RawMessage            `json:"title"`
+	Description json.RawMessage            `json:"description"`
+	NewsFlash   json.RawMessage            `json:"newsFlash"`
+	Effects     map[string]json.RawMessage `json:"effects"`
+	Reason      json.RawMessage            `json:"reason"`
+}
+
+// parseAIResponse は AI の応答を検証・正規化して審査結果に変換する
+// 修正できない問題があれば違反内容を返す（呼び出し元は違反内容を添えて再生成を依頼する）
+// 1. JSON として解釈できるか、必須項目が正しい型で存在するか
+// 2. タイトル・説明・ニュース速報が空でなく、文字数の上限を超えていないか
+// 3. effects のキーを正規化（human_rights → humanRights など）し、不明なキーは違反
+// 4. 範囲外の効果は -20〜+20 に丸め、欠けている分野は 0 で補う
+func parseAIResponse(content string) (*service.PetitionResult, []string) {
+	slog.Debug("AI raw response", slog.String("content", content))
+
+	// マークダウンのコードブロックを除去
+	cleanContent := extractJSON(content)
+
+	var resp aiResponse
+	if err := json.Unmarshal([]byte(cleanContent), &resp); err != nil {
+		return nil, []string{"応答がJSONとして解釈できません（JSONオブジェクトのみを出力してください）"}
+	}
+
+	var violations []string
+
+	var approved bool
+	if err := decodeRequired(resp.Approved, &approved); err != nil {
+		return nil, []string{`"approved" は true または false で指定してください`}
+	}
+
+	if !approved {
+		var reason string
+		if err := decodeRequired(resp.Reason, &reason); err != nil || strings.TrimSpace(reason) == "" {
+			return nil, []string{`却下する場合は "reason" に却下理由を文字列で記載してください`}
+		}
+		return &service.PetitionResult{
+			Approved: false,
+			Reason:   strings.TrimSpace(reason),
+		}, nil
+	}
+
+	title := requiredText(resp.Title, "title", entity.GeneratedPolicyTitleMaxLength, &violations)
+	description := requiredText(resp.Description, "description", 0, &violations)
+	newsFlash := requiredText(resp.NewsFlash, "newsFlash", entity.GeneratedPolicyNewsMaxLength, &violations)
+	effects := normalizeEffects(resp.Effects, &violations)
+
+	if len(violations) > 0 {
+		return nil, violations
+	}
+
+	return &service.PetitionResult{
+		Approved: true,
+		Policy: &entity.MasterPolicy{
+			Title:       title,
+			Description: description,
+			NewsFlash:   newsFlash,
+			Effects:     effects,
+		},
+	}, nil
+}
+
+// decodeRequired は必須項目を指定した型で読み取る（項目がない場合もエラー）
+func decodeRequired(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return fmt.Errorf("missing")
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// requiredText は空でない文字列項目を読み取り、文字数の上限を確認する（maxLen が 0 なら上限なし）
+func requiredText(raw json.RawMessage, name string, maxLen int, violations *[]string) string {
+	var s string
+	if err := decodeRequired(raw, &s); err != nil {
+		*violations = append(*violations, fmt.Sprintf(`"%s" を文字列で指定してください`, name))
+		return ""
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		*violations = append(*violations, fmt.Sprintf(`"%s" が空です`, name))
+		return ""
+	}
+	if maxLen > 0 && utf8.RuneCountInString(s) > maxLen {
+		*violations = append(*violations, fmt.Sprintf(`"%s" は%d文字以内にしてください（現在%d文字）`, name, maxLen, utf8.RuneCountInString(s)))
+	}
+	return s
+}
+
+// normalizeEffects は effects を正規化する
+// キーの表記揺れを吸収し、範囲外の値は丸め、欠けている分野は 0 で補う
+func normalizeEffects(raw map[string]json.RawMessage, violations *[]string) map[string]int {
+	if raw == nil {
+		*violations = append(*violations, `"effects" に6分野の効果をオブジェクトで指定してください`)
+		return nil
+	}
+
+	effects := make(map[string]int, len(entity.PolicyEffectKeys))
+	for _, key := range entity.PolicyEffectKeys {
+		effects[key] = 0
+	}
+
+	seen := make(map[string]string) // 正規化後のキー -> 元のキー
+	for rawKey, rawValue := range raw {
+		key, ok := canonicalEffectKey(rawKey)
+		if !ok {
+			*violations = append(*violations, fmt.Sprintf(`"effects" の "%s" は不明な分野です（使えるのは %s のみ）`, rawKey, strings.Join(entity.PolicyEffectKeys, ", ")))
+			continue
+		}
+		if prev, dup := seen[key]; dup {
+			*violations = append(*violations, fmt.Sprintf(`"effects" の "%s" と "%s" が重複しています`, prev, rawKey))
+			continue
+		}
+		seen[key] = rawKey
+
+		var value float64
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			*violations = append(*violations, fmt.Sprintf(`"effects.%s" は数値で指定してください`, key))
+			continue
+		}
+		effects[key] = clampEffect(key, int(math.Round(value)))
+	}
+
+	return effects
+}
+
+// canonicalEffectKey はキーの表記揺れ（human_rights, HumanRights など）を正規のキーに変換する
+func canonicalEffectKey(key string) (string, bool) {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(key))
+	for _, k := range entity.PolicyEffectKeys {
+		if strings.ToLower(k) == normalized {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// clampEffect は効果を -20〜+20 の範囲に丸める
+func clampEffect(key string, value int) int {
+	clamped := value
+	if clamped < entity.GeneratedPolicyEffectMin {
+		clamped = entity.GeneratedPolicyEffectMin
+	}
+	if clamped > entity.GeneratedPolicyEffectMax {
+		clamped = entity.GeneratedPolicyEffectMax
+	}
+	if clamped != value {
+		slog.Warn("AI policy effect out of range, clamped",
+			slog.String("param", key), slog.Int("value", value), slog.Int("clamped", clamped))
+	}
+	return clamped
+}
+
+// buildCorrectionPrompt は違反内容を伝えて回答の修正を依頼するプロンプトを作る
+func buildCorrectionPrompt(violations []string) string {
+	var sb strings.Builder
+	sb.WriteString("先ほどの回答には以下の問題がありました。回答形式に従って修正したJSONのみを出力してください。\n")
+	for _, v := range violations {
+		sb.WriteString("- ")
+		sb.WriteString(v)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,131 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+func TestParseAIResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantViolations int
+		wantApproved   bool
+		wantEffects    map[string]int
+	}{
+		{
+			name:         "却下",
+			content:      `{"approved": false, "reason": "財源がありません"}`,
+			wantApproved: false,
+		},
+		{
+			name: "範囲外の効果は丸め、欠けた分野は0で補う",
+			content: "```json\n" + `{"approved": true, "title": "森林保護法", "description": "森を守る", "newsFlash": "【速報】成立",
+				"effects": {"environment": 35, "economy": -25.4, "human_rights": 5}}` + "\n```",
+			wantApproved: true,
+			wantEffects:  map[string]int{"economy": -20, "welfare": 0, "education": 0, "environment": 20, "security": 0, "humanRights": 5},
+		},
+		{
+			name:           "JSONでない",
+			content:        "承認します",
+			wantViolations: 1,
+		},
+		{
+			name:           "却下理由がない",
+			content:        `{"approved": false}`,
+			wantViolations: 1,
+		},
+		{
+			name: "タイトルが長すぎる・ニュースが空・不明な分野",
+			content: `{"approved": true, "title": "あいうえおかきくけこさしすせそたちつてとな", "description": "説明", "newsFlash": "",
+				"effects": {"happiness": 10}}`,
+			wantViolations: 3,
+		},
+		{
+			name:           "同じ分野が表記揺れで重複",
+			content:        `{"approved": true, "title": "t", "description": "d", "newsFlash": "n", "effects": {"humanRights": 5, "human_rights": 3}}`,
+			wantViolations: 1,
+		},
+		{
+			name:           "effects がない",
+			content:        `{"approved": true, "title": "t", "description": "d", "newsFlash": "n"}`,
+			wantViolations: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, violations := parseAIResponse(tt.content)
+			if len(violations) != tt.wantViolations {
+				t.Fatalf("violations = %v, want %d件", violations, tt.wantViolations)
+			}
+			if tt.wantViolations > 0 {
+				return
+			}
+			if result.Approved != tt.wantApproved {
+				t.Fatalf("Approved = %v, want %v", result.Approved, tt.wantApproved)
+			}
+			for k, want := range tt.wantEffects {
+				if got := result.Policy.Effects[k]; got != want {
+					t.Errorf("effects[%s] = %d, want %d", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAICompatibleClient_RetriesInvalidResponse(t *testing.T) {
+	invalid := `{"choices":[{"message":{"content":"{\"approved\": true, \"title\": \"\"}"}}]}`
+	valid := `{"choices":[{"message":{"content":"{\"approved\": true, \"title\": \"t\", \"description\": \"d\", \"newsFlash\": \"n\", \"effects\": {\"economy\": 10}}"}}]}`
+
+	tests := []struct {
+		name       string
+		responses  []string
+		maxRetries int
+		wantCalls  int32
+		wantErr    error
+	}{
+		{name: "修正後の応答で成功", responses: []string{invalid, valid}, maxRetries: 2, wantCalls: 2},
+		{name: "上限まで不正なら失敗", responses: []string{invalid, invalid, invalid}, maxRetries: 2, wantCalls: 3, wantErr: entity.ErrInvalidAIResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			var lastBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				buf := new(strings.Builder)
+				_, _ = io.Copy(buf, r.Body)
+				lastBody = buf.String()
+				_, _ = w.Write([]byte(tt.responses[n-1]))
+			}))
+			defer server.Close()
+
+			client := NewOpenAICompatibleClient(Config{Endpoint: server.URL, APIKey: "k", Timeout: time.Second, MaxRetries: tt.maxRetries})
+			_, err := client.ReviewPetition(context.Background(), &service.PetitionContext{PetitionText: "x"})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("ReviewPetition: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("API呼び出し = %d回, want %d回", calls, tt.wantCalls)
+			}
+			if tt.wantCalls > 1 && !strings.Contains(lastBody, "先ほどの回答には以下の問題がありました") {
+				t.Error("再試行時に修正依頼のプロンプトが送られていない")
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// ChainVerifier は複数の TokenVerifier を順に試す（例: セッショントークン → Firebase の ID トークン）
+// いずれかが受け付けたらそのリクエスト元を返し、全てが不正と判定したら最後のエラーを返す
+type ChainVerifier []service.TokenVerifier
+
+// インターフェースの実装を保証
+var _ service.TokenVerifier = ChainVerifier(nil)
+
+// VerifyToken はトークンを受け付ける TokenVerifier を探す
+func (c ChainVerifier) VerifyToken(ctx context.Context, token string) (*service.Identity, error) {
+	err := entity.ErrUnauthenticated
+	for _, verifier := range c {
+		var identity *service.Identity
+		identity, err = verifier.VerifyToken(ctx, token)
+		if err == nil {
+			return identity, nil
+		}
+		// トークン以外の問題（鍵の取得の失敗など）は他の方式で受け付けさせない
+		if !errors.Is(err, entity.ErrUnauthenticated) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
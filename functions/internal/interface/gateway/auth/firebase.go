@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	firebaseAuth "firebase.google.com/go/v4/auth"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// FirebaseVerifier は Firebase Authentication の ID トークンを検証する
+// プレイヤーIDには Firebase の UID を使う（どの部屋でも同じ ID になる）
+// FIREBASE_AUTH_EMULATOR_HOST が設定されていればエミュレーターのトークンを受け付ける
+type FirebaseVerifier struct {
+	client *firebaseAuth.Client
+}
+
+// インターフェースの実装を保証
+var _ service.TokenVerifier = (*FirebaseVerifier)(nil)
+
+// NewFirebaseVerifier は FirebaseVerifier を作成する
+func NewFirebaseVerifier(client *firebaseAuth.Client) *FirebaseVerifier {
+	return &FirebaseVerifier{
+		client: client,
+	}
+}
+
+// VerifyToken は ID トークンを検証してリクエスト元を返す
+// 公開鍵の取得の失敗など、トークン以外の問題はそのままエラーを返す
+func (v *FirebaseVerifier) VerifyToken(ctx context.Context, token string) (*service.Identity, error) {
+	idToken, err := v.client.VerifyIDToken(ctx, token)
+	if err != nil {
+		if firebaseAuth.IsIDTokenInvalid(err) || firebaseAuth.IsIDTokenExpired(err) {
+			return nil, fmt.Errorf("%w: %v", entity.ErrUnauthenticated, err)
+		}
+		return nil, fmt.Errorf("failed to verify firebase ID token: %w", err)
+	}
+	return &service.Identity{UserID: idToken.UID}, nil
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+const (
+	sessionIssuer     = "techworld-hackathon"
+	defaultSessionTTL = 24 * time.Hour
+	minSecretLength   = 32
+)
+
+// sessionClaims はセッショントークン（JWT）のクレーム
+// sub にプレイヤーID、room に部屋IDを入れる
+type sessionClaims struct {
+	RoomID string `json:"room"`
+	jwt.RegisteredClaims
+}
+
+// SessionConfig はセッショントークンの設定
+type SessionConfig struct {
+	Secret []byte        // HMAC の鍵（全インスタンスで同じ値にする）
+	TTL    time.Duration // トークンの有効期間
+}
+
+// SessionConfigFromEnv は環境変数からセッショントークンの設定を読み込む
+// SESSION_SECRET（32バイト以上）, SESSION_TTL（例: 12h）
+// SESSION_SECRET が未設定なら起動ごとにランダムな鍵を使う（再起動・別インスタンスではトークンが無効になる）
+func SessionConfigFromEnv() SessionConfig {
+	cfg := SessionConfig{
+		Secret: []byte(os.Getenv("SESSION_SECRET")),
+		TTL:    defaultSessionTTL,
+	}
+	if len(cfg.Secret) < minSecretLength {
+		if len(cfg.Secret) > 0 {
+			slog.Warn("SESSION_SECRET is too short, using a random secret", slog.Int("minLength", minSecretLength))
+		} else {
+			slog.Warn("SESSION_SECRET not set, using a random secret (sessions are lost on restart)")
+		}
+		cfg.Secret = randomSecret()
+	}
+	if v := os.Getenv("SESSION_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.TTL = d
+		} else {
+			slog.Warn("invalid SESSION_TTL, using default", slog.String("value", v))
+		}
+	}
+	return cfg
+}
+
+// randomSecret はランダムな HMAC の鍵を作成する
+func randomSecret() []byte {
+	secret := make([]byte, minSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("failed to generate session secret: %v", err))
+	}
+	return secret
+}
+
+// SessionManager は HMAC（HS256）で署名した JWT をセッショントークンとして発行・検証する
+// トークンは発行した部屋のプレイヤーとしてのみ使える
+type SessionManager struct {
+	config SessionConfig
+	now    func() time.Time
+}
+
+// インターフェースの実装を保証
+var (
+	_ service.SessionIssuer = (*SessionManager)(nil)
+	_ service.TokenVerifier = (*SessionManager)(nil)
+)
+
+// NewSessionManager は SessionManager を作成する
+func NewSessionManager(config SessionConfig) *SessionManager {
+	return &SessionManager{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// IssueSession は部屋のプレイヤーとして操作できるトークンを発行する
+func (m *SessionManager) IssueSession(roomID, userID string) (*service.SessionToken, error) {
+	now := m.now()
+	expiresAt := now.Add(m.config.TTL)
+	claims := sessionClaims{
+		RoomID: roomID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    sessionIssuer,
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.config.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign session token: %w", err)
+	}
+	return &service.SessionToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// VerifyToken は署名・有効期限・発行元を検証してリクエスト元を返す
+func (m *SessionManager) VerifyToken(ctx context.Context, token string) (*service.Identity, error) {
+	var claims sessionClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return m.config.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrUnauthenticated, err)
+	}
+	if claims.Issuer != sessionIssuer || claims.Subject == "" || claims.RoomID == "" || claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: malformed session claims", entity.ErrUnauthenticated)
+	}
+	return &service.Identity{UserID: claims.Subject, RoomID: claims.RoomID}, nil
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+func newTestSessionManager(secret string) *SessionManager {
+	return NewSessionManager(SessionConfig{Secret: []byte(secret), TTL: time.Hour})
+}
+
+func TestSessionManager_IssueAndVerify(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestSessionManager(strings.Repeat("s", minSecretLength))
+
+	session, err := manager.IssueSession("room1", "player1")
+	if err != nil {
+		t.Fatalf("IssueSession: %v", err)
+	}
+	identity, err := manager.VerifyToken(ctx, session.Token)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if identity.UserID != "player1" || identity.RoomID != "room1" {
+		t.Errorf("identity = %+v, want player1 in room1", identity)
+	}
+	if !identity.CanActIn("room1") || identity.CanActIn("room2") {
+		t.Error("セッションは発行した部屋でのみ使える")
+	}
+}
+
+func TestSessionManager_RejectsInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestSessionManager(strings.Repeat("s", minSecretLength))
+
+	// 別の鍵で署名されたトークン
+	forged, err := newTestSessionManager(strings.Repeat("x", minSecretLength)).IssueSession("room1", "player1")
+	if err != nil {
+		t.Fatalf("IssueSession: %v", err)
+	}
+
+	// 期限切れのトークン
+	expiredManager := newTestSessionManager(strings.Repeat("s", minSecretLength))
+	expiredManager.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	expired, err := expiredManager.IssueSession("room1", "player1")
+	if err != nil {
+		t.Fatalf("IssueSession: %v", err)
+	}
+
+	valid, err := manager.IssueSession("room1", "player1")
+	if err != nil {
+		t.Fatalf("IssueSession: %v", err)
+	}
+	parts := strings.Split(valid.Token, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "空", token: ""},
+		{name: "JWT ではない", token: "not-a-token"},
+		{name: "別の鍵で署名", token: forged.Token},
+		{name: "期限切れ", token: expired.Token},
+		{name: "署名なし", token: parts[0] + "." + parts[1] + "."},
+		{name: "クレームの改ざん", token: parts[0] + "." + parts[1] + "x." + parts[2]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manager.VerifyToken(ctx, tt.token)
+			if !errors.Is(err, entity.ErrUnauthenticated) {
+				t.Errorf("VerifyToken() error = %v, want %v", err, entity.ErrUnauthenticated)
+			}
+		})
+	}
+}
+
+// stubVerifier は決まった結果を返す TokenVerifier
+type stubVerifier struct {
+	identity *service.Identity
+	err      error
+}
+
+func (s stubVerifier) VerifyToken(ctx context.Context, token string) (*service.Identity, error) {
+	return s.identity, s.err
+}
+
+func TestChainVerifier(t *testing.T) {
+	ctx := context.Background()
+	firebaseUser := &service.Identity{UserID: "uid1"}
+	errKeys := errors.New("failed to fetch public keys")
+
+	tests := []struct {
+		name    string
+		chain   ChainVerifier
+		want    *service.Identity
+		wantErr error
+	}{
+		{
+			name:  "後の方式で受け付ける",
+			chain: ChainVerifier{stubVerifier{err: entity.ErrUnauthenticated}, stubVerifier{identity: firebaseUser}},
+			want:  firebaseUser,
+		},
+		{
+			name:    "全ての方式で不正",
+			chain:   ChainVerifier{stubVerifier{err: entity.ErrUnauthenticated}, stubVerifier{err: entity.ErrUnauthenticated}},
+			wantErr: entity.ErrUnauthenticated,
+		},
+		{
+			name:    "トークン以外の失敗は後の方式を試さない",
+			chain:   ChainVerifier{stubVerifier{err: errKeys}, stubVerifier{identity: firebaseUser}},
+			wantErr: errKeys,
+		},
+		{
+			name:    "方式がない",
+			wantErr: entity.ErrUnauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.chain.VerifyToken(ctx, "token")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyToken() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
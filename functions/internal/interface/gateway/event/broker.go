@@ -0,0 +1,77 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+const (
+	subscriberBufferSize = 32
+)
+
+// Broker はプロセス内でイベントを配信するブローカー
+// ⚠️ 同一インスタンスに接続したクライアントにのみ配信される
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan entity.RoomEvent]struct{} // { roomId: { ch } }
+}
+
+// NewBroker は Broker を作成する
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[string]map[chan entity.RoomEvent]struct{}),
+	}
+}
+
+// インターフェースの実装を保証
+var (
+	_ service.EventPublisher  = (*Broker)(nil)
+	_ service.EventSubscriber = (*Broker)(nil)
+)
+
+// Publish はイベントを部屋の購読者全員に配信する
+// バッファが一杯の購読者（読み取りが遅いクライアント）にはイベントを破棄する
+func (b *Broker) Publish(ctx context.Context, event entity.RoomEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.RoomID] {
+		select {
+		case ch <- event:
+		default:
+			slog.Warn("event dropped for slow subscriber",
+				slog.String("roomId", event.RoomID),
+				slog.String("type", string(event.Type)))
+		}
+	}
+}
+
+// Subscribe は指定された部屋のイベントを購読する
+func (b *Broker) Subscribe(ctx context.Context, roomID string) (<-chan entity.RoomEvent, func()) {
+	ch := make(chan entity.RoomEvent, subscriberBufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[roomID] == nil {
+		b.subscribers[roomID] = make(map[chan entity.RoomEvent]struct{})
+	}
+	b.subscribers[roomID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[roomID], ch)
+			if len(b.subscribers[roomID]) == 0 {
+				delete(b.subscribers, roomID)
+			}
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
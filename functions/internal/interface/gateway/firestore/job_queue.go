@@ -0,0 +1,150 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+const jobCollection = "jobs"
+
+// JobQueue は Firestore を使った JobQueue の実装
+// 複数インスタンスで同じ待ち行列を共有する。取り出しはトランザクションで行い、同じジョブを2つのインスタンスに渡さない
+// 取り出しのクエリ（status in [...] かつ runAt <= now を runAt 順）には status・runAt、
+// 完了したジョブの削除のクエリ（status in [...] かつ updatedAt < before）には status・updatedAt の複合インデックスが必要（firestore.indexes.json）
+type JobQueue struct {
+	client *firestore.Client
+}
+
+// NewJobQueue は JobQueue を作成する
+func NewJobQueue(client *firestore.Client) repository.JobQueue {
+	return &JobQueue{
+		client: client,
+	}
+}
+
+// Enqueue はジョブを登録する（同じIDのジョブが既にあれば何もしない）
+func (q *JobQueue) Enqueue(ctx context.Context, job *entity.Job) error {
+	_, err := q.client.Collection(jobCollection).Doc(job.ID).Create(ctx, job)
+	if status.Code(err) == codes.AlreadyExists {
+		return nil
+	}
+	return err
+}
+
+// Claim は実行できるジョブを実行予定時刻の早い順に最大 limit 件取り出す
+// 実行中でリースが切れたジョブも取り出す（実行していたインスタンスが落ちた場合）
+func (q *JobQueue) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Job, error) {
+	query := q.client.Collection(jobCollection).
+		Where("status", "in", []entity.JobStatus{entity.JobStatusPending, entity.JobStatusRunning}).
+		Where("runAt", "<=", now).
+		OrderBy("runAt", firestore.Asc).
+		Limit(limit)
+
+	var claimed []*entity.Job
+	err := q.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+
+		docs, err := tx.Documents(query).GetAll()
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			var job entity.Job
+			if err := doc.DataTo(&job); err != nil {
+				return err
+			}
+			job.ID = doc.Ref.ID
+			job.Claim(now, lease)
+			if err := tx.Update(doc.Ref, []firestore.Update{
+				{Path: "status", Value: job.Status},
+				{Path: "runAt", Value: job.RunAt},
+				{Path: "attempts", Value: job.Attempts},
+				{Path: "updatedAt", Value: job.UpdatedAt},
+			}); err != nil {
+				return err
+			}
+			claimed = append(claimed, &job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// Complete はジョブを完了にする
+func (q *JobQueue) Complete(ctx context.Context, job *entity.Job) error {
+	return q.update(ctx, job, []firestore.Update{
+		{Path: "status", Value: entity.JobStatusDone},
+	})
+}
+
+// Retry はジョブを runAt に再び実行する
+func (q *JobQueue) Retry(ctx context.Context, job *entity.Job, runAt time.Time, lastError string) error {
+	return q.update(ctx, job, []firestore.Update{
+		{Path: "status", Value: entity.JobStatusPending},
+		{Path: "runAt", Value: runAt},
+		{Path: "lastError", Value: lastError},
+	})
+}
+
+// Fail はジョブを失敗にする
+func (q *JobQueue) Fail(ctx context.Context, job *entity.Job, lastError string) error {
+	return q.update(ctx, job, []firestore.Update{
+		{Path: "status", Value: entity.JobStatusFailed},
+		{Path: "lastError", Value: lastError},
+	})
+}
+
+// Purge は完了・失敗してから before まで更新のないジョブを最大 limit 件削除する
+func (q *JobQueue) Purge(ctx context.Context, before time.Time, limit int) (int, error) {
+	docs, err := q.client.Collection(jobCollection).
+		Where("status", "in", []entity.JobStatus{entity.JobStatusDone, entity.JobStatusFailed}).
+		Where("updatedAt", "<", before).
+		Limit(limit).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	batch := q.client.Batch()
+	for _, doc := range docs {
+		batch.Delete(doc.Ref)
+	}
+	if _, err := batch.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// update は取り出したときのリースのままならジョブのフィールドを更新する（updatedAt も更新する）
+// リースの確認と更新の間に他のインスタンスが取り出さないよう、トランザクションで行う
+func (q *JobQueue) update(ctx context.Context, claimed *entity.Job, updates []firestore.Update) error {
+	updates = append(updates, firestore.Update{Path: "updatedAt", Value: time.Now()})
+	ref := q.client.Collection(jobCollection).Doc(claimed.ID)
+	return q.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var current entity.Job
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+		if !current.HeldBy(claimed) {
+			return entity.ErrJobLeaseLost
+		}
+		return tx.Update(ref, updates)
+	})
+}
@@ -0,0 +1,68 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+const roomCodeCollection = "room_codes"
+
+// roomCodeDoc は room_codes/{code} のドキュメント
+type roomCodeDoc struct {
+	RoomID     string    `firestore:"roomId"`
+	ReservedAt time.Time `firestore:"reservedAt"`
+}
+
+// RoomCodeRepository は Firestore を使った RoomCodeRepository の実装
+type RoomCodeRepository struct {
+	client *firestore.Client
+}
+
+// NewRoomCodeRepository は RoomCodeRepository を作成する
+func NewRoomCodeRepository(client *firestore.Client) repository.RoomCodeRepository {
+	return &RoomCodeRepository{
+		client: client,
+	}
+}
+
+// Reserve は部屋コードを部屋に予約する
+// ドキュメントの作成（Create）は既にあれば失敗するため、同時に予約しても1つだけが成功する
+func (r *RoomCodeRepository) Reserve(ctx context.Context, code, roomID string, at time.Time) error {
+	err := createDoc(ctx, r.client.Collection(roomCodeCollection).Doc(code), roomCodeDoc{
+		RoomID:     roomID,
+		ReservedAt: at,
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return entity.ErrRoomCodeTaken
+	}
+	return err
+}
+
+// FindRoomID は部屋コードを予約している部屋のIDを返す（予約されていなければ空文字）
+func (r *RoomCodeRepository) FindRoomID(ctx context.Context, code string) (string, error) {
+	doc, err := getDoc(ctx, r.client.Collection(roomCodeCollection).Doc(code))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var reservation roomCodeDoc
+	if err := doc.DataTo(&reservation); err != nil {
+		return "", err
+	}
+	return reservation.RoomID, nil
+}
+
+// Release は部屋コードの予約を解除する（存在しないドキュメントの削除は成功する）
+func (r *RoomCodeRepository) Release(ctx context.Context, code string) error {
+	return deleteDoc(ctx, r.client.Collection(roomCodeCollection).Doc(code))
+}
@@ -2,6 +2,7 @@ package firestore
 
 import (
 	"context"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"google.golang.org/grpc/codes"
@@ -28,7 +29,7 @@ func NewRoomRepository(client *firestore.Client) repository.RoomRepository {
 
 // FindByID は指定されたIDの部屋を取得する
 func (r *RoomRepository) FindByID(ctx context.Context, roomID string) (*entity.Room, error) {
-	doc, err := r.client.Collection(roomCollection).Doc(roomID).Get(ctx)
+	doc, err := getDoc(ctx, r.client.Collection(roomCollection).Doc(roomID))
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			return nil, nil
@@ -43,10 +44,102 @@ func (r *RoomRepository) FindByID(ctx context.Context, roomID string) (*entity.R
 	return &room, nil
 }
 
+// FindIDsPastDeadline は deadline が now 以前の部屋のIDを返す
+// deadline は単一フィールドの範囲クエリのため、複合インデックスは不要
+func (r *RoomRepository) FindIDsPastDeadline(ctx context.Context, now time.Time) ([]string, error) {
+	docs, err := getAllDocs(ctx, r.client.Collection(roomCollection).Where("deadline", "<=", now))
+	if err != nil {
+		return nil, err
+	}
+
+	roomIDs := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		roomIDs = append(roomIDs, doc.Ref.ID)
+	}
+	return roomIDs, nil
+}
+
+// inactiveRoomPageSize は操作のない部屋を探すときに1回のクエリで読む件数
+const inactiveRoomPageSize = 500
+
+// FindIDsInactiveSince は before 以前から操作のない部屋のIDを返す
+// lastActivityAt がない部屋（記録する前に作られた部屋）は createdAt で探す
+// どちらも単一フィールドの範囲クエリと同じフィールドの並び替えのため、複合インデックスは不要
+// 部屋が多くても1回に読む件数を抑えるため、inactiveRoomPageSize 件ずつ読む
+func (r *RoomRepository) FindIDsInactiveSince(ctx context.Context, before time.Time) ([]string, error) {
+	rooms := r.client.Collection(roomCollection)
+	var roomIDs []string
+	inactive := rooms.Where("lastActivityAt", "<=", before).
+		OrderBy("lastActivityAt", firestore.Asc).
+		Select("lastActivityAt")
+	err := forEachPage(ctx, inactive, inactiveRoomPageSize, func(doc *firestore.DocumentSnapshot) {
+		roomIDs = append(roomIDs, doc.Ref.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	legacy := rooms.Where("createdAt", "<=", before).
+		OrderBy("createdAt", firestore.Asc).
+		Select("createdAt", "lastActivityAt")
+	err = forEachPage(ctx, legacy, inactiveRoomPageSize, func(doc *firestore.DocumentSnapshot) {
+		if _, ok := doc.Data()["lastActivityAt"]; !ok {
+			roomIDs = append(roomIDs, doc.Ref.ID)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roomIDs, nil
+}
+
+// forEachPage は並び替えたクエリを pageSize 件ずつ読み、各ドキュメントで fn を呼ぶ
+// 続きは前のページの最後のドキュメントから読む（並び替えのフィールドを Select に含めること）
+func forEachPage(ctx context.Context, query firestore.Query, pageSize int, fn func(doc *firestore.DocumentSnapshot)) error {
+	page := query.Limit(pageSize)
+	for {
+		docs, err := getAllDocs(ctx, page)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			fn(doc)
+		}
+		if len(docs) < pageSize {
+			return nil
+		}
+		page = query.Limit(pageSize).StartAfter(docs[len(docs)-1])
+	}
+}
+
+// FindPublicLobbies は公開で LOBBY の部屋を作成の新しい順に最大 limit 件返す
+// status・visibility の等値と createdAt の並び替えの複合インデックス（firestore.indexes.json）を使う
+func (r *RoomRepository) FindPublicLobbies(ctx context.Context, limit int) ([]*repository.RoomWithID, error) {
+	query := r.client.Collection(roomCollection).
+		Where("status", "==", entity.RoomStatusLobby).
+		Where("visibility", "==", entity.RoomVisibilityPublic).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(limit)
+	docs, err := getAllDocs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms := make([]*repository.RoomWithID, 0, len(docs))
+	for _, doc := range docs {
+		var room entity.Room
+		if err := doc.DataTo(&room); err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, &repository.RoomWithID{RoomID: doc.Ref.ID, Room: &room})
+	}
+	return rooms, nil
+}
+
 // Create は新しい部屋を作成する
 func (r *RoomRepository) Create(ctx context.Context, room *entity.Room) (string, error) {
-	docRef, _, err := r.client.Collection(roomCollection).Add(ctx, room)
-	if err != nil {
+	docRef := r.client.Collection(roomCollection).NewDoc()
+	if err := createDoc(ctx, docRef, room); err != nil {
 		return "", err
 	}
 	return docRef.ID, nil
@@ -54,19 +147,61 @@ func (r *RoomRepository) Create(ctx context.Context, room *entity.Room) (string,
 
 // Update は部屋の情報を更新する
 func (r *RoomRepository) Update(ctx context.Context, roomID string, room *entity.Room) error {
-	_, err := r.client.Collection(roomCollection).Doc(roomID).Set(ctx, room)
-	return err
+	return setDoc(ctx, r.client.Collection(roomCollection).Doc(roomID), room)
+}
+
+// UpdateVote は votes.{userId}（第1希望）・ballots.{userId}・lockedIn.{userId}・lastActivityAt のみを更新する
+// ballot が空なら ballots.{userId} を、確定していなければ lockedIn.{userId} を削除する
+func (r *RoomRepository) UpdateVote(ctx context.Context, roomID, userID string, ballot []string, lockedIn bool, at time.Time) error {
+	var ballotValue interface{} = firestore.Delete
+	if len(ballot) > 0 {
+		ballotValue = ballot
+	}
+	var lockedInValue interface{} = firestore.Delete
+	if lockedIn && len(ballot) > 0 {
+		lockedInValue = true
+	}
+	return updateDoc(ctx, r.client.Collection(roomCollection).Doc(roomID), []firestore.Update{
+		{FieldPath: firestore.FieldPath{"votes", userID}, Value: entity.FirstChoice(ballot)},
+		{FieldPath: firestore.FieldPath{"ballots", userID}, Value: ballotValue},
+		{FieldPath: firestore.FieldPath{"lockedIn", userID}, Value: lockedInValue},
+		{Path: "lastActivityAt", Value: at},
+	})
+}
+
+// Touch は lastActivityAt のみを更新する
+func (r *RoomRepository) Touch(ctx context.Context, roomID string, at time.Time) error {
+	return updateDoc(ctx, r.client.Collection(roomCollection).Doc(roomID), []firestore.Update{
+		{Path: "lastActivityAt", Value: at},
+	})
+}
+
+// UpdateCityImageURL は lastResult.cityImageUrl のみを更新する
+func (r *RoomRepository) UpdateCityImageURL(ctx context.Context, roomID, url string) error {
+	return updateDoc(ctx, r.client.Collection(roomCollection).Doc(roomID), []firestore.Update{
+		{FieldPath: firestore.FieldPath{"lastResult", "cityImageUrl"}, Value: url},
+	})
 }
 
 // Delete は部屋を削除する
 func (r *RoomRepository) Delete(ctx context.Context, roomID string) error {
 	// サブコレクションのプレイヤーも削除
-	docs, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Documents(ctx).GetAll()
+	docs, err := getAllDocs(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Query)
 	if err != nil {
 		return err
 	}
 
+	// トランザクション内ならトランザクションで削除
+	if tx := txFromContext(ctx); tx != nil {
+		for _, doc := range docs {
+			if err := tx.Delete(doc.Ref); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(r.client.Collection(roomCollection).Doc(roomID))
+	}
+
 	batch := r.client.Batch()
 	for _, doc := range docs {
 		batch.Delete(doc.Ref)
@@ -92,8 +227,8 @@ func NewPlayerRepository(client *firestore.Client) repository.PlayerRepository {
 
 // FindByID は指定されたIDのプレイヤーを取得する
 func (r *PlayerRepository) FindByID(ctx context.Context, roomID, userID string) (*entity.Player, error) {
-	doc, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Doc(userID).Get(ctx)
+	doc, err := getDoc(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Doc(userID))
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			return nil, nil
@@ -110,8 +245,8 @@ func (r *PlayerRepository) FindByID(ctx context.Context, roomID, userID string)
 
 // FindAllByRoomID は指定された部屋の全プレイヤーを取得する
 func (r *PlayerRepository) FindAllByRoomID(ctx context.Context, roomID string) ([]*entity.Player, error) {
-	docs, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Documents(ctx).GetAll()
+	docs, err := getAllDocs(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Query)
 	if err != nil {
 		return nil, err
 	}
@@ -129,8 +264,8 @@ func (r *PlayerRepository) FindAllByRoomID(ctx context.Context, roomID string) (
 
 // FindAllWithIDsByRoomID は指定された部屋の全プレイヤーをIDと共に取得する
 func (r *PlayerRepository) FindAllWithIDsByRoomID(ctx context.Context, roomID string) ([]*repository.PlayerWithID, error) {
-	docs, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Documents(ctx).GetAll()
+	docs, err := getAllDocs(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Query)
 	if err != nil {
 		return nil, err
 	}
@@ -151,29 +286,35 @@ func (r *PlayerRepository) FindAllWithIDsByRoomID(ctx context.Context, roomID st
 
 // Create はプレイヤーを作成する
 func (r *PlayerRepository) Create(ctx context.Context, roomID, userID string, player *entity.Player) error {
-	_, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Doc(userID).Set(ctx, player)
-	return err
+	return setDoc(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Doc(userID), player)
 }
 
 // Update はプレイヤー情報を更新する
 func (r *PlayerRepository) Update(ctx context.Context, roomID, userID string, player *entity.Player) error {
-	_, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Doc(userID).Set(ctx, player)
-	return err
+	return setDoc(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Doc(userID), player)
+}
+
+// UpdateCurrentVote は currentVote（第1希望）と currentBallot のみを更新する
+func (r *PlayerRepository) UpdateCurrentVote(ctx context.Context, roomID, userID string, ballot []string) error {
+	return updateDoc(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Doc(userID), []firestore.Update{
+		{Path: "currentVote", Value: entity.FirstChoice(ballot)},
+		{Path: "currentBallot", Value: ballot},
+	})
 }
 
 // Delete はプレイヤーを削除する
 func (r *PlayerRepository) Delete(ctx context.Context, roomID, userID string) error {
-	_, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Doc(userID).Delete(ctx)
-	return err
+	return deleteDoc(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Doc(userID))
 }
 
 // CountByRoomID は指定された部屋のプレイヤー数を取得する
 func (r *PlayerRepository) CountByRoomID(ctx context.Context, roomID string) (int, error) {
-	docs, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Documents(ctx).GetAll()
+	docs, err := getAllDocs(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Query)
 	if err != nil {
 		return 0, err
 	}
@@ -182,17 +323,31 @@ func (r *PlayerRepository) CountByRoomID(ctx context.Context, roomID string) (in
 
 // ClearAllVotes は全プレイヤーの投票状態をリセットする
 func (r *PlayerRepository) ClearAllVotes(ctx context.Context, roomID string) error {
-	docs, err := r.client.Collection(roomCollection).Doc(roomID).
-		Collection(playerSubCollection).Documents(ctx).GetAll()
+	docs, err := getAllDocs(ctx, r.client.Collection(roomCollection).Doc(roomID).
+		Collection(playerSubCollection).Query)
 	if err != nil {
 		return err
 	}
 
+	// トランザクション内ならトランザクションで更新
+	if tx := txFromContext(ctx); tx != nil {
+		for _, doc := range docs {
+			if err := tx.Update(doc.Ref, []firestore.Update{
+				{Path: "currentVote", Value: ""},
+				{Path: "currentBallot", Value: nil},
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// バッチで更新
 	batch := r.client.Batch()
 	for _, doc := range docs {
 		batch.Update(doc.Ref, []firestore.Update{
 			{Path: "currentVote", Value: ""},
+			{Path: "currentBallot", Value: nil},
 		})
 	}
 
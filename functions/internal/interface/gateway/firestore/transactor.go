@@ -0,0 +1,93 @@
+package firestore
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// txKey は context に Firestore トランザクションを格納するためのキー
+type txKey struct{}
+
+// Transactor は Firestore の RunTransaction を使った Transactor の実装
+type Transactor struct {
+	client *firestore.Client
+}
+
+// NewTransactor は Transactor を作成する
+func NewTransactor(client *firestore.Client) repository.Transactor {
+	return &Transactor{
+		client: client,
+	}
+}
+
+// RunInTransaction は fn をトランザクション内で実行する
+// 同じ ctx を使ったリポジトリ操作はトランザクション経由で読み書きされる
+func (t *Transactor) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 既にトランザクション内ならそのまま参加する
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	return t.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// txFromContext は context からトランザクションを取得する（トランザクション外なら nil）
+func txFromContext(ctx context.Context) *firestore.Transaction {
+	tx, _ := ctx.Value(txKey{}).(*firestore.Transaction)
+	return tx
+}
+
+// ============================================================================
+// トランザクションの有無を吸収する読み書きヘルパー
+// ============================================================================
+
+func getDoc(ctx context.Context, ref *firestore.DocumentRef) (*firestore.DocumentSnapshot, error) {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.Get(ref)
+	}
+	return ref.Get(ctx)
+}
+
+// getAllDocs はクエリの結果を全て取得する（コレクション全体は CollectionRef.Query を渡す）
+func getAllDocs(ctx context.Context, q firestore.Query) ([]*firestore.DocumentSnapshot, error) {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.Documents(q).GetAll()
+	}
+	return q.Documents(ctx).GetAll()
+}
+
+func createDoc(ctx context.Context, ref *firestore.DocumentRef, data interface{}) error {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.Create(ref, data)
+	}
+	_, err := ref.Create(ctx, data)
+	return err
+}
+
+func setDoc(ctx context.Context, ref *firestore.DocumentRef, data interface{}) error {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.Set(ref, data)
+	}
+	_, err := ref.Set(ctx, data)
+	return err
+}
+
+func updateDoc(ctx context.Context, ref *firestore.DocumentRef, updates []firestore.Update) error {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.Update(ref, updates)
+	}
+	_, err := ref.Update(ctx, updates)
+	return err
+}
+
+func deleteDoc(ctx context.Context, ref *firestore.DocumentRef) error {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.Delete(ref)
+	}
+	_, err := ref.Delete(ctx)
+	return err
+}
@@ -0,0 +1,49 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// IdeologyRepository はメモリ上の IdeologyRepository の実装
+type IdeologyRepository struct {
+	store *Store
+}
+
+// NewIdeologyRepository は IdeologyRepository を作成する
+func NewIdeologyRepository(store *Store) repository.IdeologyRepository {
+	return &IdeologyRepository{
+		store: store,
+	}
+}
+
+// GetAll は全ての思想マスターを取得する
+func (r *IdeologyRepository) GetAll(ctx context.Context) ([]entity.MasterIdeology, error) {
+	defer r.store.lock(ctx)()
+
+	ideologies := make([]entity.MasterIdeology, 0, len(r.store.ideologies))
+	for _, id := range sortedKeys(r.store.ideologies) {
+		ideologies = append(ideologies, *mustClone(r.store.ideologies[id]))
+	}
+	return ideologies, nil
+}
+
+// FindByID は指定されたIDの思想マスターを取得する
+func (r *IdeologyRepository) FindByID(ctx context.Context, id string) (*entity.MasterIdeology, error) {
+	defer r.store.lock(ctx)()
+
+	ideology, ok := r.store.ideologies[id]
+	if !ok {
+		return nil, nil
+	}
+	return mustClone(ideology), nil
+}
+
+// GetAllIDs は全ての思想IDを取得する
+func (r *IdeologyRepository) GetAllIDs(ctx context.Context) ([]string, error) {
+	defer r.store.lock(ctx)()
+
+	return sortedKeys(r.store.ideologies), nil
+}
@@ -0,0 +1,126 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// JobQueue はメモリ上の JobQueue の実装（インスタンス内のみ）
+// ジョブはプロセスの終了で消えるため、ローカル開発・テスト・1インスタンスでの運用に使う
+// 部屋などのデータとは別に保持し、Firestore のリポジトリとも組み合わせられる
+type JobQueue struct {
+	mu   sync.Mutex
+	jobs map[string]*entity.Job
+}
+
+// NewJobQueue は空の JobQueue を作成する
+func NewJobQueue() repository.JobQueue {
+	return &JobQueue{
+		jobs: make(map[string]*entity.Job),
+	}
+}
+
+// Enqueue はジョブを登録する（同じIDのジョブが既にあれば何もしない）
+func (q *JobQueue) Enqueue(ctx context.Context, job *entity.Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.jobs[job.ID]; ok {
+		return nil
+	}
+	q.jobs[job.ID] = mustClone(job)
+	return nil
+}
+
+// Claim は実行できるジョブを実行予定時刻の早い順に最大 limit 件取り出す
+func (q *JobQueue) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	due := make([]*entity.Job, 0)
+	for _, job := range q.jobs {
+		if job.Due(now) {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].RunAt.Equal(due[j].RunAt) {
+			return due[i].RunAt.Before(due[j].RunAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*entity.Job, 0, len(due))
+	for _, job := range due {
+		job.Claim(now, lease)
+		claimed = append(claimed, mustClone(job))
+	}
+	return claimed, nil
+}
+
+// Complete はジョブを完了にする
+func (q *JobQueue) Complete(ctx context.Context, claimed *entity.Job) error {
+	return q.update(claimed, func(job *entity.Job) {
+		job.Status = entity.JobStatusDone
+	})
+}
+
+// Retry はジョブを runAt に再び実行する
+func (q *JobQueue) Retry(ctx context.Context, claimed *entity.Job, runAt time.Time, lastError string) error {
+	return q.update(claimed, func(job *entity.Job) {
+		job.Status = entity.JobStatusPending
+		job.RunAt = runAt
+		job.LastError = lastError
+	})
+}
+
+// Fail はジョブを失敗にする
+func (q *JobQueue) Fail(ctx context.Context, claimed *entity.Job, lastError string) error {
+	return q.update(claimed, func(job *entity.Job) {
+		job.Status = entity.JobStatusFailed
+		job.LastError = lastError
+	})
+}
+
+// Purge は完了・失敗してから before まで更新のないジョブを最大 limit 件削除する
+func (q *JobQueue) Purge(ctx context.Context, before time.Time, limit int) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	purged := 0
+	for id, job := range q.jobs {
+		if purged >= limit {
+			break
+		}
+		if job.Finished() && job.UpdatedAt.Before(before) {
+			delete(q.jobs, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// update は取り出したときのリースのままならジョブを書き換える
+func (q *JobQueue) update(claimed *entity.Job, mutate func(job *entity.Job)) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[claimed.ID]
+	if !ok {
+		return errNotFound
+	}
+	if !job.HeldBy(claimed) {
+		return entity.ErrJobLeaseLost
+	}
+	mutate(job)
+	job.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,75 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// PolicyRepository はメモリ上の PolicyRepository の実装
+type PolicyRepository struct {
+	store *Store
+}
+
+// NewPolicyRepository は PolicyRepository を作成する
+func NewPolicyRepository(store *Store) repository.PolicyRepository {
+	return &PolicyRepository{
+		store: store,
+	}
+}
+
+// GetAll は全ての政策マスターを取得する
+func (r *PolicyRepository) GetAll(ctx context.Context) ([]entity.MasterPolicy, error) {
+	defer r.store.lock(ctx)()
+
+	policies := make([]entity.MasterPolicy, 0, len(r.store.policies))
+	for _, id := range sortedKeys(r.store.policies) {
+		policies = append(policies, *mustClone(r.store.policies[id]))
+	}
+	return policies, nil
+}
+
+// FindByID は指定されたIDの政策マスターを取得する
+func (r *PolicyRepository) FindByID(ctx context.Context, id string) (*entity.MasterPolicy, error) {
+	defer r.store.lock(ctx)()
+
+	policy, ok := r.store.policies[id]
+	if !ok {
+		return nil, nil
+	}
+	return mustClone(policy), nil
+}
+
+// FindByIDs は指定されたIDリストの政策マスターを取得する
+func (r *PolicyRepository) FindByIDs(ctx context.Context, ids []string) ([]entity.MasterPolicy, error) {
+	defer r.store.lock(ctx)()
+
+	policies := make([]entity.MasterPolicy, 0, len(ids))
+	for _, id := range ids {
+		if policy, ok := r.store.policies[id]; ok {
+			policies = append(policies, *mustClone(policy))
+		}
+	}
+	return policies, nil
+}
+
+// GetAllIDs は全ての政策IDを取得する（デッキ作成用）
+func (r *PolicyRepository) GetAllIDs(ctx context.Context) ([]string, error) {
+	defer r.store.lock(ctx)()
+
+	return sortedKeys(r.store.policies), nil
+}
+
+// Create は政策を作成する（AI陳情で生成された政策用）
+func (r *PolicyRepository) Create(ctx context.Context, policy *entity.MasterPolicy) (string, error) {
+	defer r.store.lock(ctx)()
+
+	policyID := uuid.NewString()
+	stored := mustClone(policy)
+	stored.PolicyID = policyID
+	r.store.policies[policyID] = stored
+	return policyID, nil
+}
@@ -0,0 +1,47 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// RoomCodeRepository はメモリ上の RoomCodeRepository の実装
+type RoomCodeRepository struct {
+	store *Store
+}
+
+// NewRoomCodeRepository は RoomCodeRepository を作成する
+func NewRoomCodeRepository(store *Store) repository.RoomCodeRepository {
+	return &RoomCodeRepository{
+		store: store,
+	}
+}
+
+// Reserve は部屋コードを部屋に予約する（既に予約されていれば entity.ErrRoomCodeTaken）
+func (r *RoomCodeRepository) Reserve(ctx context.Context, code, roomID string, at time.Time) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.roomCodes[code]; ok {
+		return entity.ErrRoomCodeTaken
+	}
+	r.store.roomCodes[code] = roomID
+	return nil
+}
+
+// FindRoomID は部屋コードを予約している部屋のIDを返す（予約されていなければ空文字）
+func (r *RoomCodeRepository) FindRoomID(ctx context.Context, code string) (string, error) {
+	defer r.store.lock(ctx)()
+
+	return r.store.roomCodes[code], nil
+}
+
+// Release は部屋コードの予約を解除する
+func (r *RoomCodeRepository) Release(ctx context.Context, code string) error {
+	defer r.store.lock(ctx)()
+
+	delete(r.store.roomCodes, code)
+	return nil
+}
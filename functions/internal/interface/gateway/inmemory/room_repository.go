@@ -0,0 +1,272 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// RoomRepository はメモリ上の RoomRepository の実装
+type RoomRepository struct {
+	store *Store
+}
+
+// NewRoomRepository は RoomRepository を作成する
+func NewRoomRepository(store *Store) repository.RoomRepository {
+	return &RoomRepository{
+		store: store,
+	}
+}
+
+// FindByID は指定されたIDの部屋を取得する
+func (r *RoomRepository) FindByID(ctx context.Context, roomID string) (*entity.Room, error) {
+	defer r.store.lock(ctx)()
+
+	room, ok := r.store.rooms[roomID]
+	if !ok {
+		return nil, nil
+	}
+	return mustClone(room), nil
+}
+
+// FindIDsPastDeadline は deadline が now 以前の部屋のIDを返す（ID順）
+func (r *RoomRepository) FindIDsPastDeadline(ctx context.Context, now time.Time) ([]string, error) {
+	defer r.store.lock(ctx)()
+
+	roomIDs := make([]string, 0)
+	for _, roomID := range sortedKeys(r.store.rooms) {
+		if r.store.rooms[roomID].DeadlinePassed(now) {
+			roomIDs = append(roomIDs, roomID)
+		}
+	}
+	return roomIDs, nil
+}
+
+// FindIDsInactiveSince は before 以前から操作のない部屋のIDを返す（ID順）
+func (r *RoomRepository) FindIDsInactiveSince(ctx context.Context, before time.Time) ([]string, error) {
+	defer r.store.lock(ctx)()
+
+	roomIDs := make([]string, 0)
+	for _, roomID := range sortedKeys(r.store.rooms) {
+		if !r.store.rooms[roomID].LastActive().After(before) {
+			roomIDs = append(roomIDs, roomID)
+		}
+	}
+	return roomIDs, nil
+}
+
+// FindPublicLobbies は公開で LOBBY の部屋を作成の新しい順（同時刻ならID順）に最大 limit 件返す
+func (r *RoomRepository) FindPublicLobbies(ctx context.Context, limit int) ([]*repository.RoomWithID, error) {
+	defer r.store.lock(ctx)()
+
+	rooms := make([]*repository.RoomWithID, 0)
+	for _, roomID := range sortedKeys(r.store.rooms) {
+		room := r.store.rooms[roomID]
+		if room.Visibility == entity.RoomVisibilityPublic && room.Status == entity.RoomStatusLobby {
+			rooms = append(rooms, &repository.RoomWithID{RoomID: roomID, Room: mustClone(room)})
+		}
+	}
+	sort.SliceStable(rooms, func(i, j int) bool {
+		return rooms[i].Room.CreatedAt.After(rooms[j].Room.CreatedAt)
+	})
+	if len(rooms) > limit {
+		rooms = rooms[:limit]
+	}
+	return rooms, nil
+}
+
+// Create は新しい部屋を作成する
+func (r *RoomRepository) Create(ctx context.Context, room *entity.Room) (string, error) {
+	defer r.store.lock(ctx)()
+
+	roomID := uuid.NewString()
+	r.store.rooms[roomID] = mustClone(room)
+	return roomID, nil
+}
+
+// Update は部屋の情報を更新する（ドキュメント全体を上書き）
+func (r *RoomRepository) Update(ctx context.Context, roomID string, room *entity.Room) error {
+	defer r.store.lock(ctx)()
+
+	r.store.rooms[roomID] = mustClone(room)
+	return nil
+}
+
+// UpdateVote は votes.{userId}（第1希望）・ballots.{userId}・lockedIn.{userId}・lastActivityAt のみを更新する
+func (r *RoomRepository) UpdateVote(ctx context.Context, roomID, userID string, ballot []string, lockedIn bool, at time.Time) error {
+	defer r.store.lock(ctx)()
+
+	room, ok := r.store.rooms[roomID]
+	if !ok {
+		return errNotFound
+	}
+	room.Touch(at)
+	if len(ballot) == 0 {
+		if room.Votes == nil {
+			room.Votes = make(map[string]string)
+		}
+		room.ClearBallot(userID)
+		return nil
+	}
+	room.CastBallot(userID, append([]string(nil), ballot...))
+	if lockedIn {
+		room.LockIn(userID)
+	} else {
+		delete(room.LockedIn, userID)
+	}
+	return nil
+}
+
+// Touch は lastActivityAt のみを更新する
+func (r *RoomRepository) Touch(ctx context.Context, roomID string, at time.Time) error {
+	defer r.store.lock(ctx)()
+
+	room, ok := r.store.rooms[roomID]
+	if !ok {
+		return errNotFound
+	}
+	room.Touch(at)
+	return nil
+}
+
+// UpdateCityImageURL は lastResult.cityImageUrl のみを更新する
+func (r *RoomRepository) UpdateCityImageURL(ctx context.Context, roomID, url string) error {
+	defer r.store.lock(ctx)()
+
+	room, ok := r.store.rooms[roomID]
+	if !ok {
+		return errNotFound
+	}
+	if room.LastResult == nil {
+		room.LastResult = &entity.VoteResult{}
+	}
+	room.LastResult.CityImageURL = url
+	return nil
+}
+
+// Delete は部屋を削除する（プレイヤーも削除）
+func (r *RoomRepository) Delete(ctx context.Context, roomID string) error {
+	defer r.store.lock(ctx)()
+
+	delete(r.store.rooms, roomID)
+	delete(r.store.players, roomID)
+	return nil
+}
+
+// PlayerRepository はメモリ上の PlayerRepository の実装
+type PlayerRepository struct {
+	store *Store
+}
+
+// NewPlayerRepository は PlayerRepository を作成する
+func NewPlayerRepository(store *Store) repository.PlayerRepository {
+	return &PlayerRepository{
+		store: store,
+	}
+}
+
+// FindByID は指定されたIDのプレイヤーを取得する
+func (r *PlayerRepository) FindByID(ctx context.Context, roomID, userID string) (*entity.Player, error) {
+	defer r.store.lock(ctx)()
+
+	player, ok := r.store.players[roomID][userID]
+	if !ok {
+		return nil, nil
+	}
+	return mustClone(player), nil
+}
+
+// FindAllByRoomID は指定された部屋の全プレイヤーを取得する
+func (r *PlayerRepository) FindAllByRoomID(ctx context.Context, roomID string) ([]*entity.Player, error) {
+	defer r.store.lock(ctx)()
+
+	byUser := r.store.players[roomID]
+	players := make([]*entity.Player, 0, len(byUser))
+	for _, userID := range sortedKeys(byUser) {
+		players = append(players, mustClone(byUser[userID]))
+	}
+	return players, nil
+}
+
+// FindAllWithIDsByRoomID は指定された部屋の全プレイヤーをIDと共に取得する
+func (r *PlayerRepository) FindAllWithIDsByRoomID(ctx context.Context, roomID string) ([]*repository.PlayerWithID, error) {
+	defer r.store.lock(ctx)()
+
+	byUser := r.store.players[roomID]
+	players := make([]*repository.PlayerWithID, 0, len(byUser))
+	for _, userID := range sortedKeys(byUser) {
+		players = append(players, &repository.PlayerWithID{
+			UserID: userID,
+			Player: mustClone(byUser[userID]),
+		})
+	}
+	return players, nil
+}
+
+// Create はプレイヤーを作成する
+func (r *PlayerRepository) Create(ctx context.Context, roomID, userID string, player *entity.Player) error {
+	defer r.store.lock(ctx)()
+
+	r.setPlayer(roomID, userID, player)
+	return nil
+}
+
+// Update はプレイヤー情報を更新する
+func (r *PlayerRepository) Update(ctx context.Context, roomID, userID string, player *entity.Player) error {
+	defer r.store.lock(ctx)()
+
+	r.setPlayer(roomID, userID, player)
+	return nil
+}
+
+// UpdateCurrentVote は currentVote（第1希望）と currentBallot のみを更新する
+func (r *PlayerRepository) UpdateCurrentVote(ctx context.Context, roomID, userID string, ballot []string) error {
+	defer r.store.lock(ctx)()
+
+	player, ok := r.store.players[roomID][userID]
+	if !ok {
+		return errNotFound
+	}
+	player.Vote(append([]string(nil), ballot...))
+	return nil
+}
+
+// Delete はプレイヤーを削除する
+func (r *PlayerRepository) Delete(ctx context.Context, roomID, userID string) error {
+	defer r.store.lock(ctx)()
+
+	delete(r.store.players[roomID], userID)
+	return nil
+}
+
+// ClearAllVotes は全プレイヤーの投票状態をリセットする
+func (r *PlayerRepository) ClearAllVotes(ctx context.Context, roomID string) error {
+	defer r.store.lock(ctx)()
+
+	for _, player := range r.store.players[roomID] {
+		player.ClearVote()
+	}
+	return nil
+}
+
+// CountByRoomID は指定された部屋のプレイヤー数を取得する
+func (r *PlayerRepository) CountByRoomID(ctx context.Context, roomID string) (int, error) {
+	defer r.store.lock(ctx)()
+
+	return len(r.store.players[roomID]), nil
+}
+
+// setPlayer はプレイヤーを保存する（ロックは呼び出し元で取得する）
+func (r *PlayerRepository) setPlayer(roomID, userID string, player *entity.Player) {
+	byUser, ok := r.store.players[roomID]
+	if !ok {
+		byUser = make(map[string]*entity.Player)
+		r.store.players[roomID] = byUser
+	}
+	byUser[userID] = mustClone(player)
+}
@@ -0,0 +1,127 @@
+// Package inmemory はリポジトリをメモリ上のマップで実装する
+// Firestore（エミュレータ含む）なしでサーバーやテストを動かすために使う
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+// errNotFound は更新対象のドキュメントが存在しない場合のエラー（Firestore の NotFound 相当）
+var errNotFound = errors.New("inmemory: document not found")
+
+// Store は全リポジトリが共有するデータ置き場
+// 値は保存時・取得時にコピーし、呼び出し元とマップやポインタを共有しない（Firestore と同じ値のセマンティクス）
+type Store struct {
+	mu sync.Mutex
+
+	rooms      map[string]*entity.Room
+	players    map[string]map[string]*entity.Player // roomID -> userID -> Player
+	roomCodes  map[string]string                    // code -> roomID
+	policies   map[string]*entity.MasterPolicy
+	ideologies map[string]*entity.MasterIdeology
+}
+
+// NewStore はデフォルトの政策・思想マスターを投入した Store を作成する
+func NewStore() *Store {
+	s := &Store{
+		rooms:      make(map[string]*entity.Room),
+		players:    make(map[string]map[string]*entity.Player),
+		roomCodes:  make(map[string]string),
+		policies:   make(map[string]*entity.MasterPolicy),
+		ideologies: make(map[string]*entity.MasterIdeology),
+	}
+	for _, policy := range entity.GetDefaultPolicies() {
+		s.policies[policy.PolicyID] = mustClone(&policy)
+	}
+	for _, ideology := range entity.GetDefaultIdeologies() {
+		s.ideologies[ideology.IdeologyID] = mustClone(&ideology)
+	}
+	return s
+}
+
+// txKey は context にトランザクション中の Store を格納するためのキー
+type txKey struct{}
+
+// inTransaction は ctx がこの Store のトランザクション内かを判定する
+func (s *Store) inTransaction(ctx context.Context) bool {
+	tx, _ := ctx.Value(txKey{}).(*Store)
+	return tx == s
+}
+
+// lock はデータへのアクセスを排他する
+// トランザクション内では既にロックを保持しているため何もしない
+func (s *Store) lock(ctx context.Context) func() {
+	if s.inTransaction(ctx) {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// storeSnapshot はロールバック用に複製した Store の状態
+type storeSnapshot struct {
+	rooms     map[string]*entity.Room
+	players   map[string]map[string]*entity.Player
+	roomCodes map[string]string
+}
+
+// snapshot はロールバック用に部屋・プレイヤー・部屋コードの状態を複製する（マスターデータは対象外）
+func (s *Store) snapshot() storeSnapshot {
+	rooms := make(map[string]*entity.Room, len(s.rooms))
+	for id, room := range s.rooms {
+		rooms[id] = mustClone(room)
+	}
+	players := make(map[string]map[string]*entity.Player, len(s.players))
+	for roomID, byUser := range s.players {
+		copied := make(map[string]*entity.Player, len(byUser))
+		for userID, player := range byUser {
+			copied[userID] = mustClone(player)
+		}
+		players[roomID] = copied
+	}
+	roomCodes := make(map[string]string, len(s.roomCodes))
+	for code, roomID := range s.roomCodes {
+		roomCodes[code] = roomID
+	}
+	return storeSnapshot{rooms: rooms, players: players, roomCodes: roomCodes}
+}
+
+// restore は snapshot で複製した状態に戻す
+func (s *Store) restore(snap storeSnapshot) {
+	s.rooms = snap.rooms
+	s.players = snap.players
+	s.roomCodes = snap.roomCodes
+}
+
+// sortedKeys はマップのキーを昇順に並べて返す（Firestore のドキュメントID順に合わせる）
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mustClone は JSON を経由して値を深くコピーする
+// エンティティは全て JSON で表現できるため、失敗はプログラムの誤りとして panic する
+func mustClone[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic("inmemory: failed to clone value: " + err.Error())
+	}
+	var copied T
+	if err := json.Unmarshal(data, &copied); err != nil {
+		panic("inmemory: failed to clone value: " + err.Error())
+	}
+	return &copied
+}
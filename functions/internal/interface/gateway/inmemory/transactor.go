@@ -0,0 +1,38 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// Transactor はメモリ上の Store に対する Transactor の実装
+// トランザクションは Store 全体のロックで直列化し、fn がエラーを返した場合は開始時点の状態に戻す
+type Transactor struct {
+	store *Store
+}
+
+// NewTransactor は Transactor を作成する
+func NewTransactor(store *Store) repository.Transactor {
+	return &Transactor{
+		store: store,
+	}
+}
+
+// RunInTransaction は fn をトランザクション内で実行する
+func (t *Transactor) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 既にトランザクション内ならそのまま参加する
+	if t.store.inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	snap := t.store.snapshot()
+	if err := fn(context.WithValue(ctx, txKey{}, t.store)); err != nil {
+		t.store.restore(snap)
+		return err
+	}
+	return nil
+}
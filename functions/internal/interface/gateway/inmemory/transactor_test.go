@@ -0,0 +1,67 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestTransactor_RollbackOnError(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	rooms := NewRoomRepository(store)
+	players := NewPlayerRepository(store)
+	codes := NewRoomCodeRepository(store)
+	tx := NewTransactor(store)
+
+	roomID, err := rooms.Create(ctx, entity.NewRoom("host", entity.DefaultRoomSettings()))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	errAbort := errors.New("abort")
+	err = tx.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := rooms.UpdateVote(ctx, roomID, "host", []string{"policy_001"}, false, time.Now()); err != nil {
+			return err
+		}
+		if err := players.Create(ctx, roomID, "guest", entity.NewPlayer("guest", false, nil)); err != nil {
+			return err
+		}
+		if err := codes.Reserve(ctx, "ABCD", roomID, time.Now()); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("RunInTransaction() = %v, want %v", err, errAbort)
+	}
+
+	room, _ := rooms.FindByID(ctx, roomID)
+	if room.Votes["host"] != "" {
+		t.Errorf("votes がロールバックされていない: %v", room.Votes)
+	}
+	if p, _ := players.FindByID(ctx, roomID, "guest"); p != nil {
+		t.Error("プレイヤーの作成がロールバックされていない")
+	}
+	if id, _ := codes.FindRoomID(ctx, "ABCD"); id != "" {
+		t.Error("部屋コードの予約がロールバックされていない")
+	}
+}
+
+func TestRoomRepository_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	rooms := NewRoomRepository(store)
+
+	roomID, _ := rooms.Create(ctx, entity.NewRoom("host", entity.DefaultRoomSettings()))
+	room, _ := rooms.FindByID(ctx, roomID)
+	room.Votes["host"] = "policy_001"
+
+	stored, _ := rooms.FindByID(ctx, roomID)
+	if stored.Votes["host"] != "" {
+		t.Error("取得した部屋の変更が保存済みの部屋に反映された")
+	}
+}
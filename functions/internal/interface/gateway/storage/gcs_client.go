@@ -2,11 +2,13 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 
 	"github.com/techworld-hackathon/functions/internal/domain/service"
 )
@@ -49,7 +51,7 @@ var _ service.ImageStorage = (*GCSClient)(nil)
 // UploadCityImage は街の画像をGCSにアップロードし、signed URLを返す
 func (c *GCSClient) UploadCityImage(ctx context.Context, roomID string, turn int, imageData []byte) (string, error) {
 	// オブジェクトパスを生成: city_images/{roomID}/turn_{turn}.png
-	objectPath := fmt.Sprintf("city_images/%s/turn_%d.png", roomID, turn)
+	objectPath := fmt.Sprintf("%sturn_%d.png", cityImagePrefix(roomID), turn)
 
 	// バケットとオブジェクトへの参照を取得
 	bucket := c.client.Bucket(c.bucketName)
@@ -84,6 +86,30 @@ func (c *GCSClient) UploadCityImage(ctx context.Context, roomID string, turn int
 	return signedURL, nil
 }
 
+// DeleteCityImages は city_images/{roomID}/ 以下のオブジェクトを全て削除する
+func (c *GCSClient) DeleteCityImages(ctx context.Context, roomID string) error {
+	bucket := c.client.Bucket(c.bucketName)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: cityImagePrefix(roomID)})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list city images: %w", err)
+		}
+		// 同時に削除された場合は削除済みとして扱う
+		if err := bucket.Object(attrs.Name).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return fmt.Errorf("failed to delete %s: %w", attrs.Name, err)
+		}
+	}
+}
+
+// cityImagePrefix は部屋の街の画像を置くパスの接頭辞を返す
+func cityImagePrefix(roomID string) string {
+	return fmt.Sprintf("city_images/%s/", roomID)
+}
+
 // Close はGCSクライアントを閉じる
 func (c *GCSClient) Close() error {
 	return c.client.Close()
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// identityContextKey はリクエスト元をコンテキストに入れるためのキー
+type identityContextKey struct{}
+
+// Authenticate はリクエストのトークンを検証し、リクエスト元をコンテキストに入れるミドルウェア
+// トークンは Authorization: Bearer {token} で送る（EventSource 向けにクエリパラメータ token も受け付ける）
+// トークンがなければそのまま次に渡し、操作を伴うハンドラーが 401 を返す。トークンが不正なら 401 を返す
+func (h *Handler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if token == "" || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		identity, err := h.tokenVerifier.VerifyToken(r.Context(), token)
+		if err != nil {
+			if !errors.Is(err, entity.ErrUnauthenticated) {
+				slog.Error("Authenticate: トークンの検証に失敗", slog.Any("error", err))
+			}
+			handleError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityContextKey{}, identity)))
+	})
+}
+
+// bearerToken はリクエストのトークンを返す（なければ空文字）
+func bearerToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+	return r.URL.Query().Get("token")
+}
+
+// identityFrom はコンテキストからリクエスト元を取得する（認証されていなければ nil）
+func identityFrom(ctx context.Context) *service.Identity {
+	identity, _ := ctx.Value(identityContextKey{}).(*service.Identity)
+	return identity
+}
+
+// requirePlayer は部屋で操作するプレイヤーのIDをセッションから取得する
+// セッションがなければ 401、別の部屋のセッションなら 403 を返して false を返す
+func requirePlayer(w http.ResponseWriter, r *http.Request, roomID string) (string, bool) {
+	identity := identityFrom(r.Context())
+	if identity == nil {
+		handleError(w, entity.ErrUnauthenticated)
+		return "", false
+	}
+	if !identity.CanActIn(roomID) {
+		handleError(w, entity.ErrSessionRoomMismatch)
+		return "", false
+	}
+	return identity.UserID, true
+}
+
+// viewerFrom は秘匿情報を含めるプレイヤーのIDをセッションから取得する
+// セッションがない・別の部屋のセッションなら空文字（公開情報のみ返す）
+func viewerFrom(r *http.Request, roomID string) string {
+	identity := identityFrom(r.Context())
+	if identity == nil || !identity.CanActIn(roomID) {
+		return ""
+	}
+	return identity.UserID
+}
+
+// issueSession は部屋を作成・参加したプレイヤーのセッショントークンをレスポンスに加える
+func (h *Handler) issueSession(res map[string]interface{}, roomID, playerID string) error {
+	session, err := h.sessionIssuer.IssueSession(roomID, playerID)
+	if err != nil {
+		return err
+	}
+	res["sessionToken"] = session.Token
+	res["sessionExpiresAt"] = session.ExpiresAt
+	return nil
+}
+
+// newPlayerID は部屋を作成・参加するプレイヤーのIDを返す
+// Firebase の ID トークンで認証されていれば UID、そうでなければ新しい UUID を使う
+func newPlayerID(r *http.Request) string {
+	if identity := identityFrom(r.Context()); identity != nil && identity.RoomID == "" {
+		return identity.UserID
+	}
+	return uuid.New().String()
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/interface/gateway/auth"
+)
+
+func TestAuthenticate_RequirePlayer(t *testing.T) {
+	env := newTestEnv(t)
+	host := env.createRoom(t)
+	other := env.createRoom(t)
+
+	// 別の鍵・期限切れのトークン（署名や期限が正しくないトークン）
+	forged, err := auth.NewSessionManager(auth.SessionConfig{Secret: []byte("another-secret-0123456789abcdefghij"), TTL: time.Hour}).IssueSession(host.roomID, host.playerID)
+	if err != nil {
+		t.Fatalf("IssueSession: %v", err)
+	}
+	expired, err := auth.NewSessionManager(auth.SessionConfig{Secret: testSecret, TTL: -time.Minute}).IssueSession(host.roomID, host.playerID)
+	if err != nil {
+		t.Fatalf("IssueSession: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		header     string // Authorization ヘッダー
+		wantStatus int
+	}{
+		{name: "トークンなし", wantStatus: http.StatusUnauthorized},
+		{name: "Bearer でないヘッダーはトークンなしとして扱う", header: host.token, wantStatus: http.StatusUnauthorized},
+		{name: "不正なトークン", header: "Bearer not-a-token", wantStatus: http.StatusUnauthorized},
+		{name: "別の鍵で署名したトークン", header: "Bearer " + forged.Token, wantStatus: http.StatusUnauthorized},
+		{name: "期限切れのトークン", header: "Bearer " + expired.Token, wantStatus: http.StatusUnauthorized},
+		{name: "別の部屋のセッション", header: "Bearer " + other.token, wantStatus: http.StatusForbidden},
+		{name: "この部屋のセッション", header: "Bearer " + host.token, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+host.roomID+"/ready", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			env.h.Authenticate(http.HandlerFunc(env.h.ToggleReady)).ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body = %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+
+	// 操作できたのはこの部屋のセッションの1回だけ（参加時は Ready のため、1回の切り替えで Ready でなくなる）
+	if env.player(t, host.roomID, host.playerID).IsReady {
+		t.Error("Ready の切り替えが1回になっていない")
+	}
+}
+
+func TestAuthenticate_InvalidTokenOnReadRequest(t *testing.T) {
+	env := newTestEnv(t)
+	host := env.createRoom(t)
+
+	// 読み取りでもトークンが不正なら公開情報に落とさず 401 を返す
+	rec := env.serve(t, env.h.GetRoom, http.MethodGet, "/api/rooms/"+host.roomID, "not-a-token", nil)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
+
+// readSnapshot は SSE に接続して最初に送られるスナップショットを読み取る
+func readSnapshot(t *testing.T, env *testEnv, roomID, token string) (int, *RoomResponse) {
+	t.Helper()
+	server := httptest.NewServer(env.h.Authenticate(http.HandlerFunc(env.h.RoomEvents)))
+	defer server.Close()
+
+	target := server.URL + "/api/rooms/" + roomID + "/events"
+	if token != "" {
+		target += "?token=" + url.QueryEscape(token)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var snapshot RoomResponse
+			decodeJSON(t, data, &snapshot)
+			return resp.StatusCode, &snapshot
+		}
+	}
+	t.Fatalf("スナップショットを受信できなかった: %v", scanner.Err())
+	return 0, nil
+}
+
+func TestRoomEvents_TokenQueryParameter(t *testing.T) {
+	env := newTestEnv(t)
+	host := env.createRoom(t)
+	guest := env.join(t, host.roomID, "ゲスト")
+	env.vote(t, host.roomID, guest.playerID, "policy_a")
+	other := env.createRoom(t)
+
+	// EventSource はヘッダーを送れないため ?token= のセッションで本人の秘匿情報を含める
+	status, snapshot := readSnapshot(t, env, host.roomID, guest.token)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	assertRedacted(t, env, host.roomID, guest.playerID, snapshot.Players)
+
+	// トークンなし・別の部屋のセッションでは公開情報のみ
+	for name, token := range map[string]string{"トークンなし": "", "別の部屋のセッション": other.token} {
+		status, snapshot := readSnapshot(t, env, host.roomID, token)
+		if status != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", name, status)
+		}
+		for _, p := range snapshot.Players {
+			if p.IsMe || p.Ideology != nil || p.CurrentVote != "" || p.CurrentBallot != nil {
+				t.Errorf("%s: %s の秘匿情報が含まれている: %+v", name, p.PlayerID, p)
+			}
+		}
+	}
+
+	// 不正なトークンは接続させない
+	if status, _ := readSnapshot(t, env, host.roomID, "not-a-token"); status != http.StatusUnauthorized {
+		t.Errorf("不正なトークン: status = %d, want 401", status)
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+const (
+	// sseKeepAliveInterval はプロキシによる切断を防ぐためのコメント送信間隔
+	sseKeepAliveInterval = 25 * time.Second
+	// sseSnapshotEvent は接続直後に送る部屋のスナップショットのイベント名
+	sseSnapshotEvent = "SNAPSHOT"
+)
+
+// RoomEvents は部屋のイベントを Server-Sent Events で配信する
+// GET /api/rooms/{roomId}/events?token={sessionToken}
+// EventSource はヘッダーを送れないため、セッショントークンはクエリパラメータで渡せる（秘匿情報は本人分のみ）
+// 1. 接続直後に部屋のスナップショット（GET /api/rooms/{roomId} と同じ形式）を送信
+// 2. 以降、ユースケースで発生したイベントを順次送信
+func (h *Handler) RoomEvents(w http.ResponseWriter, r *http.Request) {
+	slog.Info("RoomEvents: リクエスト受信")
+
+	if r.Method != http.MethodGet {
+		slog.Warn("RoomEvents: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/events")
+	if roomID == "" {
+		slog.Warn("RoomEvents: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+	viewerID := viewerFrom(r, roomID)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		slog.Error("RoomEvents: ストリーミング非対応", slog.String("roomId", roomID))
+		respondError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	// 先に購読してからスナップショットを取得する（取りこぼし防止）
+	events, unsubscribe := h.eventSubscriber.Subscribe(r.Context(), roomID)
+	defer unsubscribe()
+
+	snapshot, err := h.getRoomUC.Execute(r.Context(), usecase.GetRoomInput{
+		RoomID: roomID,
+	})
+	if err != nil {
+		slog.Error("RoomEvents: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeSSE(w, sseSnapshotEvent, newRoomResponse(roomID, snapshot, viewerID)); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	slog.Info("RoomEvents: 配信開始", slog.String("roomId", roomID))
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			slog.Info("RoomEvents: クライアント切断", slog.String("roomId", roomID))
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSE(w, string(event.Type), event); err != nil {
+				slog.Warn("RoomEvents: 送信失敗",
+					slog.String("roomId", roomID),
+					slog.Any("error", err))
+				return
+			}
+			flusher.Flush()
+			if event.Type == entity.RoomEventGameFinished {
+				// ゲーム終了後は以降のイベントがないため切断する
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSE は Server-Sent Events 形式でイベントを書き込む
+func writeSSE(w http.ResponseWriter, eventName string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventName, payload)
+	return err
+}
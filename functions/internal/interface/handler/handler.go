@@ -3,13 +3,15 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 
-	"github.com/google/uuid"
 	"github.com/newmo-oss/ergo"
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
 	"github.com/techworld-hackathon/functions/internal/usecase"
 )
 
@@ -17,37 +19,88 @@ import (
 type Handler struct {
 	createRoomUC     *usecase.CreateRoomUseCase
 	joinRoomUC       *usecase.JoinRoomUseCase
+	rejoinRoomUC     *usecase.RejoinRoomUseCase
 	leaveRoomUC      *usecase.LeaveRoomUseCase
 	toggleReadyUC    *usecase.ToggleReadyUseCase
+	updateSettingsUC *usecase.UpdateRoomSettingsUseCase
 	startGameUC      *usecase.StartGameUseCase
 	voteUC           *usecase.VoteUseCase
+	retractVoteUC    *usecase.RetractVoteUseCase
 	resolveVoteUC    *usecase.ResolveVoteUseCase
+	decideTieUC      *usecase.DecideTieUseCase
 	nextTurnUC       *usecase.NextTurnUseCase
 	submitPetitionUC *usecase.SubmitPetitionUseCase
+	getFinalResultUC *usecase.GetFinalResultUseCase
+	getRoomUC        *usecase.GetRoomUseCase
+	getPlayersUC     *usecase.GetPlayersUseCase
+	getGraveyardUC   *usecase.GetGraveyardUseCase
+	findRoomByCodeUC *usecase.FindRoomByCodeUseCase
+	listLobbiesUC    *usecase.ListLobbiesUseCase
+	quickMatchUC     *usecase.QuickMatchUseCase
+	updateAccessUC   *usecase.UpdateRoomAccessUseCase
+	createInviteUC   *usecase.CreateInviteUseCase
+	eventSubscriber  service.EventSubscriber
+	sessionIssuer    service.SessionIssuer
+	tokenVerifier    service.TokenVerifier
+	allowSeed        bool // 部屋作成で seed を受け付けるか（ローカル開発のみ）
 }
 
 // NewHandler は Handler を作成する
 func NewHandler(
 	createRoomUC *usecase.CreateRoomUseCase,
 	joinRoomUC *usecase.JoinRoomUseCase,
+	rejoinRoomUC *usecase.RejoinRoomUseCase,
 	leaveRoomUC *usecase.LeaveRoomUseCase,
 	toggleReadyUC *usecase.ToggleReadyUseCase,
+	updateSettingsUC *usecase.UpdateRoomSettingsUseCase,
 	startGameUC *usecase.StartGameUseCase,
 	voteUC *usecase.VoteUseCase,
+	retractVoteUC *usecase.RetractVoteUseCase,
 	resolveVoteUC *usecase.ResolveVoteUseCase,
+	decideTieUC *usecase.DecideTieUseCase,
 	nextTurnUC *usecase.NextTurnUseCase,
 	submitPetitionUC *usecase.SubmitPetitionUseCase,
+	getFinalResultUC *usecase.GetFinalResultUseCase,
+	getRoomUC *usecase.GetRoomUseCase,
+	getPlayersUC *usecase.GetPlayersUseCase,
+	getGraveyardUC *usecase.GetGraveyardUseCase,
+	findRoomByCodeUC *usecase.FindRoomByCodeUseCase,
+	listLobbiesUC *usecase.ListLobbiesUseCase,
+	quickMatchUC *usecase.QuickMatchUseCase,
+	updateAccessUC *usecase.UpdateRoomAccessUseCase,
+	createInviteUC *usecase.CreateInviteUseCase,
+	eventSubscriber service.EventSubscriber,
+	sessionIssuer service.SessionIssuer,
+	tokenVerifier service.TokenVerifier,
+	allowSeed bool,
 ) *Handler {
 	return &Handler{
 		createRoomUC:     createRoomUC,
 		joinRoomUC:       joinRoomUC,
+		rejoinRoomUC:     rejoinRoomUC,
 		leaveRoomUC:      leaveRoomUC,
 		toggleReadyUC:    toggleReadyUC,
+		updateSettingsUC: updateSettingsUC,
 		startGameUC:      startGameUC,
 		voteUC:           voteUC,
+		retractVoteUC:    retractVoteUC,
 		resolveVoteUC:    resolveVoteUC,
+		decideTieUC:      decideTieUC,
 		nextTurnUC:       nextTurnUC,
 		submitPetitionUC: submitPetitionUC,
+		getFinalResultUC: getFinalResultUC,
+		getRoomUC:        getRoomUC,
+		getPlayersUC:     getPlayersUC,
+		getGraveyardUC:   getGraveyardUC,
+		findRoomByCodeUC: findRoomByCodeUC,
+		listLobbiesUC:    listLobbiesUC,
+		quickMatchUC:     quickMatchUC,
+		updateAccessUC:   updateAccessUC,
+		createInviteUC:   createInviteUC,
+		eventSubscriber:  eventSubscriber,
+		sessionIssuer:    sessionIssuer,
+		tokenVerifier:    tokenVerifier,
+		allowSeed:        allowSeed,
 	}
 }
 
@@ -57,39 +110,38 @@ func NewHandler(
 
 // CreateRoomRequest は部屋作成リクエスト
 type CreateRoomRequest struct {
-	DisplayName string `json:"displayName"`
+	DisplayName string               `json:"displayName"`
+	Seed        *int64               `json:"seed,omitempty"`       // 部屋の乱数の種（不具合の再現用。ローカル開発のみ、省略時はランダム）
+	Visibility  string               `json:"visibility,omitempty"` // PUBLIC ならロビーの一覧に表示する（省略時は PRIVATE）
+	Settings    *RoomSettingsRequest `json:"settings,omitempty"`   // 省略した項目はデフォルト値
+	Password    string               `json:"password,omitempty"`   // 参加にパスワードを求める（省略時はなし）
+	InviteOnly  bool                 `json:"inviteOnly,omitempty"` // ホストが発行した招待がないと参加できない
 }
 
 // JoinRoomRequest は部屋参加リクエスト
 type JoinRoomRequest struct {
 	DisplayName string `json:"displayName"`
+	Password    string `json:"password,omitempty"` // パスワードを設定した部屋の場合
+	Invite      string `json:"invite,omitempty"`   // ホストが発行した招待トークン（パスワードの代わりになる）
 }
 
-// LeaveRoomRequest は部屋退出リクエスト
-type LeaveRoomRequest struct {
-	PlayerID string `json:"playerId"`
-}
-
-// ReadyRequest はReady状態トグルリクエスト
-type ReadyRequest struct {
-	PlayerID string `json:"playerId"`
-}
-
-// StartGameRequest はゲーム開始リクエスト
-type StartGameRequest struct {
-	PlayerID string `json:"playerId"`
+// RejoinRoomRequest は部屋への再参加リクエスト
+// 再参加コードを省略した場合はセッショントークンのプレイヤーとして戻る
+type RejoinRoomRequest struct {
+	RejoinCode string `json:"rejoinCode,omitempty"`
 }
 
 // VoteRequest は投票リクエスト
+// 1つだけ選ぶ場合は policyId、複数選ぶ場合は policyIds（APPROVAL は賛成する政策、INSTANT_RUNOFF・BORDA は希望順）
 type VoteRequest struct {
-	PlayerID string `json:"playerId"`
-	PolicyID string `json:"policyId"`
+	PolicyID  string   `json:"policyId,omitempty"`
+	PolicyIDs []string `json:"policyIds,omitempty"`
+	LockIn    bool     `json:"lockIn,omitempty"` // 投票と同時に確定する
 }
 
 // PetitionRequest は陳情リクエスト
 type PetitionRequest struct {
-	PlayerID string `json:"playerId"`
-	Text     string `json:"text"`
+	Text string `json:"text"`
 }
 
 // ============================================================================
@@ -121,8 +173,15 @@ func (h *Handler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// プレイヤーIDを生成
-	playerID := uuid.New().String()
+	// seed を指定できると山札の並び・同数時の選択を操作できるため、ローカル開発以外では受け付けない
+	if req.Seed != nil && !h.allowSeed {
+		slog.Warn("CreateRoom: seedの指定は無効")
+		respondError(w, http.StatusBadRequest, "seed is only accepted in local development")
+		return
+	}
+
+	// プレイヤーIDを生成（Firebase の ID トークンで認証されていれば UID）
+	playerID := newPlayerID(r)
 	slog.Info("CreateRoom: プレイヤーID生成",
 		slog.String("playerId", playerID),
 		slog.String("displayName", req.DisplayName))
@@ -130,6 +189,11 @@ func (h *Handler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 	output, err := h.createRoomUC.Execute(r.Context(), usecase.CreateRoomInput{
 		UserID:      playerID,
 		DisplayName: req.DisplayName,
+		Settings:    req.Settings.toInput(),
+		Seed:        req.Seed,
+		Visibility:  entity.RoomVisibility(req.Visibility),
+		Password:    req.Password,
+		InviteOnly:  req.InviteOnly,
 	})
 	if err != nil {
 		slog.Error("CreateRoom: ユースケース実行失敗", slog.Any("error", err))
@@ -137,14 +201,27 @@ func (h *Handler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	res := map[string]interface{}{
+		"roomId":      output.RoomID,
+		"roomCode":    output.RoomCode,
+		"status":      output.Status,
+		"visibility":  output.Visibility,
+		"hasPassword": output.HasPassword,
+		"inviteOnly":  output.InviteOnly,
+		"playerId":    output.PlayerID,
+		"rejoinCode":  output.RejoinCode,
+		"settings":    output.Settings,
+	}
+	if err := h.issueSession(res, output.RoomID, output.PlayerID); err != nil {
+		slog.Error("CreateRoom: セッション発行失敗", slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
 	slog.Info("CreateRoom: 部屋作成成功",
 		slog.String("roomId", output.RoomID),
 		slog.String("playerId", output.PlayerID))
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"roomId":   output.RoomID,
-		"status":   output.Status,
-		"playerId": output.PlayerID,
-	})
+	respondJSON(w, http.StatusOK, res)
 }
 
 // JoinRoom は部屋に参加する
@@ -166,48 +243,146 @@ func (h *Handler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.joinRoom(w, r, usecase.JoinRoomInput{RoomID: roomID})
+}
+
+// joinRoom はリクエストボディの表示名で部屋に参加し、セッショントークンを発行する
+// input には部屋ID か部屋コードを設定して呼び出す（JoinRoom・JoinRoomByCode で共通）
+func (h *Handler) joinRoom(w http.ResponseWriter, r *http.Request, input usecase.JoinRoomInput) {
+	target := slog.Group("room", slog.String("roomId", input.RoomID), slog.String("roomCode", input.RoomCode))
+
 	// リクエストボディをパース
 	var req JoinRoomRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		slog.Error("JoinRoom: リクエストボディのパース失敗",
-			slog.String("roomId", roomID),
+			target,
 			slog.Any("error", err))
 		respondError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	if req.DisplayName == "" {
-		slog.Warn("JoinRoom: displayNameが空", slog.String("roomId", roomID))
+		slog.Warn("JoinRoom: displayNameが空", target)
 		respondError(w, http.StatusBadRequest, "displayName is required")
 		return
 	}
 
-	// プレイヤーIDを生成
-	playerID := uuid.New().String()
+	// プレイヤーIDを生成（Firebase の ID トークンで認証されていれば UID）
+	input.UserID = newPlayerID(r)
+	input.DisplayName = req.DisplayName
+	input.Password = req.Password
+	input.InviteToken = req.Invite
 	slog.Info("JoinRoom: 参加処理開始",
-		slog.String("roomId", roomID),
-		slog.String("playerId", playerID),
+		target,
+		slog.String("playerId", input.UserID),
 		slog.String("displayName", req.DisplayName))
 
-	output, err := h.joinRoomUC.Execute(r.Context(), usecase.JoinRoomInput{
-		RoomID:      roomID,
-		UserID:      playerID,
-		DisplayName: req.DisplayName,
-	})
+	output, err := h.joinRoomUC.Execute(r.Context(), input)
 	if err != nil {
 		slog.Error("JoinRoom: ユースケース実行失敗",
-			slog.String("roomId", roomID),
+			target,
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	res := map[string]interface{}{
+		"roomId":     output.RoomID,
+		"playerId":   output.PlayerID,
+		"rejoinCode": output.RejoinCode,
+	}
+	if err := h.issueSession(res, output.RoomID, output.PlayerID); err != nil {
+		slog.Error("JoinRoom: セッション発行失敗",
+			slog.String("roomId", output.RoomID),
 			slog.Any("error", err))
 		handleError(w, err)
 		return
 	}
 
 	slog.Info("JoinRoom: 参加成功",
-		slog.String("roomId", roomID),
+		slog.String("roomId", output.RoomID),
 		slog.String("playerId", output.PlayerID))
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"playerId": output.PlayerID,
+	respondJSON(w, http.StatusOK, res)
+}
+
+// RejoinRoom は接続が切れたプレイヤーを同じ席に戻す
+// POST /api/rooms/{roomId}/rejoin
+// 再参加コード（参加時に発行）か、この部屋のセッショントークンで席を探し、新しいセッショントークンを発行する
+func (h *Handler) RejoinRoom(w http.ResponseWriter, r *http.Request) {
+	slog.Info("RejoinRoom: リクエスト受信")
+
+	if r.Method != http.MethodPost {
+		slog.Warn("RejoinRoom: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/rejoin")
+	if roomID == "" {
+		slog.Warn("RejoinRoom: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	// リクエストボディをパース（セッショントークンで戻る場合は空でよい）
+	var req RejoinRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		slog.Error("RejoinRoom: リクエストボディのパース失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// 再参加コードがなければセッションからプレイヤーを取得
+	var playerID string
+	if req.RejoinCode == "" {
+		var ok bool
+		if playerID, ok = requirePlayer(w, r, roomID); !ok {
+			return
+		}
+	}
+
+	output, err := h.rejoinRoomUC.Execute(r.Context(), usecase.RejoinRoomInput{
+		RoomID:     roomID,
+		UserID:     playerID,
+		RejoinCode: req.RejoinCode,
 	})
+	if err != nil {
+		slog.Error("RejoinRoom: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	// 本人の再参加のため秘匿情報（ideology, currentVote, currentBallot）も返す
+	room, player := output.Room, output.Player
+	res := map[string]interface{}{
+		"playerId":      output.PlayerID,
+		"displayName":   player.DisplayName,
+		"isHost":        player.IsHost,
+		"status":        room.Status,
+		"turn":          room.Turn,
+		"ideology":      player.Ideology,
+		"currentVote":   player.CurrentVote,
+		"currentBallot": player.CurrentBallot,
+		"isLockedIn":    room.IsLockedIn(output.PlayerID),
+		"petitionsLeft": player.RemainingPetitions(room.Settings.PetitionsPerPlayer),
+	}
+	if err := h.issueSession(res, roomID, output.PlayerID); err != nil {
+		slog.Error("RejoinRoom: セッション発行失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	slog.Info("RejoinRoom: 再参加成功",
+		slog.String("roomId", roomID),
+		slog.String("playerId", output.PlayerID))
+	respondJSON(w, http.StatusOK, res)
 }
 
 // LeaveRoom は部屋から退出する
@@ -229,34 +404,24 @@ func (h *Handler) LeaveRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// リクエストボディをパース
-	var req LeaveRoomRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		slog.Error("LeaveRoom: リクエストボディのパース失敗",
-			slog.String("roomId", roomID),
-			slog.Any("error", err))
-		respondError(w, http.StatusBadRequest, "invalid request body")
-		return
-	}
-
-	if req.PlayerID == "" {
-		slog.Warn("LeaveRoom: playerIdが空", slog.String("roomId", roomID))
-		respondError(w, http.StatusBadRequest, "playerId is required")
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
 		return
 	}
 
 	slog.Info("LeaveRoom: 退出処理開始",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID))
+		slog.String("playerId", playerID))
 
 	output, err := h.leaveRoomUC.Execute(r.Context(), usecase.LeaveRoomInput{
 		RoomID: roomID,
-		UserID: req.PlayerID,
+		UserID: playerID,
 	})
 	if err != nil {
 		slog.Error("LeaveRoom: ユースケース実行失敗",
 			slog.String("roomId", roomID),
-			slog.String("playerId", req.PlayerID),
+			slog.String("playerId", playerID),
 			slog.Any("error", err))
 		handleError(w, err)
 		return
@@ -264,7 +429,7 @@ func (h *Handler) LeaveRoom(w http.ResponseWriter, r *http.Request) {
 
 	slog.Info("LeaveRoom: 退出成功",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID))
+		slog.String("playerId", playerID))
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"success": output.Success,
 	})
@@ -289,34 +454,24 @@ func (h *Handler) ToggleReady(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// リクエストボディをパース
-	var req ReadyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		slog.Error("ToggleReady: リクエストボディのパース失敗",
-			slog.String("roomId", roomID),
-			slog.Any("error", err))
-		respondError(w, http.StatusBadRequest, "invalid request body")
-		return
-	}
-
-	if req.PlayerID == "" {
-		slog.Warn("ToggleReady: playerIdが空", slog.String("roomId", roomID))
-		respondError(w, http.StatusBadRequest, "playerId is required")
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
 		return
 	}
 
 	slog.Info("ToggleReady: Ready状態トグル開始",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID))
+		slog.String("playerId", playerID))
 
 	output, err := h.toggleReadyUC.Execute(r.Context(), usecase.ToggleReadyInput{
 		RoomID: roomID,
-		UserID: req.PlayerID,
+		UserID: playerID,
 	})
 	if err != nil {
 		slog.Error("ToggleReady: ユースケース実行失敗",
 			slog.String("roomId", roomID),
-			slog.String("playerId", req.PlayerID),
+			slog.String("playerId", playerID),
 			slog.Any("error", err))
 		handleError(w, err)
 		return
@@ -324,7 +479,7 @@ func (h *Handler) ToggleReady(w http.ResponseWriter, r *http.Request) {
 
 	slog.Info("ToggleReady: Ready状態変更成功",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID),
+		slog.String("playerId", playerID),
 		slog.Bool("isReady", output.IsReady))
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"isReady": output.IsReady,
@@ -350,34 +505,24 @@ func (h *Handler) StartGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// リクエストボディをパース
-	var req StartGameRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		slog.Error("StartGame: リクエストボディのパース失敗",
-			slog.String("roomId", roomID),
-			slog.Any("error", err))
-		respondError(w, http.StatusBadRequest, "invalid request body")
-		return
-	}
-
-	if req.PlayerID == "" {
-		slog.Warn("StartGame: playerIdが空", slog.String("roomId", roomID))
-		respondError(w, http.StatusBadRequest, "playerId is required")
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
 		return
 	}
 
 	slog.Info("StartGame: ゲーム開始処理開始",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID))
+		slog.String("playerId", playerID))
 
 	output, err := h.startGameUC.Execute(r.Context(), usecase.StartGameInput{
 		RoomID: roomID,
-		UserID: req.PlayerID,
+		UserID: playerID,
 	})
 	if err != nil {
 		slog.Error("StartGame: ユースケース実行失敗",
 			slog.String("roomId", roomID),
-			slog.String("playerId", req.PlayerID),
+			slog.String("playerId", playerID),
 			slog.Any("error", err))
 		handleError(w, err)
 		return
@@ -395,15 +540,23 @@ func (h *Handler) StartGame(w http.ResponseWriter, r *http.Request) {
 }
 
 // Vote は投票を処理する
-// POST /api/rooms/{roomId}/vote
+// POST /api/rooms/{roomId}/vote（投票）
+// PUT /api/rooms/{roomId}/vote（投票の変更）
+// DELETE /api/rooms/{roomId}/vote（投票の取り消し。RetractVote を参照）
 func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
-	slog.Info("Vote: リクエスト受信")
+	slog.Info("Vote: リクエスト受信", slog.String("method", r.Method))
 
-	if r.Method != http.MethodPost {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+	case http.MethodDelete:
+		h.RetractVote(w, r)
+		return
+	default:
 		slog.Warn("Vote: 不正なメソッド", slog.String("method", r.Method))
 		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
+	change := r.Method == http.MethodPut
 
 	// URLからroomIdを取得
 	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/vote")
@@ -413,6 +566,12 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
+		return
+	}
+
 	// リクエストボディをパース
 	var req VoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -423,34 +582,36 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.PlayerID == "" {
-		slog.Warn("Vote: playerIdが空", slog.String("roomId", roomID))
-		respondError(w, http.StatusBadRequest, "playerId is required")
-		return
-	}
-	if req.PolicyID == "" {
+	if req.PolicyID == "" && len(req.PolicyIDs) == 0 {
 		slog.Warn("Vote: policyIdが空",
 			slog.String("roomId", roomID),
-			slog.String("playerId", req.PlayerID))
-		respondError(w, http.StatusBadRequest, "policyId is required")
+			slog.String("playerId", playerID))
+		respondError(w, http.StatusBadRequest, "policyId or policyIds is required")
 		return
 	}
 
 	slog.Info("Vote: 投票処理開始",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID),
-		slog.String("policyId", req.PolicyID))
+		slog.String("playerId", playerID),
+		slog.String("policyId", req.PolicyID),
+		slog.Any("policyIds", req.PolicyIDs),
+		slog.Bool("change", change),
+		slog.Bool("lockIn", req.LockIn))
 
 	output, err := h.voteUC.Execute(r.Context(), usecase.VoteInput{
 		RoomID:   roomID,
-		UserID:   req.PlayerID,
+		UserID:   playerID,
 		PolicyID: req.PolicyID,
+		Ballot:   req.PolicyIDs,
+		Change:   change,
+		LockIn:   req.LockIn,
 	})
 	if err != nil {
 		slog.Error("Vote: ユースケース実行失敗",
 			slog.String("roomId", roomID),
-			slog.String("playerId", req.PlayerID),
+			slog.String("playerId", playerID),
 			slog.String("policyId", req.PolicyID),
+			slog.Any("policyIds", req.PolicyIDs),
 			slog.Any("error", err))
 		handleError(w, err)
 		return
@@ -458,29 +619,43 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 
 	slog.Info("Vote: 投票成功",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID),
+		slog.String("playerId", playerID),
 		slog.String("policyId", req.PolicyID),
+		slog.Any("policyIds", req.PolicyIDs),
 		slog.Bool("allVoted", output.AllVoted),
+		slog.Int("undecided", output.Undecided),
 		slog.Bool("isResolved", output.IsResolved))
+	respondJSON(w, http.StatusOK, newVoteResponse(output))
+}
 
+// newVoteResponse は投票・投票の確定のレスポンスを作成する
+func newVoteResponse(output *usecase.VoteOutput) map[string]interface{} {
 	// 自動resolveされた場合はresolve結果も返す
 	if output.IsResolved {
-		respondJSON(w, http.StatusOK, map[string]interface{}{
+		return map[string]interface{}{
 			"success":    output.Success,
 			"allVoted":   output.AllVoted,
+			"undecided":  output.Undecided,
 			"isResolved": output.IsResolved,
 			"status":     output.Room.Status,
 			"lastResult": output.Room.LastResult,
 			"cityParams": output.Room.CityParams,
 			"isGameOver": output.IsGameOver,
-		})
-		return
+		}
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"success":  output.Success,
-		"allVoted": output.AllVoted,
-	})
+	res := map[string]interface{}{
+		"success":   output.Success,
+		"allVoted":  output.AllVoted,
+		"undecided": output.Undecided,
+	}
+	// 同数で決着待ちになった場合（ホストの選択 or 再投票）
+	if output.PendingTie != nil {
+		res["isResolved"] = false
+		res["status"] = output.Room.Status
+		res["pendingTie"] = output.PendingTie
+	}
+	return res
 }
 
 // ResolveVote は投票集計を処理する
@@ -503,6 +678,11 @@ func (h *Handler) ResolveVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 部屋のプレイヤーのみ進められる
+	if _, ok := requirePlayer(w, r, roomID); !ok {
+		return
+	}
+
 	slog.Info("ResolveVote: 投票集計開始", slog.String("roomId", roomID))
 
 	output, err := h.resolveVoteUC.Execute(r.Context(), usecase.ResolveVoteInput{
@@ -516,12 +696,26 @@ func (h *Handler) ResolveVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !output.IsResolved {
+		slog.Info("ResolveVote: 同数のため決着待ち",
+			slog.String("roomId", roomID),
+			slog.String("rule", string(output.PendingTie.Rule)),
+			slog.Any("policyIds", output.PendingTie.PolicyIDs))
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"status":     output.Room.Status,
+			"isResolved": false,
+			"pendingTie": output.PendingTie,
+		})
+		return
+	}
+
 	slog.Info("ResolveVote: 投票集計成功",
 		slog.String("roomId", roomID),
 		slog.String("passedPolicy", output.Room.LastResult.PassedPolicyTitle),
 		slog.Bool("isGameOver", output.IsGameOver))
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"status":     output.Room.Status,
+		"isResolved": true,
 		"lastResult": output.Room.LastResult,
 		"cityParams": output.Room.CityParams,
 		"isGameOver": output.IsGameOver,
@@ -548,6 +742,11 @@ func (h *Handler) NextTurn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 部屋のプレイヤーのみ進められる
+	if _, ok := requirePlayer(w, r, roomID); !ok {
+		return
+	}
+
 	slog.Info("NextTurn: 次ターン処理開始", slog.String("roomId", roomID))
 
 	output, err := h.nextTurnUC.Execute(r.Context(), usecase.NextTurnInput{
@@ -564,10 +763,12 @@ func (h *Handler) NextTurn(w http.ResponseWriter, r *http.Request) {
 	slog.Info("NextTurn: 次ターン開始成功",
 		slog.String("roomId", roomID),
 		slog.Int("turn", output.Turn),
-		slog.String("status", string(output.Status)))
+		slog.String("status", string(output.Status)),
+		slog.Bool("isGameOver", output.IsGameOver))
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"status": output.Status,
-		"turn":   output.Turn,
+		"status":     output.Status,
+		"turn":       output.Turn,
+		"isGameOver": output.IsGameOver,
 	})
 }
 
@@ -590,6 +791,12 @@ func (h *Handler) SubmitPetition(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
+		return
+	}
+
 	// リクエストボディをパース
 	var req PetitionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -600,33 +807,28 @@ func (h *Handler) SubmitPetition(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.PlayerID == "" {
-		slog.Warn("SubmitPetition: playerIdが空", slog.String("roomId", roomID))
-		respondError(w, http.StatusBadRequest, "playerId is required")
-		return
-	}
 	if req.Text == "" {
 		slog.Warn("SubmitPetition: textが空",
 			slog.String("roomId", roomID),
-			slog.String("playerId", req.PlayerID))
+			slog.String("playerId", playerID))
 		respondError(w, http.StatusBadRequest, "text is required")
 		return
 	}
 
 	slog.Info("SubmitPetition: 陳情処理開始",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID),
-		slog.String("text", req.Text))
+		slog.String("playerId", playerID),
+		slog.Int("textLength", utf8.RuneCountInString(req.Text)))
 
 	output, err := h.submitPetitionUC.Execute(r.Context(), usecase.SubmitPetitionInput{
 		RoomID:       roomID,
-		PlayerID:     req.PlayerID,
+		PlayerID:     playerID,
 		PetitionText: req.Text,
 	})
 	if err != nil {
 		slog.Error("SubmitPetition: ユースケース実行失敗",
 			slog.String("roomId", roomID),
-			slog.String("playerId", req.PlayerID),
+			slog.String("playerId", playerID),
 			slog.Any("error", err))
 		handleError(w, err)
 		return
@@ -634,16 +836,55 @@ func (h *Handler) SubmitPetition(w http.ResponseWriter, r *http.Request) {
 
 	slog.Info("SubmitPetition: 陳情処理完了",
 		slog.String("roomId", roomID),
-		slog.String("playerId", req.PlayerID),
+		slog.String("playerId", playerID),
 		slog.Bool("approved", output.Approved),
-		slog.String("policyId", output.PolicyID))
+		slog.String("policyId", output.PolicyID),
+		slog.Int("surfaceTurn", output.SurfaceTurn))
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"approved": output.Approved,
-		"policyId": output.PolicyID,
-		"message":  output.Message,
+		"approved":    output.Approved,
+		"policyId":    output.PolicyID,
+		"surfaceTurn": output.SurfaceTurn,
+		"message":     output.Message,
 	})
 }
 
+// GetFinalResult は最終結果を取得する
+// GET /api/rooms/{roomId}/result
+// ゲーム終了画面用（スコア・順位・各プレイヤーの思想）
+func (h *Handler) GetFinalResult(w http.ResponseWriter, r *http.Request) {
+	slog.Info("GetFinalResult: リクエスト受信")
+
+	if r.Method != http.MethodGet {
+		slog.Warn("GetFinalResult: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/result")
+	if roomID == "" {
+		slog.Warn("GetFinalResult: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	output, err := h.getFinalResultUC.Execute(r.Context(), usecase.GetFinalResultInput{
+		RoomID: roomID,
+	})
+	if err != nil {
+		slog.Error("GetFinalResult: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	slog.Info("GetFinalResult: 最終結果取得成功",
+		slog.String("roomId", roomID),
+		slog.String("reason", string(output.FinalResult.Reason)))
+	respondJSON(w, http.StatusOK, output.FinalResult)
+}
+
 // ============================================================================
 // ユーティリティ関数
 // ============================================================================
@@ -689,6 +930,15 @@ func handleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, entity.ErrNotAllReady):
 		slog.Warn("handleError: 全員未Ready", attrs...)
 		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, entity.ErrGameNotFinished):
+		slog.Warn("handleError: ゲーム未終了", attrs...)
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, entity.ErrVoteLockedIn):
+		slog.Warn("handleError: 投票確定済み", attrs...)
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, entity.ErrNotVoted):
+		slog.Warn("handleError: 未投票", attrs...)
+		respondError(w, http.StatusConflict, err.Error())
 	case errors.Is(err, entity.ErrPetitionUsed):
 		slog.Warn("handleError: 陳情使用済み", attrs...)
 		respondError(w, http.StatusConflict, err.Error())
@@ -707,12 +957,51 @@ func handleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, entity.ErrNoIdeologyAvailable):
 		slog.Warn("handleError: 利用可能な思想がない", attrs...)
 		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, entity.ErrNoRoomCodeLeft):
+		slog.Error("handleError: 部屋コードの空きがない", attrs...)
+		respondError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, entity.ErrInvalidRoomCode):
+		slog.Warn("handleError: 無効な部屋コード", attrs...)
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, entity.ErrInvalidSettings):
+		slog.Warn("handleError: 無効な部屋設定", attrs...)
+		respondError(w, http.StatusBadRequest, err.Error())
 	case errors.Is(err, entity.ErrInvalidPolicy):
 		slog.Warn("handleError: 無効な政策", attrs...)
 		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, entity.ErrInvalidBallot):
+		slog.Warn("handleError: 無効な票", attrs...)
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, entity.ErrUnauthenticated):
+		slog.Warn("handleError: 未認証", attrs...)
+		respondError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, entity.ErrSessionRoomMismatch):
+		slog.Warn("handleError: 別の部屋のセッション", attrs...)
+		respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, entity.ErrRoomPasswordRequired),
+		errors.Is(err, entity.ErrInvalidRoomPassword),
+		errors.Is(err, entity.ErrInviteRequired),
+		errors.Is(err, entity.ErrInvalidInvite):
+		slog.Warn("handleError: 部屋に参加する権限がない", attrs...)
+		respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, entity.ErrTooManyJoinAttempts):
+		slog.Warn("handleError: 参加の失敗が多すぎる", attrs...)
+		respondError(w, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, entity.ErrTooManyInvites):
+		slog.Warn("handleError: 有効な招待が多すぎる", attrs...)
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, entity.ErrInvalidRejoinCode):
+		slog.Warn("handleError: 再参加コードが一致しない", attrs...)
+		respondError(w, http.StatusForbidden, err.Error())
 	case errors.Is(err, entity.ErrNotHost):
 		slog.Warn("handleError: ホストではない", attrs...)
 		respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, entity.ErrPetitionBlocked):
+		slog.Warn("handleError: 陳情がモデレーションで拒否", attrs...)
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, entity.ErrInvalidAIResponse):
+		slog.Error("handleError: AIの応答が不正", attrs...)
+		respondError(w, http.StatusBadGateway, err.Error())
 	default:
 		slog.Error("handleError: 内部エラー", attrs...)
 		respondError(w, http.StatusInternalServerError, "internal server error")
@@ -723,8 +1012,8 @@ func handleError(w http.ResponseWriter, err error) {
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
@@ -739,8 +1028,8 @@ func respondError(w http.ResponseWriter, status int, message string) {
 // HandleCORS はCORSプリフライトリクエストを処理する
 func HandleCORS(w http.ResponseWriter, r *http.Request) bool {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
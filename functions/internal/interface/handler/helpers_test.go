@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/interface/gateway/auth"
+	"github.com/techworld-hackathon/functions/internal/interface/gateway/event"
+	"github.com/techworld-hackathon/functions/internal/interface/gateway/inmemory"
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// testSecret はテスト用のセッショントークンの鍵
+var testSecret = []byte("handler-test-secret-0123456789abcdef")
+
+// testEnv はインメモリのリポジトリで組み立てた Handler と、検証に使うリポジトリ
+type testEnv struct {
+	h          *Handler
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	sessions   *auth.SessionManager
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	store := inmemory.NewStore()
+	roomRepo := inmemory.NewRoomRepository(store)
+	playerRepo := inmemory.NewPlayerRepository(store)
+	policyRepo := inmemory.NewPolicyRepository(store)
+	ideologyRepo := inmemory.NewIdeologyRepository(store)
+	codeRepo := inmemory.NewRoomCodeRepository(store)
+	transactor := inmemory.NewTransactor(store)
+	jobs := inmemory.NewJobQueue()
+	broker := event.NewBroker()
+	sessions := auth.NewSessionManager(auth.SessionConfig{Secret: testSecret, TTL: time.Hour})
+
+	createRoomUC := usecase.NewCreateRoomUseCase(roomRepo, playerRepo, ideologyRepo, codeRepo, transactor)
+	joinRoomUC := usecase.NewJoinRoomUseCase(roomRepo, playerRepo, ideologyRepo, codeRepo, transactor, broker)
+	h := NewHandler(
+		createRoomUC,
+		joinRoomUC,
+		usecase.NewRejoinRoomUseCase(roomRepo, playerRepo, transactor, broker),
+		usecase.NewLeaveRoomUseCase(roomRepo, playerRepo, codeRepo, transactor, nil, jobs, broker),
+		usecase.NewToggleReadyUseCase(roomRepo, playerRepo, broker),
+		usecase.NewUpdateRoomSettingsUseCase(roomRepo, playerRepo, transactor, broker),
+		usecase.NewStartGameUseCase(roomRepo, playerRepo, policyRepo, transactor, jobs, broker),
+		usecase.NewVoteUseCase(roomRepo, playerRepo, policyRepo, transactor, nil, nil, jobs, broker),
+		usecase.NewRetractVoteUseCase(roomRepo, playerRepo, transactor, broker),
+		usecase.NewResolveVoteUseCase(roomRepo, playerRepo, policyRepo, transactor, nil, nil, jobs, broker),
+		usecase.NewDecideTieUseCase(roomRepo, playerRepo, policyRepo, transactor, nil, nil, jobs, broker),
+		usecase.NewNextTurnUseCase(roomRepo, playerRepo, transactor, jobs, broker),
+		nil, // 陳情は AI を使うため、このテストでは扱わない
+		usecase.NewGetFinalResultUseCase(roomRepo),
+		usecase.NewGetRoomUseCase(roomRepo, playerRepo, policyRepo),
+		usecase.NewGetPlayersUseCase(roomRepo, playerRepo),
+		usecase.NewGetGraveyardUseCase(roomRepo, policyRepo),
+		usecase.NewFindRoomByCodeUseCase(codeRepo, roomRepo, playerRepo),
+		usecase.NewListLobbiesUseCase(roomRepo, playerRepo),
+		usecase.NewQuickMatchUseCase(roomRepo, playerRepo, joinRoomUC, createRoomUC),
+		usecase.NewUpdateRoomAccessUseCase(roomRepo, transactor),
+		usecase.NewCreateInviteUseCase(roomRepo, transactor),
+		broker,
+		sessions,
+		sessions,
+		false,
+	)
+	return &testEnv{h: h, roomRepo: roomRepo, playerRepo: playerRepo, sessions: sessions}
+}
+
+// serve は Authenticate を通してハンドラーを呼び出し、レスポンスを返す（token が空ならトークンなし）
+func (e *testEnv) serve(t *testing.T, handle http.HandlerFunc, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.h.Authenticate(handle).ServeHTTP(rec, req)
+	return rec
+}
+
+// session は部屋を作成・参加したプレイヤー
+type session struct {
+	roomID   string
+	playerID string
+	token    string
+}
+
+// createRoom は部屋を作成してホストのセッションを返す
+func (e *testEnv) createRoom(t *testing.T) session {
+	t.Helper()
+	rec := e.serve(t, e.h.CreateRoom, http.MethodPost, "/api/rooms", "", CreateRoomRequest{DisplayName: "ホスト"})
+	return decodeSession(t, rec)
+}
+
+// join は部屋に参加してセッションを返す
+func (e *testEnv) join(t *testing.T, roomID, displayName string) session {
+	t.Helper()
+	rec := e.serve(t, e.h.JoinRoom, http.MethodPost, "/api/rooms/"+roomID+"/join", "", JoinRoomRequest{DisplayName: displayName})
+	return decodeSession(t, rec)
+}
+
+func decodeSession(t *testing.T, rec *httptest.ResponseRecorder) session {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var res struct {
+		RoomID       string `json:"roomId"`
+		PlayerID     string `json:"playerId"`
+		SessionToken string `json:"sessionToken"`
+	}
+	decodeJSON(t, rec.Body.String(), &res)
+	return session{roomID: res.RoomID, playerID: res.PlayerID, token: res.SessionToken}
+}
+
+// vote はプレイヤーの票を直接保存する（ゲームを進めずに秘匿情報を用意するため）
+func (e *testEnv) vote(t *testing.T, roomID, playerID string, ballot ...string) {
+	t.Helper()
+	ctx := context.Background()
+	player, err := e.playerRepo.FindByID(ctx, roomID, playerID)
+	if err != nil || player == nil {
+		t.Fatalf("FindByID(%s) = %v, %v", playerID, player, err)
+	}
+	player.Vote(ballot)
+	if err := e.playerRepo.Update(ctx, roomID, playerID, player); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+}
+
+// player はリポジトリからプレイヤーを取得する
+func (e *testEnv) player(t *testing.T, roomID, playerID string) *entity.Player {
+	t.Helper()
+	player, err := e.playerRepo.FindByID(context.Background(), roomID, playerID)
+	if err != nil || player == nil {
+		t.Fatalf("FindByID(%s) = %v, %v", playerID, player, err)
+	}
+	return player
+}
+
+func decodeJSON(t *testing.T, body string, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(strings.NewReader(body)).Decode(v); err != nil {
+		t.Fatalf("Decode(%s): %v", body, err)
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// QuickMatchRequest はクイックマッチリクエスト
+type QuickMatchRequest struct {
+	DisplayName string               `json:"displayName"`
+	Settings    *RoomSettingsRequest `json:"settings,omitempty"` // 指定した項目が一致する部屋を探す（部屋を作成する場合もこの設定）
+}
+
+// LobbyResponse は公開ロビーのレスポンス
+type LobbyResponse struct {
+	RoomID      string              `json:"roomId"`
+	RoomCode    string              `json:"roomCode"`
+	HostName    string              `json:"hostName"`
+	PlayerCount int                 `json:"playerCount"`
+	HasPassword bool                `json:"hasPassword"` // 参加にパスワードが必要
+	Settings    entity.RoomSettings `json:"settings"`
+	CreatedAt   time.Time           `json:"createdAt"`
+}
+
+// ListLobbies は参加者を募集している公開の部屋を一覧する
+// GET /api/lobbies?limit={n}
+// 満員の部屋は含めない。セッション不要
+func (h *Handler) ListLobbies(w http.ResponseWriter, r *http.Request) {
+	slog.Info("ListLobbies: リクエスト受信")
+
+	if r.Method != http.MethodGet {
+		slog.Warn("ListLobbies: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var limit int
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			slog.Warn("ListLobbies: limitが不正", slog.String("limit", v))
+			respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	output, err := h.listLobbiesUC.Execute(r.Context(), usecase.ListLobbiesInput{
+		Limit: limit,
+	})
+	if err != nil {
+		slog.Error("ListLobbies: ユースケース実行失敗", slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	lobbies := make([]LobbyResponse, 0, len(output.Lobbies))
+	for _, lobby := range output.Lobbies {
+		lobbies = append(lobbies, LobbyResponse{
+			RoomID:      lobby.RoomID,
+			RoomCode:    lobby.Code,
+			HostName:    lobby.HostName,
+			PlayerCount: lobby.PlayerCount,
+			HasPassword: lobby.HasPassword,
+			Settings:    lobby.Settings,
+			CreatedAt:   lobby.CreatedAt,
+		})
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"lobbies": lobbies,
+	})
+}
+
+// QuickMatch は公開ロビーに参加する（参加できる部屋がなければ公開の部屋を作成する）
+// POST /api/quickmatch
+// 参加人数の多い部屋から順に参加を試す。レスポンスは部屋参加と同じく新しいセッションを含む
+func (h *Handler) QuickMatch(w http.ResponseWriter, r *http.Request) {
+	slog.Info("QuickMatch: リクエスト受信")
+
+	if r.Method != http.MethodPost {
+		slog.Warn("QuickMatch: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// リクエストボディをパース
+	var req QuickMatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Error("QuickMatch: リクエストボディのパース失敗", slog.Any("error", err))
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.DisplayName == "" {
+		slog.Warn("QuickMatch: displayNameが空")
+		respondError(w, http.StatusBadRequest, "displayName is required")
+		return
+	}
+
+	// プレイヤーIDを生成（Firebase の ID トークンで認証されていれば UID）
+	playerID := newPlayerID(r)
+	slog.Info("QuickMatch: 部屋を探す",
+		slog.String("playerId", playerID),
+		slog.String("displayName", req.DisplayName))
+
+	output, err := h.quickMatchUC.Execute(r.Context(), usecase.QuickMatchInput{
+		UserID:      playerID,
+		DisplayName: req.DisplayName,
+		Settings:    req.Settings.toInput(),
+	})
+	if err != nil {
+		slog.Error("QuickMatch: ユースケース実行失敗", slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	res := map[string]interface{}{
+		"roomId":     output.RoomID,
+		"roomCode":   output.RoomCode,
+		"playerId":   output.PlayerID,
+		"rejoinCode": output.RejoinCode,
+		"created":    output.Created,
+	}
+	if err := h.issueSession(res, output.RoomID, output.PlayerID); err != nil {
+		slog.Error("QuickMatch: セッション発行失敗",
+			slog.String("roomId", output.RoomID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	slog.Info("QuickMatch: 参加成功",
+		slog.String("roomId", output.RoomID),
+		slog.String("playerId", output.PlayerID),
+		slog.Bool("created", output.Created))
+	respondJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// ============================================================================
+// レスポンス型（読み取り用）
+// ============================================================================
+
+// RoomResponse は部屋情報のレスポンス
+// deckIds・他プレイヤーの投票先・政策の effects は含めない
+type RoomResponse struct {
+	RoomID          string                `json:"roomId"`
+	RoomCode        string                `json:"roomCode"` // 口頭で伝えるための短い部屋コード
+	HostID          string                `json:"hostId"`
+	Visibility      entity.RoomVisibility `json:"visibility"`
+	HasPassword     bool                  `json:"hasPassword"` // パスワード・招待・失敗の記録は返さない
+	InviteOnly      bool                  `json:"inviteOnly"`
+	Status          entity.RoomStatus     `json:"status"`
+	Turn            int                   `json:"turn"`
+	MaxTurns        int                   `json:"maxTurns"`
+	Settings        entity.RoomSettings   `json:"settings"`
+	CreatedAt       time.Time             `json:"createdAt"`
+	CityParams      entity.CityParams     `json:"cityParams"`
+	IsCollapsed     bool                  `json:"isCollapsed"`
+	CurrentOptions  []entity.PolicyOption `json:"currentOptions"`
+	PassedPolicyIDs []string              `json:"passedPolicyIds"`
+	PendingTie      *entity.PendingTie    `json:"pendingTie"` // 同数で決着待ち（HOST_DECIDES・REVOTE）
+	LastResult      *entity.VoteResult    `json:"lastResult"`
+	FinalResult     *entity.FinalResult   `json:"finalResult"`
+	Players         []PlayerResponse      `json:"players"`
+}
+
+// PlayerResponse はプレイヤー情報のレスポンス
+// ideology, currentVote, currentBallot はリクエストしたプレイヤー本人の分のみ設定する
+type PlayerResponse struct {
+	PlayerID       string                 `json:"playerId"`
+	DisplayName    string                 `json:"displayName"`
+	IsHost         bool                   `json:"isHost"`
+	IsReady        bool                   `json:"isReady"`
+	IsPetitionUsed bool                   `json:"isPetitionUsed"`
+	PetitionsLeft  int                    `json:"petitionsLeft"` // 残りの陳情回数
+	HasVoted       bool                   `json:"hasVoted"`
+	IsLockedIn     bool                   `json:"isLockedIn"` // 投票を確定したか
+	IsMe           bool                   `json:"isMe"`
+	Ideology       *entity.MasterIdeology `json:"ideology,omitempty"`      // 🔒 本人のみ
+	CurrentVote    string                 `json:"currentVote,omitempty"`   // 🔒 本人のみ
+	CurrentBallot  []string               `json:"currentBallot,omitempty"` // 🔒 本人のみ
+}
+
+// newPlayerResponses はプレイヤー一覧を viewerID 視点のレスポンスに変換する
+func newPlayerResponses(room *entity.Room, players []*repository.PlayerWithID, viewerID string) []PlayerResponse {
+	responses := make([]PlayerResponse, 0, len(players))
+	for _, p := range players {
+		petitionsLeft := p.Player.RemainingPetitions(room.Settings.PetitionsPerPlayer)
+		res := PlayerResponse{
+			PlayerID:       p.UserID,
+			DisplayName:    p.Player.DisplayName,
+			IsHost:         p.Player.IsHost,
+			IsReady:        p.Player.IsReady,
+			IsPetitionUsed: petitionsLeft == 0,
+			PetitionsLeft:  petitionsLeft,
+			HasVoted:       room.HasVoted(p.UserID),
+			IsLockedIn:     room.IsLockedIn(p.UserID),
+			IsMe:           viewerID != "" && p.UserID == viewerID,
+		}
+		if res.IsMe {
+			res.Ideology = p.Player.Ideology
+			res.CurrentVote = p.Player.CurrentVote
+			res.CurrentBallot = p.Player.CurrentBallot
+		}
+		responses = append(responses, res)
+	}
+	return responses
+}
+
+// newRoomResponse は部屋情報を viewerID 視点のレスポンスに変換する
+func newRoomResponse(roomID string, output *usecase.GetRoomOutput, viewerID string) RoomResponse {
+	room := output.Room
+	return RoomResponse{
+		RoomID:          roomID,
+		RoomCode:        room.Code,
+		HostID:          room.HostID,
+		Visibility:      room.Visibility,
+		HasPassword:     room.HasPassword(),
+		InviteOnly:      room.InviteOnly,
+		Status:          room.Status,
+		Turn:            room.Turn,
+		MaxTurns:        room.MaxTurns,
+		Settings:        room.Settings,
+		CreatedAt:       room.CreatedAt,
+		CityParams:      room.CityParams,
+		IsCollapsed:     room.IsCollapsed,
+		CurrentOptions:  output.CurrentOptions,
+		PassedPolicyIDs: room.PassedPolicyIDs,
+		PendingTie:      room.PendingTie,
+		LastResult:      room.LastResult,
+		FinalResult:     room.FinalResult,
+		Players:         newPlayerResponses(room, output.Players, viewerID),
+	}
+}
+
+// GraveyardEntryResponse は可決されなかった政策のレスポンス
+// effects はゲーム終了後のみ含める（プレイ中に政策の効果を推測させない）
+type GraveyardEntryResponse struct {
+	PolicyID    string         `json:"policyId"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Turn        int            `json:"turn"`
+	Votes       int            `json:"votes"`
+	Recycled    bool           `json:"recycled"`
+	Effects     map[string]int `json:"effects,omitempty"` // FINISHED のみ
+}
+
+// newGraveyardResponse は政策の墓場のレスポンスを作成する
+func newGraveyardResponse(output *usecase.GetGraveyardOutput) []GraveyardEntryResponse {
+	revealEffects := output.Room.Status == entity.RoomStatusFinished
+	entries := make([]GraveyardEntryResponse, 0, len(output.Entries))
+	for _, e := range output.Entries {
+		entry := GraveyardEntryResponse{
+			PolicyID:    e.Rejected.PolicyID,
+			Title:       e.Policy.Title,
+			Description: e.Policy.Description,
+			Turn:        e.Rejected.Turn,
+			Votes:       e.Rejected.Votes,
+			Recycled:    e.Rejected.Recycled,
+		}
+		if revealEffects {
+			entry.Effects = e.Policy.Effects
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ============================================================================
+// ハンドラー実装（読み取り）
+// ============================================================================
+
+// GetRoom は部屋情報を取得する
+// GET /api/rooms/{roomId}
+// この部屋のセッションで認証した場合、そのプレイヤーの秘匿情報のみ含める
+func (h *Handler) GetRoom(w http.ResponseWriter, r *http.Request) {
+	slog.Info("GetRoom: リクエスト受信")
+
+	if r.Method != http.MethodGet {
+		slog.Warn("GetRoom: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "")
+	if roomID == "" {
+		slog.Warn("GetRoom: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+	viewerID := viewerFrom(r, roomID)
+
+	output, err := h.getRoomUC.Execute(r.Context(), usecase.GetRoomInput{
+		RoomID: roomID,
+	})
+	if err != nil {
+		slog.Error("GetRoom: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newRoomResponse(roomID, output, viewerID))
+}
+
+// GetPlayers はプレイヤー一覧を取得する
+// GET /api/rooms/{roomId}/players
+// この部屋のセッションで認証した場合、そのプレイヤーの秘匿情報のみ含める
+func (h *Handler) GetPlayers(w http.ResponseWriter, r *http.Request) {
+	slog.Info("GetPlayers: リクエスト受信")
+
+	if r.Method != http.MethodGet {
+		slog.Warn("GetPlayers: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/players")
+	if roomID == "" {
+		slog.Warn("GetPlayers: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+	viewerID := viewerFrom(r, roomID)
+
+	output, err := h.getPlayersUC.Execute(r.Context(), usecase.GetPlayersInput{
+		RoomID: roomID,
+	})
+	if err != nil {
+		slog.Error("GetPlayers: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"players": newPlayerResponses(output.Room, output.Players, viewerID),
+	})
+}
+
+// GetGraveyard は可決されなかった政策の一覧（政策の墓場）を取得する
+// GET /api/rooms/{roomId}/graveyard
+// ゲーム終了画面の振り返り用（effects は FINISHED のみ含める）
+func (h *Handler) GetGraveyard(w http.ResponseWriter, r *http.Request) {
+	slog.Info("GetGraveyard: リクエスト受信")
+
+	if r.Method != http.MethodGet {
+		slog.Warn("GetGraveyard: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/graveyard")
+	if roomID == "" {
+		slog.Warn("GetGraveyard: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	output, err := h.getGraveyardUC.Execute(r.Context(), usecase.GetGraveyardInput{
+		RoomID: roomID,
+	})
+	if err != nil {
+		slog.Error("GetGraveyard: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"recycleRejected": output.Room.Settings.RecycleRejected,
+		"policies":        newGraveyardResponse(output),
+	})
+}
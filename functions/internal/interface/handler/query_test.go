@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// assertRedacted は viewer 本人の秘匿情報だけが含まれ、他のプレイヤーの分は含まれないことを確認する
+func assertRedacted(t *testing.T, env *testEnv, roomID, viewerID string, players []PlayerResponse) {
+	t.Helper()
+	if len(players) != 2 {
+		t.Fatalf("players = %+v, want 2人", players)
+	}
+	for _, p := range players {
+		if p.PlayerID != viewerID {
+			if p.IsMe || p.Ideology != nil || p.CurrentVote != "" || p.CurrentBallot != nil {
+				t.Errorf("他のプレイヤー %s の秘匿情報が含まれている: %+v", p.PlayerID, p)
+			}
+			continue
+		}
+		stored := env.player(t, roomID, viewerID)
+		if !p.IsMe || p.Ideology == nil || p.Ideology.IdeologyID != stored.Ideology.IdeologyID {
+			t.Errorf("本人の思想 = %+v, want %s", p.Ideology, stored.Ideology.IdeologyID)
+		}
+		if p.CurrentVote != stored.CurrentVote || !reflect.DeepEqual(p.CurrentBallot, stored.CurrentBallot) {
+			t.Errorf("本人の票 = %s %v, want %s %v", p.CurrentVote, p.CurrentBallot, stored.CurrentVote, stored.CurrentBallot)
+		}
+	}
+}
+
+func TestGetRoom_RedactsOtherPlayers(t *testing.T) {
+	env := newTestEnv(t)
+	host := env.createRoom(t)
+	guest := env.join(t, host.roomID, "ゲスト")
+	env.vote(t, host.roomID, host.playerID, "policy_a", "policy_b")
+	env.vote(t, host.roomID, guest.playerID, "policy_c")
+
+	for _, viewer := range []session{host, guest} {
+		rec := env.serve(t, env.h.GetRoom, http.MethodGet, "/api/rooms/"+host.roomID, viewer.token, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+		var res RoomResponse
+		decodeJSON(t, rec.Body.String(), &res)
+		assertRedacted(t, env, host.roomID, viewer.playerID, res.Players)
+
+		// 山札は誰にも返さない
+		if strings.Contains(rec.Body.String(), "deckIds") {
+			t.Errorf("deckIds が含まれている: %s", rec.Body.String())
+		}
+	}
+}
+
+func TestGetPlayers_RedactsOtherPlayers(t *testing.T) {
+	env := newTestEnv(t)
+	host := env.createRoom(t)
+	guest := env.join(t, host.roomID, "ゲスト")
+	env.vote(t, host.roomID, host.playerID, "policy_a")
+	env.vote(t, host.roomID, guest.playerID, "policy_c", "policy_a")
+
+	rec := env.serve(t, env.h.GetPlayers, http.MethodGet, "/api/rooms/"+host.roomID+"/players", guest.token, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var res struct {
+		Players []PlayerResponse `json:"players"`
+	}
+	decodeJSON(t, rec.Body.String(), &res)
+	assertRedacted(t, env, host.roomID, guest.playerID, res.Players)
+}
+
+func TestGetRoom_WithoutSessionReturnsNoSecrets(t *testing.T) {
+	env := newTestEnv(t)
+	host := env.createRoom(t)
+	env.join(t, host.roomID, "ゲスト")
+	env.vote(t, host.roomID, host.playerID, "policy_a")
+
+	// トークンなし・別の部屋のセッションでは、誰の秘匿情報も返さない
+	other := env.createRoom(t)
+	for name, token := range map[string]string{"トークンなし": "", "別の部屋のセッション": other.token} {
+		rec := env.serve(t, env.h.GetRoom, http.MethodGet, "/api/rooms/"+host.roomID, token, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", name, rec.Code, rec.Body.String())
+		}
+		var res RoomResponse
+		decodeJSON(t, rec.Body.String(), &res)
+		for _, p := range res.Players {
+			if p.IsMe || p.Ideology != nil || p.CurrentVote != "" || p.CurrentBallot != nil {
+				t.Errorf("%s: %s の秘匿情報が含まれている: %+v", name, p.PlayerID, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// UpdateRoomAccessRequest は部屋への参加の制限の変更リクエスト（省略した項目は変更しない）
+type UpdateRoomAccessRequest struct {
+	Password   *string `json:"password,omitempty"` // 空文字でパスワードを解除する
+	InviteOnly *bool   `json:"inviteOnly,omitempty"`
+}
+
+// CreateInviteRequest は招待の発行リクエスト
+type CreateInviteRequest struct {
+	TTLSeconds int `json:"ttlSeconds,omitempty"` // 招待の有効期間（省略時は1時間）
+}
+
+// UpdateRoomAccess は部屋のパスワード・招待のみの設定を変更する
+// POST /api/rooms/{roomId}/access
+// ホストのみ、LOBBYのみ
+func (h *Handler) UpdateRoomAccess(w http.ResponseWriter, r *http.Request) {
+	slog.Info("UpdateRoomAccess: リクエスト受信")
+
+	if r.Method != http.MethodPost {
+		slog.Warn("UpdateRoomAccess: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/access")
+	if roomID == "" {
+		slog.Warn("UpdateRoomAccess: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
+		return
+	}
+
+	// リクエストボディをパース
+	var req UpdateRoomAccessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Error("UpdateRoomAccess: リクエストボディのパース失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	output, err := h.updateAccessUC.Execute(r.Context(), usecase.UpdateRoomAccessInput{
+		RoomID:     roomID,
+		UserID:     playerID,
+		Password:   req.Password,
+		InviteOnly: req.InviteOnly,
+	})
+	if err != nil {
+		slog.Error("UpdateRoomAccess: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.String("playerId", playerID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	slog.Info("UpdateRoomAccess: 変更成功",
+		slog.String("roomId", roomID),
+		slog.Bool("hasPassword", output.HasPassword),
+		slog.Bool("inviteOnly", output.InviteOnly))
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"hasPassword": output.HasPassword,
+		"inviteOnly":  output.InviteOnly,
+	})
+}
+
+// CreateInvite は1回だけ使える招待を発行する
+// POST /api/rooms/{roomId}/invites
+// ホストのみ、LOBBYのみ。招待トークンはこのレスポンスでのみ返す（参加時に invite として送る）
+func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	slog.Info("CreateInvite: リクエスト受信")
+
+	if r.Method != http.MethodPost {
+		slog.Warn("CreateInvite: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/invites")
+	if roomID == "" {
+		slog.Warn("CreateInvite: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
+		return
+	}
+
+	// リクエストボディをパース（有効期間を省略する場合は空でよい）
+	var req CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		slog.Error("CreateInvite: リクエストボディのパース失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.TTLSeconds < 0 {
+		slog.Warn("CreateInvite: ttlSecondsが負", slog.Int("ttlSeconds", req.TTLSeconds))
+		respondError(w, http.StatusBadRequest, "ttlSeconds must be positive")
+		return
+	}
+
+	output, err := h.createInviteUC.Execute(r.Context(), usecase.CreateInviteInput{
+		RoomID: roomID,
+		UserID: playerID,
+		TTL:    time.Duration(req.TTLSeconds) * time.Second,
+	})
+	if err != nil {
+		slog.Error("CreateInvite: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.String("playerId", playerID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	// 招待トークンはログに出さない
+	slog.Info("CreateInvite: 招待発行成功",
+		slog.String("roomId", roomID),
+		slog.Time("expiresAt", output.ExpiresAt))
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"inviteToken": output.Token,
+		"expiresAt":   output.ExpiresAt,
+	})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// FindRoomByCode は部屋コードから部屋を探す
+// GET /api/codes/{code}
+// 参加前の確認用（セッション不要）。部屋コードは大文字・小文字、ハイフンを区別しない
+func (h *Handler) FindRoomByCode(w http.ResponseWriter, r *http.Request) {
+	slog.Info("FindRoomByCode: リクエスト受信")
+
+	if r.Method != http.MethodGet {
+		slog.Warn("FindRoomByCode: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLから部屋コードを取得
+	code := extractRoomID(r.URL.Path, "/api/codes/", "")
+	if code == "" {
+		slog.Warn("FindRoomByCode: 部屋コードが空")
+		respondError(w, http.StatusBadRequest, "room code is required")
+		return
+	}
+
+	output, err := h.findRoomByCodeUC.Execute(r.Context(), usecase.FindRoomByCodeInput{
+		Code: code,
+	})
+	if err != nil {
+		slog.Error("FindRoomByCode: ユースケース実行失敗",
+			slog.String("roomCode", code),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"roomId":      output.RoomID,
+		"roomCode":    output.Code,
+		"status":      output.Status,
+		"playerCount": output.PlayerCount,
+		"maxPlayers":  output.MaxPlayers,
+	})
+}
+
+// JoinRoomByCode は部屋コードで部屋に参加する
+// POST /api/codes/{code}/join
+// レスポンスは POST /api/rooms/{roomId}/join と同じ（roomId で以降のAPIを呼び出す）
+func (h *Handler) JoinRoomByCode(w http.ResponseWriter, r *http.Request) {
+	slog.Info("JoinRoomByCode: リクエスト受信")
+
+	if r.Method != http.MethodPost {
+		slog.Warn("JoinRoomByCode: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLから部屋コードを取得
+	code := extractRoomID(r.URL.Path, "/api/codes/", "/join")
+	if code == "" {
+		slog.Warn("JoinRoomByCode: 部屋コードが空")
+		respondError(w, http.StatusBadRequest, "room code is required")
+		return
+	}
+
+	h.joinRoom(w, r, usecase.JoinRoomInput{RoomCode: code})
+}
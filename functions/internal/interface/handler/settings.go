@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// RoomSettingsRequest は部屋設定のリクエスト（省略した項目は変更しない）
+type RoomSettingsRequest struct {
+	MaxTurns           *int                      `json:"maxTurns,omitempty"`
+	MaxPlayers         *int                      `json:"maxPlayers,omitempty"`
+	OptionsPerTurn     *int                      `json:"optionsPerTurn,omitempty"`
+	InitialCityParams  *entity.CityParams        `json:"initialCityParams,omitempty"`
+	CityRules          entity.CityRules          `json:"cityRules,omitempty"` // 指定した分野だけ上書き
+	PetitionsPerPlayer *int                      `json:"petitionsPerPlayer,omitempty"`
+	VotingMethod       *entity.VotingMethod      `json:"votingMethod,omitempty"`
+	TieBreakRule       *entity.TieBreakRule      `json:"tieBreakRule,omitempty"`
+	RequireLockIn      *bool                     `json:"requireLockIn,omitempty"`
+	VotingTimeLimit    *int                      `json:"votingTimeLimit,omitempty"`
+	ResultTimeLimit    *int                      `json:"resultTimeLimit,omitempty"`
+	TimeoutVote        *entity.TimeoutVote       `json:"timeoutVote,omitempty"`
+	RecycleRejected    *bool                     `json:"recycleRejected,omitempty"`
+	PetitionPlacement  *entity.PetitionPlacement `json:"petitionPlacement,omitempty"`
+	PetitionWindow     *int                      `json:"petitionWindow,omitempty"`
+}
+
+// toInput はユースケースの入力に変換する（nil なら何も指定しない）
+func (req *RoomSettingsRequest) toInput() usecase.RoomSettingsInput {
+	if req == nil {
+		return usecase.RoomSettingsInput{}
+	}
+	return usecase.RoomSettingsInput{
+		MaxTurns:           req.MaxTurns,
+		MaxPlayers:         req.MaxPlayers,
+		OptionsPerTurn:     req.OptionsPerTurn,
+		InitialCityParams:  req.InitialCityParams,
+		CityRules:          req.CityRules,
+		PetitionsPerPlayer: req.PetitionsPerPlayer,
+		VotingMethod:       req.VotingMethod,
+		TieBreakRule:       req.TieBreakRule,
+		RequireLockIn:      req.RequireLockIn,
+		VotingTimeLimit:    req.VotingTimeLimit,
+		ResultTimeLimit:    req.ResultTimeLimit,
+		TimeoutVote:        req.TimeoutVote,
+		RecycleRejected:    req.RecycleRejected,
+		PetitionPlacement:  req.PetitionPlacement,
+		PetitionWindow:     req.PetitionWindow,
+	}
+}
+
+// UpdateRoomSettingsRequest は部屋設定変更リクエスト
+type UpdateRoomSettingsRequest struct {
+	Settings RoomSettingsRequest `json:"settings"`
+}
+
+// UpdateRoomSettings は部屋の設定を変更する
+// POST /api/rooms/{roomId}/settings
+func (h *Handler) UpdateRoomSettings(w http.ResponseWriter, r *http.Request) {
+	slog.Info("UpdateRoomSettings: リクエスト受信")
+
+	if r.Method != http.MethodPost {
+		slog.Warn("UpdateRoomSettings: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/settings")
+	if roomID == "" {
+		slog.Warn("UpdateRoomSettings: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
+		return
+	}
+
+	// リクエストボディをパース
+	var req UpdateRoomSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Error("UpdateRoomSettings: リクエストボディのパース失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	slog.Info("UpdateRoomSettings: 設定変更処理開始",
+		slog.String("roomId", roomID),
+		slog.String("playerId", playerID))
+
+	output, err := h.updateSettingsUC.Execute(r.Context(), usecase.UpdateRoomSettingsInput{
+		RoomID:   roomID,
+		UserID:   playerID,
+		Settings: req.Settings.toInput(),
+	})
+	if err != nil {
+		slog.Error("UpdateRoomSettings: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.String("playerId", playerID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	slog.Info("UpdateRoomSettings: 設定変更成功",
+		slog.String("roomId", roomID),
+		slog.Any("settings", output.Settings))
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"settings": output.Settings,
+	})
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// DecideTieRequest は同数の決着リクエスト
+type DecideTieRequest struct {
+	PolicyID string `json:"policyId"`
+}
+
+// DecideTie はホストが同数の政策から可決する政策を選ぶ
+// POST /api/rooms/{roomId}/tiebreak
+func (h *Handler) DecideTie(w http.ResponseWriter, r *http.Request) {
+	slog.Info("DecideTie: リクエスト受信")
+
+	if r.Method != http.MethodPost {
+		slog.Warn("DecideTie: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/tiebreak")
+	if roomID == "" {
+		slog.Warn("DecideTie: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
+		return
+	}
+
+	// リクエストボディをパース
+	var req DecideTieRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Error("DecideTie: リクエストボディのパース失敗",
+			slog.String("roomId", roomID),
+			slog.Any("error", err))
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.PolicyID == "" {
+		slog.Warn("DecideTie: policyIdが空",
+			slog.String("roomId", roomID),
+			slog.String("playerId", playerID))
+		respondError(w, http.StatusBadRequest, "policyId is required")
+		return
+	}
+
+	slog.Info("DecideTie: 決着処理開始",
+		slog.String("roomId", roomID),
+		slog.String("playerId", playerID),
+		slog.String("policyId", req.PolicyID))
+
+	output, err := h.decideTieUC.Execute(r.Context(), usecase.DecideTieInput{
+		RoomID:   roomID,
+		UserID:   playerID,
+		PolicyID: req.PolicyID,
+	})
+	if err != nil {
+		slog.Error("DecideTie: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.String("playerId", playerID),
+			slog.String("policyId", req.PolicyID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	slog.Info("DecideTie: 決着成功",
+		slog.String("roomId", roomID),
+		slog.String("passedPolicy", output.Room.LastResult.PassedPolicyTitle),
+		slog.Bool("isGameOver", output.IsGameOver))
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     output.Room.Status,
+		"lastResult": output.Room.LastResult,
+		"cityParams": output.Room.CityParams,
+		"isGameOver": output.IsGameOver,
+	})
+}
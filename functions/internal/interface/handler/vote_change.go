@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// RetractVote は投票を取り消す（集計前・確定前のみ）
+// DELETE /api/rooms/{roomId}/vote
+func (h *Handler) RetractVote(w http.ResponseWriter, r *http.Request) {
+	slog.Info("RetractVote: リクエスト受信")
+
+	if r.Method != http.MethodDelete {
+		slog.Warn("RetractVote: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/vote")
+	if roomID == "" {
+		slog.Warn("RetractVote: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
+		return
+	}
+
+	output, err := h.retractVoteUC.Execute(r.Context(), usecase.RetractVoteInput{
+		RoomID: roomID,
+		UserID: playerID,
+	})
+	if err != nil {
+		slog.Error("RetractVote: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.String("playerId", playerID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	slog.Info("RetractVote: 投票取り消し成功",
+		slog.String("roomId", roomID),
+		slog.String("playerId", playerID),
+		slog.Int("undecided", output.Undecided))
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   output.Success,
+		"undecided": output.Undecided,
+	})
+}
+
+// LockInVote は投票済みの票を確定する
+// POST /api/rooms/{roomId}/lockin
+// requireLockIn の部屋では全員が確定した時点で自動でresolveを実行する
+func (h *Handler) LockInVote(w http.ResponseWriter, r *http.Request) {
+	slog.Info("LockInVote: リクエスト受信")
+
+	if r.Method != http.MethodPost {
+		slog.Warn("LockInVote: 不正なメソッド", slog.String("method", r.Method))
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// URLからroomIdを取得
+	roomID := extractRoomID(r.URL.Path, "/api/rooms/", "/lockin")
+	if roomID == "" {
+		slog.Warn("LockInVote: roomIdが空")
+		respondError(w, http.StatusBadRequest, "room ID is required")
+		return
+	}
+
+	// セッションからプレイヤーを取得
+	playerID, ok := requirePlayer(w, r, roomID)
+	if !ok {
+		return
+	}
+
+	output, err := h.voteUC.Execute(r.Context(), usecase.VoteInput{
+		RoomID: roomID,
+		UserID: playerID,
+		LockIn: true,
+	})
+	if err != nil {
+		slog.Error("LockInVote: ユースケース実行失敗",
+			slog.String("roomId", roomID),
+			slog.String("playerId", playerID),
+			slog.Any("error", err))
+		handleError(w, err)
+		return
+	}
+
+	slog.Info("LockInVote: 投票確定成功",
+		slog.String("roomId", roomID),
+		slog.String("playerId", playerID),
+		slog.Int("undecided", output.Undecided),
+		slog.Bool("isResolved", output.IsResolved))
+	respondJSON(w, http.StatusOK, newVoteResponse(output))
+}
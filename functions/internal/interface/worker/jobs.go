@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/usecase"
+)
+
+// RegisterGameJobs はゲーム進行のジョブと定期処理を登録する
+// ROOM_DEADLINE: 部屋のフェーズの期限切れの処理
+// CITY_IMAGE: 街の画像の生成・アップロードのやり直し
+// 定期処理: 期限を過ぎた部屋をまとめて確認する（ジョブを取りこぼした場合・インスタンス内の待ち行列が消えた場合の保険）
+func RegisterGameJobs(r *Runner, handleTimeoutsUC *usecase.HandleTimeoutsUseCase, retryCityImageUC *usecase.RetryCityImageUseCase, sweepInterval time.Duration) {
+	r.Handle(entity.JobTypeRoomDeadline, func(ctx context.Context, job *entity.Job) error {
+		roomID := job.Payload["roomId"]
+		if roomID == "" {
+			return Permanent(fmt.Errorf("roomId is required"))
+		}
+		_, err := handleTimeoutsUC.Execute(ctx, usecase.HandleTimeoutsInput{RoomID: roomID, Now: time.Now()})
+		return err
+	})
+
+	r.Handle(entity.JobTypeCityImage, func(ctx context.Context, job *entity.Job) error {
+		roomID := job.Payload["roomId"]
+		turn, err := strconv.Atoi(job.Payload["turn"])
+		if roomID == "" || err != nil {
+			return Permanent(fmt.Errorf("invalid city image payload: %v", job.Payload))
+		}
+		_, err = retryCityImageUC.Execute(ctx, usecase.RetryCityImageInput{RoomID: roomID, Turn: turn})
+		return err
+	})
+
+	r.Every("room-timeouts", sweepInterval, func(ctx context.Context, now time.Time) error {
+		output, err := handleTimeoutsUC.Execute(ctx, usecase.HandleTimeoutsInput{Now: now})
+		if err != nil {
+			return err
+		}
+		if len(output.HandledRoomIDs) > 0 {
+			slog.Info("rooms advanced on timeout", slog.Any("roomIds", output.HandledRoomIDs))
+		}
+		return nil
+	})
+}
+
+// CleanupConfig は操作のない部屋の削除の設定
+type CleanupConfig struct {
+	Interval time.Duration  // 削除する部屋を探す間隔
+	TTL      entity.RoomTTL // 操作のない部屋を残しておく時間
+	DryRun   bool           // true なら削除せずにログに出すだけにする（運用時の確認用）
+}
+
+// RegisterRoomCleanup は操作のない部屋を削除する定期処理と、部屋の削除に伴うジョブを登録する
+// DELETE_CITY_IMAGES: 部屋の削除後に失敗した街の画像の削除のやり直し
+func RegisterRoomCleanup(r *Runner, cleanupRoomsUC *usecase.CleanupRoomsUseCase, deleteCityImagesUC *usecase.DeleteCityImagesUseCase, config CleanupConfig) {
+	r.Handle(entity.JobTypeDeleteImages, func(ctx context.Context, job *entity.Job) error {
+		roomID := job.Payload["roomId"]
+		if roomID == "" {
+			return Permanent(fmt.Errorf("roomId is required"))
+		}
+		_, err := deleteCityImagesUC.Execute(ctx, usecase.DeleteCityImagesInput{RoomID: roomID})
+		return err
+	})
+
+	r.Every("room-cleanup", config.Interval, func(ctx context.Context, now time.Time) error {
+		output, err := cleanupRoomsUC.Execute(ctx, usecase.CleanupRoomsInput{Now: now, TTL: config.TTL, DryRun: config.DryRun})
+		if err != nil {
+			return err
+		}
+		if len(output.RoomIDs) > 0 {
+			slog.Info("expired rooms cleaned up", slog.Int("count", len(output.RoomIDs)), slog.Bool("dryRun", config.DryRun))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,234 @@
+// Package worker は API サーバー内でバックグラウンドの処理を行う
+// JobQueue に登録されたジョブ（遅延実行・失敗時のバックオフ付き再試行）と、一定間隔で行う処理を実行する
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// HandlerFunc はジョブを実行する関数
+// リースが切れた場合や再試行で同じジョブが複数回実行されることがあるため、冪等に作ること
+// エラーを返すとバックオフ後に再試行する（Permanent で包んだエラーは再試行しない）
+type HandlerFunc func(ctx context.Context, job *entity.Job) error
+
+// permanentError は再試行しても成功しないエラー
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent は再試行しないエラーとして包む（不正なペイロードなど）
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Config は Runner の設定
+type Config struct {
+	PollInterval time.Duration // 待ち行列を確認する間隔
+	Lease        time.Duration // 取り出したジョブを他のインスタンスに渡さない時間（ジョブの実行時間より長くする）
+	BatchSize    int           // 1回の確認で実行するジョブの最大数（リースは1件ずつ取る）
+	BaseBackoff  time.Duration // 1回目の失敗後の待ち時間（失敗するごとに2倍）
+	MaxBackoff   time.Duration // 待ち時間の上限
+	Retention    time.Duration // 完了・失敗したジョブを残しておく時間（0 なら削除しない）
+	PurgeEvery   time.Duration // 完了・失敗したジョブを削除する間隔
+}
+
+// DefaultConfig はデフォルトの設定を返す
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		Lease:        time.Minute,
+		BatchSize:    20,
+		BaseBackoff:  2 * time.Second,
+		MaxBackoff:   5 * time.Minute,
+		Retention:    24 * time.Hour,
+		PurgeEvery:   time.Hour,
+	}
+}
+
+// purgeBatchSize は1回の削除で消すジョブの最大数（Firestore の1回の書き込みの上限）
+const purgeBatchSize = 500
+
+// periodicTask は一定間隔で行う処理
+type periodicTask struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context, now time.Time) error
+}
+
+// Runner はジョブと定期処理を実行する
+type Runner struct {
+	queue    repository.JobQueue
+	config   Config
+	handlers map[entity.JobType]HandlerFunc
+	periodic []periodicTask
+}
+
+// NewRunner は Runner を作成する
+// config.Retention が正なら、完了・失敗したジョブを定期的に削除する
+func NewRunner(queue repository.JobQueue, config Config) *Runner {
+	r := &Runner{
+		queue:    queue,
+		config:   config,
+		handlers: make(map[entity.JobType]HandlerFunc),
+	}
+	if config.Retention > 0 && config.PurgeEvery > 0 {
+		r.Every("job-purge", config.PurgeEvery, func(ctx context.Context, now time.Time) error {
+			_, err := r.PurgeFinished(ctx, now)
+			return err
+		})
+	}
+	return r
+}
+
+// Handle はジョブの種類ごとのハンドラーを登録する（Run の前に呼ぶ）
+func (r *Runner) Handle(jobType entity.JobType, handler HandlerFunc) {
+	r.handlers[jobType] = handler
+}
+
+// Every は一定間隔で行う処理を登録する（Run の前に呼ぶ）
+// 定期処理はインスタンスごとに動くため、複数インスタンスで重複しても問題ない処理にすること
+func (r *Runner) Every(name string, interval time.Duration, fn func(ctx context.Context, now time.Time) error) {
+	r.periodic = append(r.periodic, periodicTask{name: name, interval: interval, fn: fn})
+}
+
+// Run は ctx がキャンセルされるまでジョブと定期処理を実行する
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, task := range r.periodic {
+		wg.Add(1)
+		go func(task periodicTask) {
+			defer wg.Done()
+			r.runPeriodic(ctx, task)
+		}(task)
+	}
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case now := <-ticker.C:
+			r.RunDue(ctx, now)
+		}
+	}
+}
+
+// runPeriodic は定期処理を interval ごとに実行する
+func (r *Runner) runPeriodic(ctx context.Context, task periodicTask) {
+	ticker := time.NewTicker(task.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := task.fn(ctx, now); err != nil {
+				slog.Warn("periodic task failed", slog.String("task", task.name), slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// RunDue は now の時点で実行できるジョブを取り出して実行し、実行したジョブの数を返す（最大 BatchSize 件）
+// まとめて取り出すと、前のジョブの実行に時間がかかった場合に後のジョブのリースが実行前に切れて重複して実行されるため、
+// 実行する直前に1件ずつ取り出す（リースは前のジョブの実行にかかった時間だけ後から数える）
+func (r *Runner) RunDue(ctx context.Context, now time.Time) int {
+	start := time.Now()
+	for n := 0; n < r.config.BatchSize; n++ {
+		claimAt := now.Add(time.Since(start))
+		jobs, err := r.queue.Claim(ctx, claimAt, r.config.Lease, 1)
+		if err != nil {
+			slog.Warn("failed to claim jobs", slog.Any("error", err))
+			return n
+		}
+		if len(jobs) == 0 {
+			return n
+		}
+		r.execute(ctx, jobs[0], now)
+	}
+	return r.config.BatchSize
+}
+
+// PurgeFinished は完了・失敗してから Retention が過ぎたジョブを削除し、削除した数を返す
+func (r *Runner) PurgeFinished(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		n, err := r.queue.Purge(ctx, now.Add(-r.config.Retention), purgeBatchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < purgeBatchSize {
+			if total > 0 {
+				slog.Info("finished jobs purged", slog.Int("count", total))
+			}
+			return total, nil
+		}
+	}
+}
+
+// execute はジョブを1つ実行し、結果を待ち行列に記録する
+// 成功なら完了、失敗なら最大回数に達するまでバックオフ後に再試行、達したら（または Permanent なら）失敗にする
+// 実行中にリースが切れて他のインスタンスが取り出し直していれば、結果はそちらに任せて記録しない
+func (r *Runner) execute(ctx context.Context, job *entity.Job, now time.Time) {
+	logger := slog.With(slog.String("jobId", job.ID), slog.String("type", string(job.Type)), slog.Int("attempt", job.Attempts))
+
+	err := r.call(ctx, job)
+	if err == nil {
+		if err := r.queue.Complete(ctx, job); err != nil {
+			logResultError(logger, "failed to complete job", err)
+		}
+		return
+	}
+
+	var permanent *permanentError
+	if errors.As(err, &permanent) || job.Exhausted() {
+		logger.Error("job failed", slog.Any("error", err))
+		if err := r.queue.Fail(ctx, job, err.Error()); err != nil {
+			logResultError(logger, "failed to mark job as failed", err)
+		}
+		return
+	}
+
+	retryAt := now.Add(job.Backoff(r.config.BaseBackoff, r.config.MaxBackoff))
+	logger.Warn("job failed, retrying", slog.Time("retryAt", retryAt), slog.Any("error", err))
+	if err := r.queue.Retry(ctx, job, retryAt, err.Error()); err != nil {
+		logResultError(logger, "failed to schedule job retry", err)
+	}
+}
+
+// logResultError はジョブの結果を記録できなかったことをログに出す
+func logResultError(logger *slog.Logger, msg string, err error) {
+	if errors.Is(err, entity.ErrJobLeaseLost) {
+		logger.Warn("job lease was lost before its result was recorded", slog.Any("error", err))
+		return
+	}
+	logger.Warn(msg, slog.Any("error", err))
+}
+
+// call はジョブのハンドラーを呼び出す（ハンドラーの panic もエラーとして扱う）
+func (r *Runner) call(ctx context.Context, job *entity.Job) (err error) {
+	handler, ok := r.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job type %s", job.Type))
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job handler panicked: %v", p)
+		}
+	}()
+	return handler(ctx, job)
+}
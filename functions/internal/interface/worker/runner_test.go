@@ -0,0 +1,292 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/interface/gateway/inmemory"
+)
+
+var base = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func testConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		Lease:        time.Minute,
+		BatchSize:    10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   4 * time.Second,
+	}
+}
+
+func TestRunner_DelayedJob(t *testing.T) {
+	queue := inmemory.NewJobQueue()
+	runner := NewRunner(queue, testConfig())
+	var runs int
+	runner.Handle(entity.JobTypeRoomDeadline, func(ctx context.Context, job *entity.Job) error {
+		runs++
+		return nil
+	})
+
+	ctx := context.Background()
+	job := entity.NewJob("job1", entity.JobTypeRoomDeadline, map[string]string{"roomId": "r1"}, base.Add(10*time.Second))
+	if err := queue.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	// 同じIDは1度しか登録されない
+	if err := queue.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	if n := runner.RunDue(ctx, base); n != 0 || runs != 0 {
+		t.Fatalf("予定時刻より前に実行された（claimed = %d, runs = %d）", n, runs)
+	}
+	if n := runner.RunDue(ctx, base.Add(10*time.Second)); n != 1 || runs != 1 {
+		t.Fatalf("claimed = %d, runs = %d, want 1, 1", n, runs)
+	}
+	// 完了したジョブは再び実行しない
+	if n := runner.RunDue(ctx, base.Add(time.Hour)); n != 0 || runs != 1 {
+		t.Errorf("完了したジョブが再び実行された（claimed = %d, runs = %d）", n, runs)
+	}
+}
+
+func TestRunner_RetryWithBackoff(t *testing.T) {
+	queue := inmemory.NewJobQueue()
+	runner := NewRunner(queue, testConfig())
+	var runs int
+	runner.Handle(entity.JobTypeCityImage, func(ctx context.Context, job *entity.Job) error {
+		runs++
+		return errors.New("upload failed")
+	})
+
+	ctx := context.Background()
+	job := entity.NewJob("job1", entity.JobTypeCityImage, nil, base)
+	job.MaxAttempts = 3
+	if err := queue.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// 1回目の失敗後は1秒、2回目の失敗後は2秒待つ
+	now := base
+	runner.RunDue(ctx, now)
+	if n := runner.RunDue(ctx, now.Add(999*time.Millisecond)); n != 0 {
+		t.Fatalf("バックオフ中に再試行された")
+	}
+	now = now.Add(time.Second)
+	runner.RunDue(ctx, now)
+	if n := runner.RunDue(ctx, now.Add(time.Second)); n != 0 {
+		t.Fatalf("2回目の失敗後のバックオフが2倍になっていない")
+	}
+	now = now.Add(2 * time.Second)
+	runner.RunDue(ctx, now)
+	if runs != 3 {
+		t.Fatalf("runs = %d, want 3", runs)
+	}
+
+	// 最大回数まで失敗したら実行しない
+	if n := runner.RunDue(ctx, now.Add(time.Hour)); n != 0 || runs != 3 {
+		t.Errorf("最大回数の後に実行された（claimed = %d, runs = %d）", n, runs)
+	}
+}
+
+func TestRunner_PermanentAndUnknownJobsAreNotRetried(t *testing.T) {
+	queue := inmemory.NewJobQueue()
+	runner := NewRunner(queue, testConfig())
+	var runs int
+	runner.Handle(entity.JobTypeRoomDeadline, func(ctx context.Context, job *entity.Job) error {
+		runs++
+		return Permanent(errors.New("bad payload"))
+	})
+
+	ctx := context.Background()
+	for _, job := range []*entity.Job{
+		entity.NewJob("permanent", entity.JobTypeRoomDeadline, nil, base),
+		entity.NewJob("unknown", "UNKNOWN", nil, base),
+	} {
+		if err := queue.Enqueue(ctx, job); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	if n := runner.RunDue(ctx, base); n != 2 {
+		t.Fatalf("claimed = %d, want 2", n)
+	}
+	if n := runner.RunDue(ctx, base.Add(time.Hour)); n != 0 || runs != 1 {
+		t.Errorf("再試行された（claimed = %d, runs = %d）", n, runs)
+	}
+}
+
+func TestRunner_ExpiredLeaseIsReclaimed(t *testing.T) {
+	queue := inmemory.NewJobQueue()
+	ctx := context.Background()
+	if err := queue.Enqueue(ctx, entity.NewJob("job1", entity.JobTypeRoomDeadline, nil, base)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// 別のインスタンスが取り出したまま落ちた
+	claimed, err := queue.Claim(ctx, base, time.Minute, 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("Claim = %v, %v", claimed, err)
+	}
+	if again, _ := queue.Claim(ctx, base.Add(30*time.Second), time.Minute, 10); len(again) != 0 {
+		t.Fatalf("リース中のジョブが取り出された")
+	}
+
+	runner := NewRunner(queue, testConfig())
+	var attempts int
+	runner.Handle(entity.JobTypeRoomDeadline, func(ctx context.Context, job *entity.Job) error {
+		attempts = job.Attempts
+		return nil
+	})
+	if n := runner.RunDue(ctx, base.Add(time.Minute)); n != 1 || attempts != 2 {
+		t.Errorf("claimed = %d, attempts = %d, want リース切れで2回目の実行", n, attempts)
+	}
+}
+
+func TestRunner_HandlerPanicIsRetried(t *testing.T) {
+	queue := inmemory.NewJobQueue()
+	runner := NewRunner(queue, testConfig())
+	var runs int
+	runner.Handle(entity.JobTypeRoomDeadline, func(ctx context.Context, job *entity.Job) error {
+		runs++
+		if runs == 1 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	ctx := context.Background()
+	if err := queue.Enqueue(ctx, entity.NewJob("job1", entity.JobTypeRoomDeadline, nil, base)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	runner.RunDue(ctx, base)
+	runner.RunDue(ctx, base.Add(time.Second))
+	if runs != 2 {
+		t.Errorf("runs = %d, want panic 後に再試行", runs)
+	}
+}
+
+// claimRecorder は取り出しのたびに取り出した件数を記録する
+type claimRecorder struct {
+	repository.JobQueue
+	claims []int
+}
+
+func (q *claimRecorder) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Job, error) {
+	jobs, err := q.JobQueue.Claim(ctx, now, lease, limit)
+	q.claims = append(q.claims, len(jobs))
+	return jobs, err
+}
+
+func TestRunner_LeaseIsTakenPerJob(t *testing.T) {
+	queue := &claimRecorder{JobQueue: inmemory.NewJobQueue()}
+	runner := NewRunner(queue, testConfig())
+	var runs int
+	runner.Handle(entity.JobTypeRoomDeadline, func(ctx context.Context, job *entity.Job) error {
+		runs++
+		return nil
+	})
+
+	ctx := context.Background()
+	for _, id := range []string{"job1", "job2", "job3"} {
+		if err := queue.Enqueue(ctx, entity.NewJob(id, entity.JobTypeRoomDeadline, nil, base)); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	// 前のジョブの実行中に後のジョブのリースが切れないよう、実行する直前に1件ずつ取り出す
+	if n := runner.RunDue(ctx, base); n != 3 || runs != 3 {
+		t.Fatalf("claimed = %d, runs = %d, want 3, 3", n, runs)
+	}
+	if want := []int{1, 1, 1, 0}; !reflect.DeepEqual(queue.claims, want) {
+		t.Errorf("claims = %v, want %v", queue.claims, want)
+	}
+}
+
+func TestRunner_LostLeaseDoesNotOverwriteResult(t *testing.T) {
+	queue := inmemory.NewJobQueue()
+	runner := NewRunner(queue, testConfig())
+	ctx := context.Background()
+	if err := queue.Enqueue(ctx, entity.NewJob("job1", entity.JobTypeRoomDeadline, nil, base)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// 1回目の実行が長引いてリースが切れ、その間に他のインスタンスが取り出して完了させる
+	var first bool
+	runner.Handle(entity.JobTypeRoomDeadline, func(ctx context.Context, job *entity.Job) error {
+		if !first {
+			first = true
+			jobs, err := queue.Claim(ctx, base.Add(2*time.Minute), time.Minute, 1)
+			if err != nil || len(jobs) != 1 {
+				t.Fatalf("Claim = %v, %v, want リース切れのジョブ", jobs, err)
+			}
+			if err := queue.Complete(ctx, jobs[0]); err != nil {
+				t.Fatalf("Complete: %v", err)
+			}
+			return errors.New("slow handler failed")
+		}
+		return nil
+	})
+	runner.RunDue(ctx, base)
+
+	// 古いリースの結果（再試行）で完了が上書きされない
+	if n := runner.RunDue(ctx, base.Add(time.Hour)); n != 0 {
+		t.Errorf("claimed = %d, want 完了したジョブは再び実行されない", n)
+	}
+
+	// 古いリースのままでは結果を記録できない
+	job := entity.NewJob("job2", entity.JobTypeRoomDeadline, nil, base)
+	if err := queue.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	stale, _ := queue.Claim(ctx, base, time.Minute, 1)
+	if _, err := queue.Claim(ctx, base.Add(2*time.Minute), time.Minute, 1); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if err := queue.Complete(ctx, stale[0]); !errors.Is(err, entity.ErrJobLeaseLost) {
+		t.Errorf("Complete = %v, want ErrJobLeaseLost", err)
+	}
+}
+
+func TestRunner_PurgeFinishedJobs(t *testing.T) {
+	queue := inmemory.NewJobQueue()
+	config := testConfig()
+	config.Retention = time.Hour
+	runner := NewRunner(queue, config)
+	runner.Handle(entity.JobTypeRoomDeadline, func(ctx context.Context, job *entity.Job) error {
+		return nil
+	})
+
+	ctx := context.Background()
+	if err := queue.Enqueue(ctx, entity.NewJob("done", entity.JobTypeRoomDeadline, nil, base)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := queue.Enqueue(ctx, entity.NewJob("pending", entity.JobTypeRoomDeadline, nil, base.Add(48*time.Hour))); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	runner.RunDue(ctx, base)
+
+	// 保持期間内のジョブは残す（updatedAt は実時刻のため、実時刻を基準にする）
+	now := time.Now()
+	if n, err := runner.PurgeFinished(ctx, now); err != nil || n != 0 {
+		t.Fatalf("PurgeFinished = %d, %v, want 保持期間内は削除しない", n, err)
+	}
+	if n, err := runner.PurgeFinished(ctx, now.Add(2*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("PurgeFinished = %d, %v, want 完了したジョブだけ削除", n, err)
+	}
+
+	// 削除したジョブと同じIDは再び登録できる（未完了のジョブは残っている）
+	if err := queue.Enqueue(ctx, entity.NewJob("done", entity.JobTypeRoomDeadline, nil, base.Add(time.Hour))); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if n := runner.RunDue(ctx, base.Add(time.Hour)); n != 1 {
+		t.Errorf("claimed = %d, want 削除後に登録し直したジョブを実行", n)
+	}
+	if n := runner.RunDue(ctx, base.Add(48*time.Hour)); n != 1 {
+		t.Errorf("claimed = %d, want 未完了のジョブは削除されない", n)
+	}
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// CleanupRoomsInput は操作のない部屋の削除の入力
+type CleanupRoomsInput struct {
+	Now    time.Time
+	TTL    entity.RoomTTL // 操作のない部屋を残しておく時間
+	DryRun bool           // true なら削除せずに、削除する部屋を返すだけにする
+}
+
+// CleanupRoomsOutput は操作のない部屋の削除の出力
+type CleanupRoomsOutput struct {
+	RoomIDs []string // 削除した部屋（DryRun の場合は削除する部屋）
+}
+
+// CleanupRoomsUseCase は一定時間操作のない部屋を削除するユースケース
+// 定期処理から呼び出す（エンドポイントはない）
+type CleanupRoomsUseCase struct {
+	roomRepo     repository.RoomRepository
+	codeRepo     repository.RoomCodeRepository
+	transactor   repository.Transactor
+	imageStorage service.ImageStorage
+	jobs         repository.JobQueue
+}
+
+// NewCleanupRoomsUseCase は CleanupRoomsUseCase を作成する
+func NewCleanupRoomsUseCase(
+	roomRepo repository.RoomRepository,
+	codeRepo repository.RoomCodeRepository,
+	transactor repository.Transactor,
+	imageStorage service.ImageStorage,
+	jobs repository.JobQueue,
+) *CleanupRoomsUseCase {
+	return &CleanupRoomsUseCase{
+		roomRepo:     roomRepo,
+		codeRepo:     codeRepo,
+		transactor:   transactor,
+		imageStorage: imageStorage,
+		jobs:         jobs,
+	}
+}
+
+// Execute は残しておく時間（LOBBY・VOTING・RESULT は ttl.Idle、FINISHED は ttl.Finished）を過ぎた部屋を削除する
+// 部屋ごとにプレイヤーのサブコレクションも含めて削除して部屋コードを解放し、コミット後に街の画像も削除する
+// 1つの部屋で失敗しても他の部屋の処理は続ける（失敗した部屋は次の呼び出しで再び処理される）
+func (uc *CleanupRoomsUseCase) Execute(ctx context.Context, input CleanupRoomsInput) (*CleanupRoomsOutput, error) {
+	roomIDs, err := uc.roomRepo.FindIDsInactiveSince(ctx, input.Now.Add(-input.TTL.Min()))
+	if err != nil {
+		return nil, err
+	}
+
+	output := &CleanupRoomsOutput{RoomIDs: make([]string, 0, len(roomIDs))}
+	for _, roomID := range roomIDs {
+		expired, err := uc.deleteRoom(ctx, roomID, input)
+		if err != nil {
+			slog.Warn("failed to clean up room", slog.String("roomId", roomID), slog.Any("error", err))
+			continue
+		}
+		if !expired {
+			continue
+		}
+		output.RoomIDs = append(output.RoomIDs, roomID)
+		slog.Info("expired room cleaned up", slog.String("roomId", roomID), slog.Bool("dryRun", input.DryRun))
+	}
+	return output, nil
+}
+
+// deleteRoom は残しておく時間を過ぎた部屋を削除する
+// 部屋の読み取りから削除までは1つのトランザクションで行う（削除の直前に操作された部屋は残す）
+// 残しておく時間を過ぎていなければ（既に削除されていれば）何もせず false を返す
+func (uc *CleanupRoomsUseCase) deleteRoom(ctx context.Context, roomID string, input CleanupRoomsInput) (bool, error) {
+	expired := false
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		expired = false
+
+		room, err := uc.roomRepo.FindByID(ctx, roomID)
+		if err != nil {
+			return err
+		}
+		if room == nil || !room.Expired(input.Now, input.TTL) {
+			return nil
+		}
+		expired = true
+
+		if input.DryRun {
+			return nil
+		}
+		return deleteRoomAndCode(ctx, uc.roomRepo, uc.codeRepo, roomID, room)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if expired && !input.DryRun {
+		deleteCityImages(ctx, uc.imageStorage, uc.jobs, roomID)
+	}
+	return expired, nil
+}
+
+// deleteRoomAndCode は部屋を削除し、部屋コードの予約を解放して再利用できるようにする
+// トランザクション内で部屋を読み取った後に呼び出す（書き込みのみ）
+func deleteRoomAndCode(ctx context.Context, roomRepo repository.RoomRepository, codeRepo repository.RoomCodeRepository, roomID string, room *entity.Room) error {
+	if err := roomRepo.Delete(ctx, roomID); err != nil {
+		return err
+	}
+	if room.Code == "" {
+		// 部屋コードの導入前に作成された部屋
+		return nil
+	}
+	return codeRepo.Release(ctx, room.Code)
+}
+
+// deleteCityImages は削除した部屋の街の画像を削除する
+// 部屋は削除済みのため失敗してもユースケースは失敗させず、画像が残らないようジョブで後からやり直す
+func deleteCityImages(ctx context.Context, imageStorage service.ImageStorage, jobs repository.JobQueue, roomID string) {
+	if imageStorage == nil {
+		return
+	}
+	if err := imageStorage.DeleteCityImages(ctx, roomID); err != nil {
+		slog.Warn("failed to delete city images", slog.String("roomId", roomID), slog.Any("error", err))
+		enqueueJob(ctx, jobs, entity.NewDeleteCityImagesJob(roomID, time.Now().Add(cityImageRetryDelay)))
+	}
+}
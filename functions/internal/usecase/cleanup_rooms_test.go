@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestCleanupRoomsUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	now := time.Now()
+	ttl := entity.RoomTTL{Idle: time.Hour, Finished: 24 * time.Hour}
+
+	// 操作から1時間経った LOBBY の部屋
+	idleRoom := env.createRoom(t)
+	env.join(t, idleRoom, "p1")
+	env.updateRoom(t, idleRoom, func(room *entity.Room) { room.Touch(now.Add(-time.Hour)) })
+
+	// 操作から1時間経ったが FINISHED なので残す部屋
+	finishedRoom := env.createRoom(t)
+	env.updateRoom(t, finishedRoom, func(room *entity.Room) {
+		room.Finish()
+		room.Touch(now.Add(-time.Hour))
+	})
+
+	// 最近操作された部屋
+	activeRoom := env.createRoom(t)
+
+	input := CleanupRoomsInput{Now: now, TTL: ttl, DryRun: true}
+	output, err := env.cleanupRoomsUC().Execute(ctx, input)
+	assertErr(t, err, nil)
+	if !reflect.DeepEqual(output.RoomIDs, []string{idleRoom}) {
+		t.Fatalf("RoomIDs = %v, want [%s]", output.RoomIDs, idleRoom)
+	}
+	if env.room(t, idleRoom) == nil || len(env.imageStorage.deleted) != 0 {
+		t.Fatal("dry run で部屋か画像が削除された")
+	}
+
+	input.DryRun = false
+	output, err = env.cleanupRoomsUC().Execute(ctx, input)
+	assertErr(t, err, nil)
+	if !reflect.DeepEqual(output.RoomIDs, []string{idleRoom}) {
+		t.Fatalf("RoomIDs = %v, want [%s]", output.RoomIDs, idleRoom)
+	}
+	if room, _ := env.roomRepo.FindByID(ctx, idleRoom); room != nil {
+		t.Error("部屋が削除されていない")
+	}
+	if p, _ := env.playerRepo.FindByID(ctx, idleRoom, "p1"); p != nil {
+		t.Error("プレイヤーが削除されていない")
+	}
+	if !reflect.DeepEqual(env.imageStorage.deleted, []string{idleRoom}) {
+		t.Errorf("画像を削除した部屋 = %v, want [%s]", env.imageStorage.deleted, idleRoom)
+	}
+	for _, roomID := range []string{finishedRoom, activeRoom} {
+		if room, _ := env.roomRepo.FindByID(ctx, roomID); room == nil {
+			t.Errorf("残すべき部屋 %s が削除された", roomID)
+		}
+	}
+
+	// FINISHED の部屋も Finished を過ぎれば削除する
+	output, err = env.cleanupRoomsUC().Execute(ctx, CleanupRoomsInput{Now: now.Add(24 * time.Hour), TTL: ttl})
+	assertErr(t, err, nil)
+	if len(output.RoomIDs) != 2 {
+		t.Errorf("RoomIDs = %v, want 2部屋", output.RoomIDs)
+	}
+}
+
+func TestCleanupRoomsUseCase_PlayerActionsKeepRoom(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+	env.join(t, roomID, "p1")
+
+	past := time.Now().Add(-time.Hour)
+	env.updateRoom(t, roomID, func(room *entity.Room) { room.Touch(past) })
+	_, err := env.toggleReadyUC().Execute(ctx, ToggleReadyInput{RoomID: roomID, UserID: "p1"})
+	assertErr(t, err, nil)
+	if !env.room(t, roomID).LastActivityAt.After(past) {
+		t.Error("Ready の切り替えで lastActivityAt が更新されていない")
+	}
+
+	roomID = env.startedRoom(t, "p1")
+	env.updateRoom(t, roomID, func(room *entity.Room) { room.Touch(past) })
+	_, err = env.voteUC().Execute(ctx, VoteInput{RoomID: roomID, UserID: "p1", PolicyID: env.room(t, roomID).CurrentPolicyIDs[0]})
+	assertErr(t, err, nil)
+	if !env.room(t, roomID).LastActivityAt.After(past) {
+		t.Error("投票で lastActivityAt が更新されていない")
+	}
+}
+
+func TestCleanupRoomsUseCase_ImageDeletionFailureIsRetried(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	now := time.Now()
+	ttl := entity.RoomTTL{Idle: time.Hour, Finished: time.Hour}
+
+	roomID := env.createRoom(t)
+	env.updateRoom(t, roomID, func(room *entity.Room) { room.Touch(now.Add(-time.Hour)) })
+
+	// 画像の削除に失敗しても部屋は削除する
+	env.imageStorage.deleteErr = errors.New("gcs unavailable")
+	output, err := env.cleanupRoomsUC().Execute(ctx, CleanupRoomsInput{Now: now, TTL: ttl})
+	assertErr(t, err, nil)
+	if room, _ := env.roomRepo.FindByID(ctx, roomID); room != nil || !reflect.DeepEqual(output.RoomIDs, []string{roomID}) {
+		t.Fatalf("RoomIDs = %v, want 画像の削除に失敗しても部屋を削除", output.RoomIDs)
+	}
+
+	// 画像の削除をやり直すジョブが予約される
+	jobs, err := env.jobs.Claim(ctx, time.Now().Add(time.Minute), time.Minute, 10)
+	assertErr(t, err, nil)
+	if len(jobs) != 1 || jobs[0].Type != entity.JobTypeDeleteImages || jobs[0].Payload["roomId"] != roomID {
+		t.Fatalf("jobs = %+v, want 部屋の DELETE_CITY_IMAGES ジョブ", jobs)
+	}
+
+	env.imageStorage.deleteErr = nil
+	_, err = env.deleteCityImagesUC().Execute(ctx, DeleteCityImagesInput{RoomID: jobs[0].Payload["roomId"]})
+	assertErr(t, err, nil)
+	if !reflect.DeepEqual(env.imageStorage.deleted, []string{roomID}) {
+		t.Errorf("画像を削除した部屋 = %v, want [%s]", env.imageStorage.deleted, roomID)
+	}
+}
@@ -2,7 +2,8 @@ package usecase
 
 import (
 	"context"
-	"math/rand"
+	"errors"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
@@ -12,13 +13,24 @@ import (
 type CreateRoomInput struct {
 	UserID      string
 	DisplayName string
+	Settings    RoomSettingsInput     // 指定しなかった項目はデフォルト値
+	Visibility  entity.RoomVisibility // 空なら PRIVATE
+	Password    string                // 空ならパスワードなし
+	InviteOnly  bool                  // 招待がないと参加できない
+	Seed        *int64                // 部屋の乱数の種（不具合の再現用。nil ならランダム）
 }
 
 // CreateRoomOutput は部屋作成の出力
 type CreateRoomOutput struct {
-	RoomID   string
-	Status   entity.RoomStatus
-	PlayerID string
+	RoomID      string
+	RoomCode    string // 口頭で伝えるための短い部屋コード
+	Status      entity.RoomStatus
+	Visibility  entity.RoomVisibility
+	HasPassword bool
+	InviteOnly  bool
+	PlayerID    string
+	RejoinCode  string // セッションを失ったときに同じ席に戻るためのコード（この応答でのみ返す）
+	Settings    entity.RoomSettings
 }
 
 // CreateRoomUseCase は部屋作成のユースケース
@@ -27,6 +39,8 @@ type CreateRoomUseCase struct {
 	roomRepo     repository.RoomRepository
 	playerRepo   repository.PlayerRepository
 	ideologyRepo repository.IdeologyRepository
+	codeRepo     repository.RoomCodeRepository
+	transactor   repository.Transactor
 }
 
 // NewCreateRoomUseCase は CreateRoomUseCase を作成する
@@ -34,19 +48,37 @@ func NewCreateRoomUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
 	ideologyRepo repository.IdeologyRepository,
+	codeRepo repository.RoomCodeRepository,
+	transactor repository.Transactor,
 ) *CreateRoomUseCase {
 	return &CreateRoomUseCase{
 		roomRepo:     roomRepo,
 		playerRepo:   playerRepo,
 		ideologyRepo: ideologyRepo,
+		codeRepo:     codeRepo,
+		transactor:   transactor,
 	}
 }
 
 // Execute は部屋を作成する
-// 1. 新しい部屋を作成
-// 2. ホストプレイヤーを追加
-// 3. 思想をランダムに割り当て
+// 1. 設定・公開範囲・パスワードを検証（指定しなかった項目はデフォルト値。パスワードはハッシュのみ保存）
+// 2. 新しい部屋を非公開で作成（seed の指定があれば使う）
+// 3. 部屋コードを予約
+// 4. 思想をランダムに割り当て（crypto/rand）
+// 5. ホストプレイヤーを追加（再参加コードを発行）し、部屋コード・votes・公開範囲を部屋に反映
+// 5 は1つのトランザクションで行い、部屋を読み直してから反映する（完成前の部屋はロビーの一覧に出さず、
+// その間に部屋に書き込まれた内容も上書きしない）。3・5 に失敗したら作成した部屋と部屋コードの予約を削除する
 func (uc *CreateRoomUseCase) Execute(ctx context.Context, input CreateRoomInput) (*CreateRoomOutput, error) {
+	// 設定を検証
+	settings := input.Settings.applyTo(entity.DefaultRoomSettings())
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	visibility, err := entity.ParseRoomVisibility(string(input.Visibility))
+	if err != nil {
+		return nil, err
+	}
+
 	// 思想を取得
 	ideologies, err := uc.ideologyRepo.GetAll(ctx)
 	if err != nil {
@@ -56,35 +88,88 @@ func (uc *CreateRoomUseCase) Execute(ctx context.Context, input CreateRoomInput)
 		return nil, entity.ErrNoIdeologyAvailable
 	}
 
-	// ランダムに思想を選択
-	selectedIdeology := ideologies[rand.Intn(len(ideologies))]
-
 	// 新しい部屋を作成
-	room := entity.NewRoom(input.UserID)
+	room := entity.NewRoom(input.UserID, settings)
+	room.Visibility = visibility
+	room.InviteOnly = input.InviteOnly
+	if err := room.SetPassword(input.Password); err != nil {
+		return nil, err
+	}
+	if input.Seed != nil {
+		room.Seed = *input.Seed
+	}
 
-	// 部屋を保存
-	roomID, err := uc.roomRepo.Create(ctx, room)
+	// 思想を選択（秘匿情報のため部屋の乱数は使わない）
+	selectedIdeology, err := entity.PickIdeology(ideologies)
 	if err != nil {
 		return nil, err
 	}
 
 	// ホストプレイヤーを作成
 	player := entity.NewPlayer(input.DisplayName, true, &selectedIdeology)
-
-	// プレイヤーを保存
-	if err := uc.playerRepo.Create(ctx, roomID, input.UserID, player); err != nil {
+	rejoinCode, err := entity.NewRejoinCode()
+	if err != nil {
 		return nil, err
 	}
+	player.SetRejoinCode(rejoinCode)
 
-	// votesマップにホストを追加
-	room.Votes[input.UserID] = ""
-	if err := uc.roomRepo.Update(ctx, roomID, room); err != nil {
+	// 部屋を保存（ホストが揃うまではロビーの一覧・クイックマッチに出さないよう非公開にしておく）
+	room.Visibility = entity.RoomVisibilityPrivate
+	roomID, err := uc.roomRepo.Create(ctx, room)
+	if err != nil {
 		return nil, err
 	}
 
+	// 部屋コードを予約（部屋IDが決まってから予約する）
+	code, err := allocateRoomCode(ctx, uc.codeRepo, uc.roomRepo, uc.transactor, roomID, time.Now())
+	if err != nil {
+		return nil, uc.abandon(ctx, roomID, "", err)
+	}
+
+	// ホストプレイヤーを保存し、部屋を完成させる
+	err = uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		room, err = uc.roomRepo.FindByID(ctx, roomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+		if err := uc.playerRepo.Create(ctx, roomID, input.UserID, player); err != nil {
+			return err
+		}
+		room.Code = code
+		room.Visibility = visibility
+		room.Votes[input.UserID] = ""
+		return uc.roomRepo.Update(ctx, roomID, room)
+	})
+	if err != nil {
+		return nil, uc.abandon(ctx, roomID, code, err)
+	}
+
 	return &CreateRoomOutput{
-		RoomID:   roomID,
-		Status:   room.Status,
-		PlayerID: input.UserID,
+		RoomID:      roomID,
+		RoomCode:    room.Code,
+		Status:      room.Status,
+		Visibility:  room.Visibility,
+		HasPassword: room.HasPassword(),
+		InviteOnly:  room.InviteOnly,
+		PlayerID:    input.UserID,
+		RejoinCode:  rejoinCode,
+		Settings:    room.Settings,
 	}, nil
 }
+
+// abandon は作成に失敗した部屋と部屋コードの予約を削除し、元のエラーを返す（code が空なら予約はない）
+func (uc *CreateRoomUseCase) abandon(ctx context.Context, roomID, code string, err error) error {
+	errs := []error{err}
+	if delErr := uc.roomRepo.Delete(ctx, roomID); delErr != nil {
+		errs = append(errs, delErr)
+	}
+	if code != "" {
+		if relErr := uc.codeRepo.Release(ctx, code); relErr != nil {
+			errs = append(errs, relErr)
+		}
+	}
+	return errors.Join(errs...)
+}
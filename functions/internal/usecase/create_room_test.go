@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+func TestCreateRoomUseCase_Execute(t *testing.T) {
+	env := newTestEnv(t)
+
+	out, err := env.createRoomUC().Execute(context.Background(), CreateRoomInput{UserID: "host", DisplayName: "ホスト"})
+	assertErr(t, err, nil)
+
+	if out.RoomID == "" {
+		t.Fatal("RoomID is empty")
+	}
+	if out.Status != entity.RoomStatusLobby {
+		t.Errorf("Status = %s, want %s", out.Status, entity.RoomStatusLobby)
+	}
+	if out.PlayerID != "host" {
+		t.Errorf("PlayerID = %s, want host", out.PlayerID)
+	}
+
+	room := env.room(t, out.RoomID)
+	if room.HostID != "host" {
+		t.Errorf("HostID = %s, want host", room.HostID)
+	}
+	if _, ok := room.Votes["host"]; !ok {
+		t.Error("votes にホストが登録されていない")
+	}
+
+	host := env.player(t, out.RoomID, "host")
+	if !host.IsHost {
+		t.Error("作成者がホストになっていない")
+	}
+	if host.Ideology == nil {
+		t.Error("ホストに思想が割り当てられていない")
+	}
+}
+
+func TestCreateRoomUseCase_SeedReproducible(t *testing.T) {
+	// 同じ seed で同じ操作をすれば、山札の並びが同じになる
+	play := func() *entity.Room {
+		env := newTestEnv(t)
+		seed := int64(42)
+		out, err := env.createRoomUC().Execute(context.Background(), CreateRoomInput{UserID: "host", DisplayName: "ホスト", Seed: &seed})
+		assertErr(t, err, nil)
+		env.join(t, out.RoomID, "p1", "p2")
+		if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: out.RoomID, UserID: "host"}); err != nil {
+			t.Fatalf("StartGame: %v", err)
+		}
+		return env.room(t, out.RoomID)
+	}
+
+	room1 := play()
+	room2 := play()
+	if room1.Seed != 42 {
+		t.Errorf("seed = %d, want 42", room1.Seed)
+	}
+	if !reflect.DeepEqual(room1.CurrentPolicyIDs, room2.CurrentPolicyIDs) || !reflect.DeepEqual(room1.DeckIDs, room2.DeckIDs) {
+		t.Errorf("deck = %v %v / %v %v, want 同じ", room1.CurrentPolicyIDs, room1.DeckIDs, room2.CurrentPolicyIDs, room2.DeckIDs)
+	}
+}
+
+// reserveHookCodeRepo は最初の部屋コードの予約の後に onReserve を呼ぶ RoomCodeRepository
+// 部屋の作成の途中（部屋を保存してから完成させるまで）に他の操作を割り込ませるために使う
+type reserveHookCodeRepo struct {
+	repository.RoomCodeRepository
+	onReserve func(code, roomID string)
+}
+
+func (r *reserveHookCodeRepo) Reserve(ctx context.Context, code, roomID string, at time.Time) error {
+	if err := r.RoomCodeRepository.Reserve(ctx, code, roomID, at); err != nil {
+		return err
+	}
+	if hook := r.onReserve; hook != nil {
+		r.onReserve = nil
+		hook(code, roomID)
+	}
+	return nil
+}
+
+func TestCreateRoomUseCase_JoinDuringCreate(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	var quickMatched *QuickMatchOutput
+	env.codeRepo = &reserveHookCodeRepo{
+		RoomCodeRepository: env.codeRepo,
+		onReserve: func(code, roomID string) {
+			// 完成前の公開の部屋はロビーの一覧・クイックマッチに出さない
+			lobbies, err := env.listLobbiesUC().Execute(ctx, ListLobbiesInput{})
+			assertErr(t, err, nil)
+			for _, lobby := range lobbies.Lobbies {
+				if lobby.RoomID == roomID {
+					t.Errorf("完成前の部屋がロビーの一覧に出ている: %+v", lobby)
+				}
+			}
+			quickMatched, err = env.quickMatchUC().Execute(ctx, QuickMatchInput{UserID: "matcher", DisplayName: "matcher"})
+			assertErr(t, err, nil)
+
+			// 部屋IDを知っているプレイヤーが完成前に参加しても、その書き込みは失われない
+			env.join(t, roomID, "early")
+		},
+	}
+
+	out, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト", Visibility: entity.RoomVisibilityPublic})
+	assertErr(t, err, nil)
+	if quickMatched == nil || quickMatched.RoomID == out.RoomID {
+		t.Fatalf("クイックマッチ = %+v, want 完成前の部屋には参加しない", quickMatched)
+	}
+
+	room := env.room(t, out.RoomID)
+	if room.Visibility != entity.RoomVisibilityPublic || room.Code == "" || room.Code != out.RoomCode {
+		t.Errorf("visibility = %s, code = %q, want PUBLIC・部屋コードあり", room.Visibility, room.Code)
+	}
+	for _, userID := range []string{"host", "early"} {
+		if _, ok := room.Votes[userID]; !ok {
+			t.Errorf("votes に %s がいない: %v", userID, room.Votes)
+		}
+	}
+	if host := env.player(t, out.RoomID, "host"); !host.IsHost {
+		t.Error("作成者がホストになっていない")
+	}
+
+	// 完成後はロビーの一覧に出る
+	lobbies, err := env.listLobbiesUC().Execute(ctx, ListLobbiesInput{})
+	assertErr(t, err, nil)
+	found := false
+	for _, lobby := range lobbies.Lobbies {
+		found = found || lobby.RoomID == out.RoomID
+	}
+	if !found {
+		t.Error("完成した公開の部屋がロビーの一覧に出ていない")
+	}
+}
+
+// failingPlayerRepo はプレイヤーの作成に失敗する PlayerRepository
+type failingPlayerRepo struct {
+	repository.PlayerRepository
+}
+
+func (r *failingPlayerRepo) Create(ctx context.Context, roomID, userID string, player *entity.Player) error {
+	return errors.New("write failed")
+}
+
+func TestCreateRoomUseCase_CleansUpOnPlayerFailure(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	var roomID, code string
+	env.codeRepo = &reserveHookCodeRepo{
+		RoomCodeRepository: env.codeRepo,
+		onReserve: func(reserved, id string) {
+			code, roomID = reserved, id
+		},
+	}
+	uc := NewCreateRoomUseCase(env.roomRepo, &failingPlayerRepo{PlayerRepository: env.playerRepo}, env.ideologyRepo, env.codeRepo, env.transactor)
+	if _, err := uc.Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト"}); err == nil {
+		t.Fatal("プレイヤーの作成に失敗したのにエラーが返されていない")
+	}
+
+	// 部屋も部屋コードの予約も残さない
+	if room, _ := env.roomRepo.FindByID(ctx, roomID); room != nil {
+		t.Error("作成に失敗した部屋が残っている")
+	}
+	if reserved, _ := env.codeRepo.FindRoomID(ctx, code); reserved != "" {
+		t.Errorf("部屋コード %s の予約が残っている（%s）", code, reserved)
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// DecideTieInput はホストによる同数の決着の入力
+type DecideTieInput struct {
+	RoomID   string
+	UserID   string // ホストチェック用
+	PolicyID string // 同数の政策のうち可決する政策
+}
+
+// DecideTieOutput はホストによる同数の決着の出力
+type DecideTieOutput struct {
+	Room       *entity.Room
+	IsGameOver bool
+}
+
+// DecideTieUseCase はホストが同数の政策から可決する政策を選ぶユースケース（tieBreakRule が HOST_DECIDES の場合）
+// POST /api/rooms/{roomId}/tiebreak
+type DecideTieUseCase struct {
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	transactor repository.Transactor
+	resolver   *turnResolver
+	publisher  service.EventPublisher
+}
+
+// NewDecideTieUseCase は DecideTieUseCase を作成する
+func NewDecideTieUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+	policyRepo repository.PolicyRepository,
+	transactor repository.Transactor,
+	imageGenerator service.ImageGenerator,
+	imageStorage service.ImageStorage,
+	jobs repository.JobQueue,
+	publisher service.EventPublisher,
+) *DecideTieUseCase {
+	return &DecideTieUseCase{
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+		transactor: transactor,
+		resolver: &turnResolver{
+			roomRepo:       roomRepo,
+			policyRepo:     policyRepo,
+			imageGenerator: imageGenerator,
+			imageStorage:   imageStorage,
+			jobs:           jobs,
+		},
+		publisher: publisher,
+	}
+}
+
+// Execute はホストが選んだ政策を可決し、結果を反映する
+// 1. ホストであることを確認
+// 2. ホストが同数の政策から選ぶのを待っていることを確認
+// 3. 選んだ政策が同数の政策に含まれることを確認
+// 4. 政策の効果を適用し、lastResult を設定（集計のラウンドは同数になった投票のもの）
+// 1〜4 は1つのトランザクションで行う
+// 5. コミット後に街の画像を生成
+func (uc *DecideTieUseCase) Execute(ctx context.Context, input DecideTieInput) (*DecideTieOutput, error) {
+	var output *DecideTieOutput
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		// 部屋を取得
+		room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+
+		// ホストチェック
+		if room.HostID != input.UserID {
+			return entity.ErrNotHost
+		}
+
+		// ホストの選択を待っている場合のみ
+		if room.Status != entity.RoomStatusVoting || !room.AwaitingHost() {
+			return entity.ErrInvalidPhase
+		}
+
+		// 同数の政策から選んでいるか
+		valid := false
+		for _, policyID := range room.PendingTie.PolicyIDs {
+			if policyID == input.PolicyID {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return entity.ErrInvalidPolicy
+		}
+
+		// プレイヤーを取得（最終結果の記録に使う）
+		players, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+
+		isGameOver, err := uc.resolver.apply(ctx, room, players, input.PolicyID, nil)
+		if err != nil {
+			return err
+		}
+
+		// 部屋を更新
+		room.Touch(time.Now())
+		if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
+			return err
+		}
+
+		output = &DecideTieOutput{
+			Room:       room,
+			IsGameOver: isGameOver,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 結果発表の期限に期限切れの処理を予約
+	scheduleDeadline(ctx, uc.resolver.jobs, input.RoomID, output.Room)
+
+	// 街の画像を生成（外部API呼び出しのためコミット後に行う）
+	uc.resolver.attachCityImage(ctx, input.RoomID, output.Room)
+
+	publishTurnResolved(ctx, uc.publisher, input.RoomID, output.Room, output.IsGameOver)
+
+	return output, nil
+}
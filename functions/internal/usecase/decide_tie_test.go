@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestDecideTieUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		policyID string
+		pending  bool
+		wantErr  error
+	}{
+		{name: "ホストが同数の政策から選ぶ", userID: "host", policyID: "policy_002", pending: true},
+		{name: "ホスト以外は選べない", userID: "p1", policyID: "policy_002", pending: true, wantErr: entity.ErrNotHost},
+		{name: "同数でない政策は選べない", userID: "host", policyID: "policy_003", pending: true, wantErr: entity.ErrInvalidPolicy},
+		{name: "決着待ちでなければ選べない", userID: "host", policyID: "policy_002", wantErr: entity.ErrInvalidPhase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := env.startedRoom(t, "p1")
+			env.updateRoom(t, roomID, func(room *entity.Room) {
+				room.Settings.TieBreakRule = entity.TieBreakHostDecides
+				room.CurrentPolicyIDs = []string{"policy_001", "policy_002", "policy_003"}
+				room.Votes = map[string]string{"host": "policy_001", "p1": "policy_002"}
+			})
+			if tt.pending {
+				if _, err := env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID}); err != nil {
+					t.Fatalf("ResolveVote: %v", err)
+				}
+			}
+
+			out, err := env.decideTieUC().Execute(context.Background(), DecideTieInput{RoomID: roomID, UserID: tt.userID, PolicyID: tt.policyID})
+			assertErr(t, err, tt.wantErr)
+			room := env.room(t, roomID)
+			if tt.wantErr != nil {
+				if room.Status != entity.RoomStatusVoting {
+					t.Errorf("status = %s, want VOTING のまま", room.Status)
+				}
+				return
+			}
+
+			if out.Room.Status != entity.RoomStatusResult || room.Status != entity.RoomStatusResult {
+				t.Errorf("status = %s, want RESULT", room.Status)
+			}
+			if room.PendingTie != nil {
+				t.Error("決着後も pendingTie が残っている")
+			}
+			if room.LastResult.PassedPolicyID != tt.policyID {
+				t.Errorf("passedPolicyId = %s, want %s", room.LastResult.PassedPolicyID, tt.policyID)
+			}
+			if len(room.LastResult.Rounds) != 1 || room.LastResult.Rounds[0].Scores["policy_001"] != 1 {
+				t.Errorf("rounds = %+v, want 同数になった投票の集計", room.LastResult.Rounds)
+			}
+			if !env.publisher.has(entity.RoomEventTurnResolved) {
+				t.Error("TURN_RESOLVED が配信されていない")
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// DeleteCityImagesInput は街の画像の削除のやり直しの入力
+type DeleteCityImagesInput struct {
+	RoomID string // 削除済みの部屋
+}
+
+// DeleteCityImagesOutput は街の画像の削除のやり直しの出力
+type DeleteCityImagesOutput struct {
+	Success bool
+}
+
+// DeleteCityImagesUseCase は部屋の削除後に失敗した街の画像の削除をやり直すユースケース
+// DELETE_CITY_IMAGES ジョブから呼び出す（エンドポイントはない）
+type DeleteCityImagesUseCase struct {
+	imageStorage service.ImageStorage
+}
+
+// NewDeleteCityImagesUseCase は DeleteCityImagesUseCase を作成する
+func NewDeleteCityImagesUseCase(imageStorage service.ImageStorage) *DeleteCityImagesUseCase {
+	return &DeleteCityImagesUseCase{
+		imageStorage: imageStorage,
+	}
+}
+
+// Execute は部屋の街の画像をすべて削除する（既に削除されていても成功する）
+// 失敗した場合はエラーを返す（ジョブを再試行させる）
+func (uc *DeleteCityImagesUseCase) Execute(ctx context.Context, input DeleteCityImagesInput) (*DeleteCityImagesOutput, error) {
+	if uc.imageStorage == nil {
+		return &DeleteCityImagesOutput{Success: true}, nil
+	}
+	if err := uc.imageStorage.DeleteCityImages(ctx, input.RoomID); err != nil {
+		return nil, err
+	}
+	return &DeleteCityImagesOutput{Success: true}, nil
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// publishEvent はイベントを配信する（publisher が未設定の場合は何もしない）
+func publishEvent(ctx context.Context, publisher service.EventPublisher, event entity.RoomEvent) {
+	if publisher == nil {
+		return
+	}
+	publisher.Publish(ctx, event)
+}
+
+// publishTurnResolved は投票集計完了（とゲーム終了）のイベントを配信する
+func publishTurnResolved(ctx context.Context, publisher service.EventPublisher, roomID string, room *entity.Room, isGameOver bool) {
+	event := entity.NewRoomEvent(entity.RoomEventTurnResolved, roomID, room)
+	if room.LastResult != nil {
+		// Base64画像はイベントに含めない（cityImageUrl を使う）
+		lastResult := *room.LastResult
+		lastResult.CityImage = ""
+		event.Data["lastResult"] = lastResult
+	}
+	event.Data["cityParams"] = room.CityParams
+	event.Data["isGameOver"] = isGameOver
+	publishEvent(ctx, publisher, event)
+
+	if isGameOver {
+		finished := entity.NewRoomEvent(entity.RoomEventGameFinished, roomID, room)
+		finished.Data["finalResult"] = room.FinalResult
+		publishEvent(ctx, publisher, finished)
+	}
+}
+
+// publishTiePending は同数で決着待ちになったことを配信する
+func publishTiePending(ctx context.Context, publisher service.EventPublisher, roomID string, room *entity.Room) {
+	event := entity.NewRoomEvent(entity.RoomEventTiePending, roomID, room)
+	event.Data["pendingTie"] = room.PendingTie
+	publishEvent(ctx, publisher, event)
+}
+
+// publishTurnAdvanced は次ターンへの移行（提示できる政策が尽きた場合はゲーム終了）のイベントを配信する
+func publishTurnAdvanced(ctx context.Context, publisher service.EventPublisher, roomID string, room *entity.Room, turn turnAdvance) {
+	if turn.isGameOver {
+		finished := entity.NewRoomEvent(entity.RoomEventGameFinished, roomID, room)
+		finished.Data["finalResult"] = room.FinalResult
+		publishEvent(ctx, publisher, finished)
+		return
+	}
+	event := entity.NewRoomEvent(entity.RoomEventTurnAdvanced, roomID, room)
+	event.Data["currentPolicyIds"] = room.CurrentPolicyIDs
+	event.Data["reshuffled"] = turn.reshuffled
+	publishEvent(ctx, publisher, event)
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// finishGame はゲームを終了し、最終結果（スコア・順位・思想の公開）を部屋に記録する
+// 部屋の保存は呼び出し元で行う
+func finishGame(room *entity.Room, players []*repository.PlayerWithID) {
+	playerMap := make(map[string]*entity.Player, len(players))
+	for _, p := range players {
+		playerMap[p.UserID] = p.Player
+	}
+
+	room.Finish()
+	room.FinalResult = entity.NewFinalResult(room.EndReason(), room.CityParams, playerMap)
+}
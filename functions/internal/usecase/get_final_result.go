@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// GetFinalResultInput は最終結果取得の入力
+type GetFinalResultInput struct {
+	RoomID string
+}
+
+// GetFinalResultOutput は最終結果取得の出力
+type GetFinalResultOutput struct {
+	FinalResult *entity.FinalResult
+}
+
+// GetFinalResultUseCase は最終結果取得のユースケース
+// GET /api/rooms/{roomId}/result
+type GetFinalResultUseCase struct {
+	roomRepo repository.RoomRepository
+}
+
+// NewGetFinalResultUseCase は GetFinalResultUseCase を作成する
+func NewGetFinalResultUseCase(
+	roomRepo repository.RoomRepository,
+) *GetFinalResultUseCase {
+	return &GetFinalResultUseCase{
+		roomRepo: roomRepo,
+	}
+}
+
+// Execute は最終結果を取得する
+// 1. FINISHED状態であることを確認
+// 2. 保存済みの finalResult を返す（再計算はしない）
+func (uc *GetFinalResultUseCase) Execute(ctx context.Context, input GetFinalResultInput) (*GetFinalResultOutput, error) {
+	// 部屋を取得
+	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, entity.ErrRoomNotFound
+	}
+
+	// FINISHED状態でないと結果を取得できない
+	if room.Status != entity.RoomStatusFinished || room.FinalResult == nil {
+		return nil, entity.ErrGameNotFinished
+	}
+
+	return &GetFinalResultOutput{
+		FinalResult: room.FinalResult,
+	}, nil
+}
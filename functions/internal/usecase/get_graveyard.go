@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// GetGraveyardInput は政策の墓場取得の入力
+type GetGraveyardInput struct {
+	RoomID string
+}
+
+// GraveyardEntry は可決されなかった政策の記録と政策本体の組
+type GraveyardEntry struct {
+	Rejected entity.RejectedPolicy
+	Policy   *entity.MasterPolicy
+}
+
+// GetGraveyardOutput は政策の墓場取得の出力
+// effects の除外（ゲーム終了前）はプレゼンテーション層（handler）で行う
+type GetGraveyardOutput struct {
+	Room    *entity.Room
+	Entries []GraveyardEntry // 提示されたターン順
+}
+
+// GetGraveyardUseCase は政策の墓場取得のユースケース
+// GET /api/rooms/{roomId}/graveyard
+type GetGraveyardUseCase struct {
+	roomRepo   repository.RoomRepository
+	policyRepo repository.PolicyRepository
+}
+
+// NewGetGraveyardUseCase は GetGraveyardUseCase を作成する
+func NewGetGraveyardUseCase(
+	roomRepo repository.RoomRepository,
+	policyRepo repository.PolicyRepository,
+) *GetGraveyardUseCase {
+	return &GetGraveyardUseCase{
+		roomRepo:   roomRepo,
+		policyRepo: policyRepo,
+	}
+}
+
+// Execute は可決されなかった政策の一覧を取得する
+// 1. 部屋を取得
+// 2. rejectedPolicies の政策を展開（AI生成 → マスターの順で探す）
+func (uc *GetGraveyardUseCase) Execute(ctx context.Context, input GetGraveyardInput) (*GetGraveyardOutput, error) {
+	// 部屋を取得
+	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, entity.ErrRoomNotFound
+	}
+
+	entries := make([]GraveyardEntry, 0, len(room.RejectedPolicies))
+	for _, rejected := range room.RejectedPolicies {
+		policy, err := findPolicy(ctx, room, uc.policyRepo, rejected.PolicyID)
+		if err != nil {
+			return nil, err
+		}
+		if policy == nil {
+			return nil, entity.ErrPolicyNotFound
+		}
+		entries = append(entries, GraveyardEntry{
+			Rejected: rejected,
+			Policy:   policy,
+		})
+	}
+
+	return &GetGraveyardOutput{
+		Room:    room,
+		Entries: entries,
+	}, nil
+}
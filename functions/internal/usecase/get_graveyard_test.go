@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestGetGraveyardUseCase_Execute(t *testing.T) {
+	for _, recycle := range []bool{true, false} {
+		env := newTestEnv(t)
+		roomID := env.createRoom(t)
+		env.join(t, roomID, "p1")
+		if _, err := env.updateSettingsUC().Execute(context.Background(), UpdateRoomSettingsInput{
+			RoomID: roomID, UserID: "host", Settings: RoomSettingsInput{RecycleRejected: &recycle},
+		}); err != nil {
+			t.Fatalf("UpdateRoomSettings: %v", err)
+		}
+		if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+			t.Fatalf("StartGame: %v", err)
+		}
+
+		options := env.room(t, roomID).CurrentPolicyIDs
+		env.voteAll(t, roomID, map[string]string{"host": options[0], "p1": options[1]})
+		passed := env.room(t, roomID).LastResult.PassedPolicyID
+
+		out, err := env.getGraveyardUC().Execute(context.Background(), GetGraveyardInput{RoomID: roomID})
+		if err != nil {
+			t.Fatalf("recycle=%v: Execute: %v", recycle, err)
+		}
+		if len(out.Entries) != 2 {
+			t.Fatalf("recycle=%v: entries = %d, want 2", recycle, len(out.Entries))
+		}
+		totalVotes := 0
+		for _, e := range out.Entries {
+			if e.Rejected.PolicyID == passed {
+				t.Errorf("recycle=%v: 可決された %s が墓場にある", recycle, passed)
+			}
+			if e.Policy == nil || e.Policy.PolicyID != e.Rejected.PolicyID || e.Rejected.Turn != 1 || e.Rejected.Recycled != recycle {
+				t.Errorf("recycle=%v: entry = %+v", recycle, e)
+			}
+			totalVotes += e.Rejected.Votes
+		}
+		if totalVotes != 1 {
+			t.Errorf("recycle=%v: 否決された政策の得票合計 = %d, want 1", recycle, totalVotes)
+		}
+
+		discard := env.room(t, roomID).DiscardIDs
+		if recycle && len(discard) != 2 {
+			t.Errorf("再利用する設定なのに捨て札に入っていない: %v", discard)
+		}
+		if !recycle && len(discard) != 0 {
+			t.Errorf("再利用しない設定なのに捨て札に入った: %v", discard)
+		}
+	}
+}
+
+func TestGetGraveyardUseCase_RoomNotFound(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.getGraveyardUC().Execute(context.Background(), GetGraveyardInput{RoomID: "missing"})
+	assertErr(t, err, entity.ErrRoomNotFound)
+}
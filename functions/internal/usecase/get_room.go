@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// GetRoomInput は部屋情報取得の入力
+type GetRoomInput struct {
+	RoomID string
+}
+
+// GetRoomOutput は部屋情報取得の出力
+// 秘匿情報の除外はプレゼンテーション層（handler）で行う
+type GetRoomOutput struct {
+	Room           *entity.Room
+	Players        []*repository.PlayerWithID
+	CurrentOptions []entity.PolicyOption // currentPolicyIds を展開したもの（effects は含まない）
+}
+
+// GetRoomUseCase は部屋情報取得のユースケース
+// GET /api/rooms/{roomId}
+type GetRoomUseCase struct {
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	policyRepo repository.PolicyRepository
+}
+
+// NewGetRoomUseCase は GetRoomUseCase を作成する
+func NewGetRoomUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+	policyRepo repository.PolicyRepository,
+) *GetRoomUseCase {
+	return &GetRoomUseCase{
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+		policyRepo: policyRepo,
+	}
+}
+
+// Execute は部屋情報を取得する
+// 1. 部屋を取得
+// 2. プレイヤー一覧を取得
+// 3. currentPolicyIds を PolicyOption に展開
+func (uc *GetRoomUseCase) Execute(ctx context.Context, input GetRoomInput) (*GetRoomOutput, error) {
+	// 部屋を取得
+	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, entity.ErrRoomNotFound
+	}
+
+	// プレイヤー一覧を取得
+	players, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 提示中の政策を展開（AI生成 → マスターの順で探す）
+	options := make([]entity.PolicyOption, 0, len(room.CurrentPolicyIDs))
+	for _, policyID := range room.CurrentPolicyIDs {
+		policy, err := findPolicy(ctx, room, uc.policyRepo, policyID)
+		if err != nil {
+			return nil, err
+		}
+		if policy == nil {
+			return nil, entity.ErrPolicyNotFound
+		}
+		options = append(options, policy.ToOption())
+	}
+
+	return &GetRoomOutput{
+		Room:           room,
+		Players:        players,
+		CurrentOptions: options,
+	}, nil
+}
+
+// GetPlayersInput はプレイヤー一覧取得の入力
+type GetPlayersInput struct {
+	RoomID string
+}
+
+// GetPlayersOutput はプレイヤー一覧取得の出力
+type GetPlayersOutput struct {
+	Room    *entity.Room
+	Players []*repository.PlayerWithID
+}
+
+// GetPlayersUseCase はプレイヤー一覧取得のユースケース
+// GET /api/rooms/{roomId}/players
+type GetPlayersUseCase struct {
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+}
+
+// NewGetPlayersUseCase は GetPlayersUseCase を作成する
+func NewGetPlayersUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+) *GetPlayersUseCase {
+	return &GetPlayersUseCase{
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+	}
+}
+
+// Execute はプレイヤー一覧を取得する
+func (uc *GetPlayersUseCase) Execute(ctx context.Context, input GetPlayersInput) (*GetPlayersOutput, error) {
+	// 部屋を取得（投票済みかの判定に votes を使う）
+	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, entity.ErrRoomNotFound
+	}
+
+	players, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPlayersOutput{
+		Room:    room,
+		Players: players,
+	}, nil
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// HandleTimeoutsInput は制限時間切れの処理の入力
+type HandleTimeoutsInput struct {
+	Now    time.Time // この時刻までに期限を過ぎた部屋を処理する
+	RoomID string    // 指定した場合はその部屋だけを処理する（期限に予約したジョブから呼ぶ）
+}
+
+// HandleTimeoutsOutput は制限時間切れの処理の出力
+type HandleTimeoutsOutput struct {
+	HandledRoomIDs []string // 期限切れで進行させた部屋
+}
+
+// HandleTimeoutsUseCase は制限時間（settings.votingTimeLimit・settings.resultTimeLimit）を過ぎた部屋を進行させるユースケース
+// 期限に予約したジョブ（ROOM_DEADLINE）と、ジョブの取りこぼしを拾う定期処理から呼び出す（エンドポイントはない）
+type HandleTimeoutsUseCase struct {
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	transactor repository.Transactor
+	resolver   *turnResolver
+	publisher  service.EventPublisher
+}
+
+// NewHandleTimeoutsUseCase は HandleTimeoutsUseCase を作成する
+func NewHandleTimeoutsUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+	policyRepo repository.PolicyRepository,
+	transactor repository.Transactor,
+	imageGenerator service.ImageGenerator,
+	imageStorage service.ImageStorage,
+	jobs repository.JobQueue,
+	publisher service.EventPublisher,
+) *HandleTimeoutsUseCase {
+	return &HandleTimeoutsUseCase{
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+		transactor: transactor,
+		resolver: &turnResolver{
+			roomRepo:       roomRepo,
+			policyRepo:     policyRepo,
+			imageGenerator: imageGenerator,
+			imageStorage:   imageStorage,
+			jobs:           jobs,
+		},
+		publisher: publisher,
+	}
+}
+
+// timeoutOutcome は1部屋の制限時間切れの処理の結果を表す
+type timeoutOutcome struct {
+	room        *entity.Room
+	phase       entity.RoomStatus // 期限を過ぎたフェーズ
+	filledVotes []string          // timeoutVote が RANDOM で投票を補ったプレイヤー
+	resolved    turnOutcome       // VOTING の場合の集計結果
+	turn        turnAdvance       // RESULT の場合の次のターンへの移行結果
+}
+
+// Execute は期限を過ぎた部屋を1つずつ進行させる
+// 1つの部屋で失敗しても他の部屋の処理は続ける（失敗した部屋は次の呼び出しで再び処理される）
+// RoomID を指定した場合は、その部屋の失敗をエラーとして返す（ジョブを再試行させる）
+func (uc *HandleTimeoutsUseCase) Execute(ctx context.Context, input HandleTimeoutsInput) (*HandleTimeoutsOutput, error) {
+	if input.RoomID != "" {
+		handled, err := uc.handleRoom(ctx, input.RoomID, input.Now)
+		if err != nil {
+			return nil, err
+		}
+		output := &HandleTimeoutsOutput{HandledRoomIDs: make([]string, 0, 1)}
+		if handled {
+			output.HandledRoomIDs = append(output.HandledRoomIDs, input.RoomID)
+		}
+		return output, nil
+	}
+
+	roomIDs, err := uc.roomRepo.FindIDsPastDeadline(ctx, input.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &HandleTimeoutsOutput{HandledRoomIDs: make([]string, 0, len(roomIDs))}
+	for _, roomID := range roomIDs {
+		handled, err := uc.handleRoom(ctx, roomID, input.Now)
+		if err != nil {
+			slog.Warn("failed to handle room timeout", slog.String("roomId", roomID), slog.Any("error", err))
+			continue
+		}
+		if handled {
+			output.HandledRoomIDs = append(output.HandledRoomIDs, roomID)
+		}
+	}
+	return output, nil
+}
+
+// handleRoom は期限を過ぎた部屋を進行させる
+// VOTING: 未投票のプレイヤーを timeoutVote に従って扱い（棄権 or ランダムに投票）、集計する
+// （ホストが同数の政策から選ぶのを待っている場合は、同数の政策からランダムに選ぶ）
+// RESULT: 次のターンに進める（NextTurnUseCase と同じ）
+// 部屋の読み取りから更新までは1つのトランザクションで行う（クライアントの操作や他のインスタンスと同時でも1回だけ進む）
+// 期限を過ぎていなければ（既に進んでいれば）何もせず false を返す
+func (uc *HandleTimeoutsUseCase) handleRoom(ctx context.Context, roomID string, now time.Time) (bool, error) {
+	var outcome *timeoutOutcome
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		outcome = nil
+
+		room, err := uc.roomRepo.FindByID(ctx, roomID)
+		if err != nil {
+			return err
+		}
+		if room == nil || !room.DeadlinePassed(now) {
+			return nil
+		}
+
+		players, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, roomID)
+		if err != nil {
+			return err
+		}
+
+		result := &timeoutOutcome{room: room, phase: room.Status}
+		switch room.Status {
+		case entity.RoomStatusVoting:
+			if err := uc.resolveOnTimeout(ctx, roomID, room, players, result); err != nil {
+				return err
+			}
+		case entity.RoomStatusResult:
+			result.turn, err = advanceTurn(ctx, uc.playerRepo, roomID, room)
+			if err != nil {
+				return err
+			}
+		default:
+			// 期限が残っているだけなので消す
+			room.Deadline = nil
+		}
+
+		if err := uc.roomRepo.Update(ctx, roomID, room); err != nil {
+			return err
+		}
+		outcome = result
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if outcome == nil {
+		return false, nil
+	}
+
+	// 次の期限に期限切れの処理を予約
+	scheduleDeadline(ctx, uc.resolver.jobs, roomID, outcome.room)
+
+	event := entity.NewRoomEvent(entity.RoomEventPhaseTimedOut, roomID, outcome.room)
+	event.Data["phase"] = outcome.phase
+	event.Data["filledVotes"] = outcome.filledVotes
+	publishEvent(ctx, uc.publisher, event)
+
+	switch outcome.phase {
+	case entity.RoomStatusVoting:
+		if outcome.resolved.resolved {
+			// 街の画像を生成（外部API呼び出しのためコミット後に行う）
+			uc.resolver.attachCityImage(ctx, roomID, outcome.room)
+			publishTurnResolved(ctx, uc.publisher, roomID, outcome.room, outcome.resolved.isGameOver)
+		} else if outcome.room.PendingTie != nil {
+			publishTiePending(ctx, uc.publisher, roomID, outcome.room)
+		}
+	case entity.RoomStatusResult:
+		publishTurnAdvanced(ctx, uc.publisher, roomID, outcome.room, outcome.turn)
+	}
+	return true, nil
+}
+
+// resolveOnTimeout は投票の期限を過ぎた部屋を集計する
+// トランザクション内で呼び出す前提で、部屋の保存は呼び出し元で行う
+func (uc *HandleTimeoutsUseCase) resolveOnTimeout(ctx context.Context, roomID string, room *entity.Room, players []*repository.PlayerWithID, result *timeoutOutcome) error {
+	// ホストが選ばなかった場合は同数の政策からランダムに選ぶ
+	if room.AwaitingHost() {
+		winner := room.PickWinner(entity.VoteTally{Winners: room.PendingTie.PolicyIDs})
+		isGameOver, err := uc.resolver.apply(ctx, room, players, winner, nil)
+		if err != nil {
+			return err
+		}
+		result.resolved = turnOutcome{resolved: true, isGameOver: isGameOver}
+		return nil
+	}
+
+	userIDs := make([]string, 0, len(players))
+	for _, p := range players {
+		userIDs = append(userIDs, p.UserID)
+	}
+	result.filledVotes = room.FillMissingVotes(userIDs)
+
+	outcome, err := uc.resolver.resolveTally(ctx, room, players, room.TallyOnTimeout())
+	if err != nil {
+		return err
+	}
+	result.resolved = outcome
+
+	// 再投票になった場合は全員の投票をリセット、そうでなければ補った投票をプレイヤーに反映する
+	if room.Revoting() && !outcome.resolved {
+		return clearPlayerVotes(ctx, uc.playerRepo, roomID, players)
+	}
+	for _, userID := range result.filledVotes {
+		if err := uc.playerRepo.UpdateCurrentVote(ctx, roomID, userID, room.CurrentBallots()[userID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
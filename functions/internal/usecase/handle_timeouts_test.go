@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+// timedRoom は制限時間のある設定でゲームを開始した部屋を作成する
+func timedRoom(t *testing.T, env *testEnv, timeoutVote entity.TimeoutVote, guests ...string) string {
+	t.Helper()
+	roomID := env.createRoom(t)
+	env.join(t, roomID, guests...)
+	votingTimeLimit, resultTimeLimit := 30, 120
+	if _, err := env.updateSettingsUC().Execute(context.Background(), UpdateRoomSettingsInput{
+		RoomID: roomID,
+		UserID: "host",
+		Settings: RoomSettingsInput{
+			VotingTimeLimit: &votingTimeLimit,
+			ResultTimeLimit: &resultTimeLimit,
+			TimeoutVote:     &timeoutVote,
+		},
+	}); err != nil {
+		t.Fatalf("UpdateRoomSettings: %v", err)
+	}
+	if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+	return roomID
+}
+
+// afterDeadline は部屋の期限を過ぎた時刻を返す
+func afterDeadline(t *testing.T, env *testEnv, roomID string) time.Time {
+	t.Helper()
+	room := env.room(t, roomID)
+	if room.Deadline == nil {
+		t.Fatalf("deadline が設定されていない（status = %s）", room.Status)
+	}
+	return room.Deadline.Add(time.Second)
+}
+
+func TestHandleTimeoutsUseCase_Voting(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeoutVote entity.TimeoutVote
+		wantFilled  int
+	}{
+		{name: "未投票のプレイヤーは棄権として集計する", timeoutVote: entity.TimeoutVoteAbstain, wantFilled: 0},
+		{name: "未投票のプレイヤーはランダムに投票したとみなす", timeoutVote: entity.TimeoutVoteRandom, wantFilled: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := timedRoom(t, env, tt.timeoutVote, "p1", "p2")
+			policyID := env.room(t, roomID).CurrentPolicyIDs[0]
+			if _, err := env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", PolicyID: policyID}); err != nil {
+				t.Fatalf("Vote: %v", err)
+			}
+			now := afterDeadline(t, env, roomID)
+
+			// 期限前は何もしない
+			out, err := env.handleTimeoutsUC().Execute(context.Background(), HandleTimeoutsInput{Now: now.Add(-time.Minute)})
+			assertErr(t, err, nil)
+			if len(out.HandledRoomIDs) != 0 {
+				t.Fatalf("期限前に処理された: %v", out.HandledRoomIDs)
+			}
+
+			out, err = env.handleTimeoutsUC().Execute(context.Background(), HandleTimeoutsInput{Now: now})
+			assertErr(t, err, nil)
+			if len(out.HandledRoomIDs) != 1 || out.HandledRoomIDs[0] != roomID {
+				t.Fatalf("handled = %v, want [%s]", out.HandledRoomIDs, roomID)
+			}
+
+			room := env.room(t, roomID)
+			if room.Status != entity.RoomStatusResult {
+				t.Fatalf("status = %s, want RESULT", room.Status)
+			}
+			if len(room.LastResult.VoteDetails) != 1+tt.wantFilled {
+				t.Errorf("voteDetails = %v, want %d票", room.LastResult.VoteDetails, 1+tt.wantFilled)
+			}
+			if tt.wantFilled == 0 && room.LastResult.PassedPolicyID != policyID {
+				t.Errorf("passedPolicyId = %s, want %s", room.LastResult.PassedPolicyID, policyID)
+			}
+			if tt.wantFilled > 0 && env.player(t, roomID, "p1").CurrentVote == "" {
+				t.Error("補った投票がプレイヤーに反映されていない")
+			}
+			if room.Deadline == nil {
+				t.Error("RESULT の期限が設定されていない")
+			}
+			if !env.publisher.has(entity.RoomEventPhaseTimedOut) || !env.publisher.has(entity.RoomEventTurnResolved) {
+				t.Errorf("events = %v, want PHASE_TIMED_OUT と TURN_RESOLVED", env.publisher.types())
+			}
+
+			// 同じ期限で2回処理しない（RESULT の期限はまだ先）
+			out, err = env.handleTimeoutsUC().Execute(context.Background(), HandleTimeoutsInput{Now: now})
+			assertErr(t, err, nil)
+			if len(out.HandledRoomIDs) != 0 {
+				t.Errorf("2回処理された: %v", out.HandledRoomIDs)
+			}
+		})
+	}
+}
+
+func TestHandleTimeoutsUseCase_NobodyVoted(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := timedRoom(t, env, entity.TimeoutVoteAbstain, "p1")
+	env.updateRoom(t, roomID, func(room *entity.Room) {
+		room.Settings.TieBreakRule = entity.TieBreakFirstOption
+	})
+
+	if _, err := env.handleTimeoutsUC().Execute(context.Background(), HandleTimeoutsInput{Now: afterDeadline(t, env, roomID)}); err != nil {
+		t.Fatalf("HandleTimeouts: %v", err)
+	}
+
+	// 全ての政策が同数になり、FIRST_OPTION で最初の政策が可決される
+	room := env.room(t, roomID)
+	if room.Status != entity.RoomStatusResult || room.LastResult.PassedPolicyID != room.CurrentPolicyIDs[0] {
+		t.Errorf("status = %s, passed = %v, want RESULT で最初の政策", room.Status, room.LastResult)
+	}
+}
+
+func TestHandleTimeoutsUseCase_HostDidNotDecide(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := timedRoom(t, env, entity.TimeoutVoteAbstain, "p1")
+	env.updateRoom(t, roomID, func(room *entity.Room) {
+		room.Settings.TieBreakRule = entity.TieBreakHostDecides
+		room.CurrentPolicyIDs = []string{"policy_001", "policy_002", "policy_003"}
+	})
+	env.voteAll(t, roomID, map[string]string{"host": "policy_001", "p1": "policy_002"})
+	if !env.room(t, roomID).AwaitingHost() {
+		t.Fatal("ホストの選択待ちになっていない")
+	}
+
+	if _, err := env.handleTimeoutsUC().Execute(context.Background(), HandleTimeoutsInput{Now: afterDeadline(t, env, roomID)}); err != nil {
+		t.Fatalf("HandleTimeouts: %v", err)
+	}
+
+	room := env.room(t, roomID)
+	if room.Status != entity.RoomStatusResult || room.PendingTie != nil {
+		t.Fatalf("status = %s, pendingTie = %v, want RESULT で決着", room.Status, room.PendingTie)
+	}
+	if passed := room.LastResult.PassedPolicyID; passed != "policy_001" && passed != "policy_002" {
+		t.Errorf("passedPolicyId = %s, want 同数の政策のどちらか", passed)
+	}
+}
+
+func TestHandleTimeoutsUseCase_Result(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := timedRoom(t, env, entity.TimeoutVoteAbstain, "p1")
+	policyID := env.room(t, roomID).CurrentPolicyIDs[0]
+	env.voteAll(t, roomID, map[string]string{"host": policyID, "p1": policyID})
+
+	if _, err := env.handleTimeoutsUC().Execute(context.Background(), HandleTimeoutsInput{Now: afterDeadline(t, env, roomID)}); err != nil {
+		t.Fatalf("HandleTimeouts: %v", err)
+	}
+
+	room := env.room(t, roomID)
+	if room.Status != entity.RoomStatusVoting || room.Turn != 2 {
+		t.Fatalf("status = %s, turn = %d, want VOTING のターン2", room.Status, room.Turn)
+	}
+	if room.Deadline == nil {
+		t.Error("次のターンの投票の期限が設定されていない")
+	}
+	if env.player(t, roomID, "host").CurrentVote != "" {
+		t.Error("プレイヤーの投票がリセットされていない")
+	}
+	if !env.publisher.has(entity.RoomEventTurnAdvanced) {
+		t.Error("TURN_ADVANCED が配信されていない")
+	}
+}
+
+func TestHandleTimeoutsUseCase_DeadlineJob(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := timedRoom(t, env, entity.TimeoutVoteAbstain, "p1")
+	ctx := context.Background()
+	deadline := *env.room(t, roomID).Deadline
+
+	// ゲーム開始時に投票の期限のジョブが予約される
+	if jobs, _ := env.jobs.Claim(ctx, deadline.Add(-time.Second), time.Minute, 10); len(jobs) != 0 {
+		t.Fatalf("期限前にジョブが取り出された: %+v", jobs)
+	}
+	jobs, err := env.jobs.Claim(ctx, deadline, time.Minute, 10)
+	assertErr(t, err, nil)
+	if len(jobs) != 1 || jobs[0].Type != entity.JobTypeRoomDeadline || jobs[0].Payload["roomId"] != roomID {
+		t.Fatalf("jobs = %+v, want 部屋の ROOM_DEADLINE ジョブ", jobs)
+	}
+
+	// ジョブからは部屋を指定して処理する
+	out, err := env.handleTimeoutsUC().Execute(ctx, HandleTimeoutsInput{RoomID: roomID, Now: deadline})
+	assertErr(t, err, nil)
+	assertErr(t, env.jobs.Complete(ctx, jobs[0]), nil)
+	if len(out.HandledRoomIDs) != 1 || env.room(t, roomID).Status != entity.RoomStatusResult {
+		t.Fatalf("handled = %v, status = %s, want RESULT", out.HandledRoomIDs, env.room(t, roomID).Status)
+	}
+
+	// 結果発表の期限のジョブが続けて予約される
+	resultDeadline := *env.room(t, roomID).Deadline
+	jobs, err = env.jobs.Claim(ctx, resultDeadline, time.Minute, 10)
+	assertErr(t, err, nil)
+	if len(jobs) != 1 || jobs[0].Type != entity.JobTypeRoomDeadline {
+		t.Errorf("jobs = %+v, want 結果発表の期限のジョブ", jobs)
+	}
+}
+
+func TestHandleTimeoutsUseCase_NoTimeLimit(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := env.startedRoom(t, "p1")
+	if env.room(t, roomID).Deadline != nil {
+		t.Fatal("制限時間なしの部屋に期限が設定された")
+	}
+
+	out, err := env.handleTimeoutsUC().Execute(context.Background(), HandleTimeoutsInput{Now: time.Now().Add(time.Hour)})
+	assertErr(t, err, nil)
+	if len(out.HandledRoomIDs) != 0 {
+		t.Errorf("handled = %v, want なし", out.HandledRoomIDs)
+	}
+}
@@ -0,0 +1,391 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+	"github.com/techworld-hackathon/functions/internal/interface/gateway/inmemory"
+)
+
+// testEnv はインメモリのリポジトリと偽の外部サービスで組み立てたユースケース一式
+type testEnv struct {
+	roomRepo     repository.RoomRepository
+	playerRepo   repository.PlayerRepository
+	policyRepo   repository.PolicyRepository
+	ideologyRepo repository.IdeologyRepository
+	codeRepo     repository.RoomCodeRepository
+	transactor   repository.Transactor
+	jobs         repository.JobQueue
+
+	imageGenerator *fakeImageGenerator
+	imageStorage   *fakeImageStorage
+	reviewer       *fakePetitionReviewer
+	moderator      *fakePetitionModerator
+	publisher      *recordingPublisher
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	store := inmemory.NewStore()
+	return &testEnv{
+		roomRepo:       inmemory.NewRoomRepository(store),
+		playerRepo:     inmemory.NewPlayerRepository(store),
+		policyRepo:     inmemory.NewPolicyRepository(store),
+		ideologyRepo:   inmemory.NewIdeologyRepository(store),
+		codeRepo:       inmemory.NewRoomCodeRepository(store),
+		transactor:     inmemory.NewTransactor(store),
+		jobs:           inmemory.NewJobQueue(),
+		imageGenerator: &fakeImageGenerator{},
+		imageStorage:   &fakeImageStorage{},
+		reviewer:       &fakePetitionReviewer{},
+		moderator:      &fakePetitionModerator{relevant: true},
+		publisher:      &recordingPublisher{},
+	}
+}
+
+func (e *testEnv) createRoomUC() *CreateRoomUseCase {
+	return NewCreateRoomUseCase(e.roomRepo, e.playerRepo, e.ideologyRepo, e.codeRepo, e.transactor)
+}
+
+func (e *testEnv) joinRoomUC() *JoinRoomUseCase {
+	return NewJoinRoomUseCase(e.roomRepo, e.playerRepo, e.ideologyRepo, e.codeRepo, e.transactor, e.publisher)
+}
+
+func (e *testEnv) rejoinRoomUC() *RejoinRoomUseCase {
+	return NewRejoinRoomUseCase(e.roomRepo, e.playerRepo, e.transactor, e.publisher)
+}
+
+func (e *testEnv) findRoomByCodeUC() *FindRoomByCodeUseCase {
+	return NewFindRoomByCodeUseCase(e.codeRepo, e.roomRepo, e.playerRepo)
+}
+
+func (e *testEnv) listLobbiesUC() *ListLobbiesUseCase {
+	return NewListLobbiesUseCase(e.roomRepo, e.playerRepo)
+}
+
+func (e *testEnv) quickMatchUC() *QuickMatchUseCase {
+	return NewQuickMatchUseCase(e.roomRepo, e.playerRepo, e.joinRoomUC(), e.createRoomUC())
+}
+
+func (e *testEnv) updateRoomAccessUC() *UpdateRoomAccessUseCase {
+	return NewUpdateRoomAccessUseCase(e.roomRepo, e.transactor)
+}
+
+func (e *testEnv) createInviteUC() *CreateInviteUseCase {
+	return NewCreateInviteUseCase(e.roomRepo, e.transactor)
+}
+
+func (e *testEnv) leaveRoomUC() *LeaveRoomUseCase {
+	return NewLeaveRoomUseCase(e.roomRepo, e.playerRepo, e.codeRepo, e.transactor, e.imageStorage, e.jobs, e.publisher)
+}
+
+func (e *testEnv) toggleReadyUC() *ToggleReadyUseCase {
+	return NewToggleReadyUseCase(e.roomRepo, e.playerRepo, e.publisher)
+}
+
+func (e *testEnv) updateSettingsUC() *UpdateRoomSettingsUseCase {
+	return NewUpdateRoomSettingsUseCase(e.roomRepo, e.playerRepo, e.transactor, e.publisher)
+}
+
+func (e *testEnv) startGameUC() *StartGameUseCase {
+	return NewStartGameUseCase(e.roomRepo, e.playerRepo, e.policyRepo, e.transactor, e.jobs, e.publisher)
+}
+
+func (e *testEnv) voteUC() *VoteUseCase {
+	return NewVoteUseCase(e.roomRepo, e.playerRepo, e.policyRepo, e.transactor, e.imageGenerator, e.imageStorage, e.jobs, e.publisher)
+}
+
+func (e *testEnv) retractVoteUC() *RetractVoteUseCase {
+	return NewRetractVoteUseCase(e.roomRepo, e.playerRepo, e.transactor, e.publisher)
+}
+
+func (e *testEnv) resolveVoteUC() *ResolveVoteUseCase {
+	return NewResolveVoteUseCase(e.roomRepo, e.playerRepo, e.policyRepo, e.transactor, e.imageGenerator, e.imageStorage, e.jobs, e.publisher)
+}
+
+func (e *testEnv) decideTieUC() *DecideTieUseCase {
+	return NewDecideTieUseCase(e.roomRepo, e.playerRepo, e.policyRepo, e.transactor, e.imageGenerator, e.imageStorage, e.jobs, e.publisher)
+}
+
+func (e *testEnv) nextTurnUC() *NextTurnUseCase {
+	return NewNextTurnUseCase(e.roomRepo, e.playerRepo, e.transactor, e.jobs, e.publisher)
+}
+
+func (e *testEnv) handleTimeoutsUC() *HandleTimeoutsUseCase {
+	return NewHandleTimeoutsUseCase(e.roomRepo, e.playerRepo, e.policyRepo, e.transactor, e.imageGenerator, e.imageStorage, e.jobs, e.publisher)
+}
+
+func (e *testEnv) retryCityImageUC() *RetryCityImageUseCase {
+	return NewRetryCityImageUseCase(e.roomRepo, e.policyRepo, e.imageGenerator, e.imageStorage)
+}
+
+func (e *testEnv) cleanupRoomsUC() *CleanupRoomsUseCase {
+	return NewCleanupRoomsUseCase(e.roomRepo, e.codeRepo, e.transactor, e.imageStorage, e.jobs)
+}
+
+func (e *testEnv) deleteCityImagesUC() *DeleteCityImagesUseCase {
+	return NewDeleteCityImagesUseCase(e.imageStorage)
+}
+
+func (e *testEnv) submitPetitionUC() *SubmitPetitionUseCase {
+	return NewSubmitPetitionUseCase(e.roomRepo, e.playerRepo, e.policyRepo, e.reviewer, e.moderator, e.transactor, e.publisher)
+}
+
+func (e *testEnv) getGraveyardUC() *GetGraveyardUseCase {
+	return NewGetGraveyardUseCase(e.roomRepo, e.policyRepo)
+}
+
+// createRoom はホストだけの部屋を作成し、部屋IDを返す（ホストのIDは "host"）
+func (e *testEnv) createRoom(t *testing.T) string {
+	t.Helper()
+	out, err := e.createRoomUC().Execute(context.Background(), CreateRoomInput{UserID: "host", DisplayName: "ホスト"})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	return out.RoomID
+}
+
+// createPublicRoom は hostID がホストの公開の部屋を作成し、部屋IDを返す
+func (e *testEnv) createPublicRoom(t *testing.T, hostID string, settings RoomSettingsInput) string {
+	t.Helper()
+	out, err := e.createRoomUC().Execute(context.Background(), CreateRoomInput{
+		UserID:      hostID,
+		DisplayName: hostID,
+		Settings:    settings,
+		Visibility:  entity.RoomVisibilityPublic,
+	})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	return out.RoomID
+}
+
+// join はプレイヤーを部屋に参加させる
+func (e *testEnv) join(t *testing.T, roomID string, userIDs ...string) {
+	t.Helper()
+	for _, userID := range userIDs {
+		if _, err := e.joinRoomUC().Execute(context.Background(), JoinRoomInput{RoomID: roomID, UserID: userID, DisplayName: userID}); err != nil {
+			t.Fatalf("JoinRoom(%s): %v", userID, err)
+		}
+	}
+}
+
+// startedRoom はホストと guests が参加した部屋でゲームを開始し、部屋IDを返す
+func (e *testEnv) startedRoom(t *testing.T, guests ...string) string {
+	t.Helper()
+	roomID := e.createRoom(t)
+	e.join(t, roomID, guests...)
+	if _, err := e.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+	return roomID
+}
+
+// room は部屋を取得する（存在しなければテストを失敗させる）
+func (e *testEnv) room(t *testing.T, roomID string) *entity.Room {
+	t.Helper()
+	room, err := e.roomRepo.FindByID(context.Background(), roomID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if room == nil {
+		t.Fatalf("room %s not found", roomID)
+	}
+	return room
+}
+
+// player はプレイヤーを取得する（存在しなければテストを失敗させる）
+func (e *testEnv) player(t *testing.T, roomID, userID string) *entity.Player {
+	t.Helper()
+	player, err := e.playerRepo.FindByID(context.Background(), roomID, userID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if player == nil {
+		t.Fatalf("player %s not found in room %s", userID, roomID)
+	}
+	return player
+}
+
+// updateRoom はテストの前提条件を作るために部屋を直接書き換える
+func (e *testEnv) updateRoom(t *testing.T, roomID string, mutate func(room *entity.Room)) {
+	t.Helper()
+	room := e.room(t, roomID)
+	mutate(room)
+	if err := e.roomRepo.Update(context.Background(), roomID, room); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+}
+
+// voteAll は全員を指定した政策に投票させ、最後の投票結果を返す
+func (e *testEnv) voteAll(t *testing.T, roomID string, votes map[string]string) *VoteOutput {
+	t.Helper()
+	var out *VoteOutput
+	for _, userID := range sortedUserIDs(votes) {
+		var err error
+		out, err = e.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: userID, PolicyID: votes[userID]})
+		if err != nil {
+			t.Fatalf("Vote(%s): %v", userID, err)
+		}
+	}
+	return out
+}
+
+func sortedUserIDs(votes map[string]string) []string {
+	ids := make([]string, 0, len(votes))
+	for id := range votes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// assertErr は期待したエラー（nil を含む）が返されたかを確認する
+func assertErr(t *testing.T, got, want error) {
+	t.Helper()
+	if want == nil {
+		if got != nil {
+			t.Fatalf("unexpected error: %v", got)
+		}
+		return
+	}
+	if !errors.Is(got, want) {
+		t.Fatalf("error = %v, want %v", got, want)
+	}
+}
+
+// ============================================================================
+// 外部サービスの偽物
+// ============================================================================
+
+// fakeImageGenerator は固定の画像を返す ImageGenerator
+type fakeImageGenerator struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (f *fakeImageGenerator) GenerateCityImage(ctx context.Context, cityParams *entity.CityParams, passedPolicies []*entity.MasterPolicy) (*service.ImageGenerateResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &service.ImageGenerateResult{Image: base64.StdEncoding.EncodeToString([]byte("png")), Seed: 1}, nil
+}
+
+// fakeImageStorage はアップロードせずに決まった URL を返す ImageStorage
+type fakeImageStorage struct {
+	mu        sync.Mutex
+	uploads   []string
+	deleted   []string // 画像を削除した部屋
+	deleteErr error    // 設定されていれば画像の削除に失敗する
+}
+
+func (f *fakeImageStorage) UploadCityImage(ctx context.Context, roomID string, turn int, imageData []byte) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	url := fmt.Sprintf("https://storage.example.com/%s/turn_%d.png", roomID, turn)
+	f.uploads = append(f.uploads, url)
+	return url, nil
+}
+
+func (f *fakeImageStorage) DeleteCityImages(ctx context.Context, roomID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, roomID)
+	return nil
+}
+
+// fakePetitionReviewer は設定された結果を返す PetitionReviewer
+type fakePetitionReviewer struct {
+	result   *service.PetitionResult
+	err      error
+	calls    int
+	onReview func() // 審査中に他の操作が行われた状況を再現する
+}
+
+func (f *fakePetitionReviewer) ReviewPetition(ctx context.Context, petitionCtx *service.PetitionContext) (*service.PetitionResult, error) {
+	f.calls++
+	if f.onReview != nil {
+		f.onReview()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.result == nil {
+		return &service.PetitionResult{Approved: false, Reason: "テスト用の却下"}, nil
+	}
+	// 呼び出し側で書き換えられても次の呼び出しに影響しないようにコピーを返す
+	result := *f.result
+	if result.Policy != nil {
+		policy := *result.Policy
+		result.Policy = &policy
+	}
+	return &result, nil
+}
+
+// fakePetitionModerator は設定された判定を返す PetitionModerator
+type fakePetitionModerator struct {
+	blockReason string // 空でなければこの理由で拒否する
+	relevant    bool
+}
+
+func (f *fakePetitionModerator) Moderate(ctx context.Context, petitionText string) (*service.ModerationResult, error) {
+	if f.blockReason != "" {
+		return &service.ModerationResult{Allowed: false, Reason: f.blockReason}, nil
+	}
+	return &service.ModerationResult{Allowed: true}, nil
+}
+
+func (f *fakePetitionModerator) CheckRelevance(ctx context.Context, petitionText string, policy *entity.MasterPolicy) (*service.ModerationResult, error) {
+	if !f.relevant {
+		return &service.ModerationResult{Allowed: false, Reason: "提案の内容と関係のない政策になりました"}, nil
+	}
+	return &service.ModerationResult{Allowed: true}, nil
+}
+
+// recordingPublisher は配信されたイベントを記録する EventPublisher
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []entity.RoomEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event entity.RoomEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+// types は配信されたイベントの種類を順に返す
+func (p *recordingPublisher) types() []entity.RoomEventType {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	types := make([]entity.RoomEventType, 0, len(p.events))
+	for _, e := range p.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+// has は指定した種類のイベントが配信されたかを返す
+func (p *recordingPublisher) has(eventType entity.RoomEventType) bool {
+	for _, t := range p.types() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// enqueueJob はジョブを登録する（queue が未設定の場合は何もしない）
+// 登録に失敗してもユースケースは失敗させない（期限切れは定期的な確認でも拾われる）
+func enqueueJob(ctx context.Context, queue repository.JobQueue, job *entity.Job) {
+	if queue == nil || job == nil {
+		return
+	}
+	if err := queue.Enqueue(ctx, job); err != nil {
+		slog.Warn("failed to enqueue job", slog.String("jobId", job.ID), slog.String("type", string(job.Type)), slog.Any("error", err))
+	}
+}
+
+// scheduleDeadline は部屋の現在のフェーズの期限に期限切れの処理を行うジョブを登録する（期限がなければ何もしない）
+// トランザクションのコミット後に呼び出す
+func scheduleDeadline(ctx context.Context, queue repository.JobQueue, roomID string, room *entity.Room) {
+	enqueueJob(ctx, queue, entity.NewRoomDeadlineJob(roomID, room))
+}
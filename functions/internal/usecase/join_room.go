@@ -2,30 +2,40 @@ package usecase
 
 import (
 	"context"
-	"math/rand"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
 )
 
 // JoinRoomInput は部屋参加の入力
+// RoomID の代わりに RoomCode（部屋コード）でも参加できる
 type JoinRoomInput struct {
 	RoomID      string
+	RoomCode    string // RoomID が空の場合に使う
 	UserID      string
 	DisplayName string
+	Password    string // パスワードを設定した部屋に招待なしで参加する場合
+	InviteToken string // ホストが発行した招待（パスワード・招待のみの制限より優先する）
 }
 
 // JoinRoomOutput は部屋参加の出力
 type JoinRoomOutput struct {
-	PlayerID string
+	RoomID     string // 部屋コードで参加した場合の部屋ID
+	PlayerID   string
+	RejoinCode string // セッションを失ったときに同じ席に戻るためのコード（この応答でのみ返す）
 }
 
 // JoinRoomUseCase は部屋参加のユースケース
-// POST /api/rooms/{roomId}/join
+// POST /api/rooms/{roomId}/join, POST /api/codes/{code}/join
 type JoinRoomUseCase struct {
 	roomRepo     repository.RoomRepository
 	playerRepo   repository.PlayerRepository
 	ideologyRepo repository.IdeologyRepository
+	codeRepo     repository.RoomCodeRepository
+	transactor   repository.Transactor
+	publisher    service.EventPublisher
 }
 
 // NewJoinRoomUseCase は JoinRoomUseCase を作成する
@@ -33,101 +43,159 @@ func NewJoinRoomUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
 	ideologyRepo repository.IdeologyRepository,
+	codeRepo repository.RoomCodeRepository,
+	transactor repository.Transactor,
+	publisher service.EventPublisher,
 ) *JoinRoomUseCase {
 	return &JoinRoomUseCase{
 		roomRepo:     roomRepo,
 		playerRepo:   playerRepo,
 		ideologyRepo: ideologyRepo,
+		codeRepo:     codeRepo,
+		transactor:   transactor,
+		publisher:    publisher,
 	}
 }
 
 // Execute は部屋に参加する
-// 1. ルームの存在・状態確認（LOBBYのみ参加可）
+// 0. 部屋コードの指定なら部屋IDに変換（予約の読み取りはトランザクション外）
+// 1. ルームの存在・状態確認（LOBBYのみ参加可）、パスワード・招待の確認
 // 2. 既に参加済みでないか確認
 // 3. 未使用の思想からランダムに割り当て
-// 4. プレイヤーを追加
+// 4. プレイヤーを追加（再参加コードを発行）
 // 5. votesに追加
+// ゲーム開始後に戻るプレイヤーは RejoinRoomUseCase を使う
+// 1〜5 は1つのトランザクションで行う（同時参加での定員超過・思想の重複を防ぐ）
+// パスワード・招待の不一致は部屋に記録してからエラーを返す（多すぎれば entity.ErrTooManyJoinAttempts）
+// 参加に失敗した場合、使った招待は使われなかったことになる
 func (uc *JoinRoomUseCase) Execute(ctx context.Context, input JoinRoomInput) (*JoinRoomOutput, error) {
-	// 部屋を取得
-	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
-	if err != nil {
-		return nil, err
-	}
-	if room == nil {
-		return nil, entity.ErrRoomNotFound
-	}
-
-	// LOBBY状態でないと参加できない
-	if room.Status != entity.RoomStatusLobby {
-		return nil, entity.ErrGameAlreadyStarted
+	if input.RoomID == "" {
+		roomID, err := resolveRoomCode(ctx, uc.codeRepo, input.RoomCode)
+		if err != nil {
+			return nil, err
+		}
+		input.RoomID = roomID
 	}
 
-	// 既に参加済みかチェック
-	existingPlayer, err := uc.playerRepo.FindByID(ctx, input.RoomID, input.UserID)
+	// 全思想を取得（マスターデータのためトランザクション外で読む）
+	allIdeologies, err := uc.ideologyRepo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if existingPlayer != nil {
-		return nil, entity.ErrPlayerAlreadyInRoom
-	}
 
-	// 現在のプレイヤー一覧を取得
-	players, err := uc.playerRepo.FindAllByRoomID(ctx, input.RoomID)
+	rejoinCode, err := entity.NewRejoinCode()
 	if err != nil {
 		return nil, err
 	}
 
-	// プレイヤー上限チェック（最大4人）
-	const maxPlayers = 4
-	if len(players) >= maxPlayers {
-		return nil, entity.ErrRoomFull
-	}
+	var room *entity.Room
+	var player *entity.Player
+	var accessErr error
+	err = uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		accessErr = nil
+
+		// 部屋を取得
+		var err error
+		room, err = uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
 
-	// 全思想を取得
-	allIdeologies, err := uc.ideologyRepo.GetAll(ctx)
-	if err != nil {
-		return nil, err
-	}
+		// LOBBY状態でないと参加できない
+		if room.Status != entity.RoomStatusLobby {
+			return entity.ErrGameAlreadyStarted
+		}
 
-	// 使用済み思想IDを収集
-	usedIdeologyIDs := make(map[string]bool)
-	for _, p := range players {
-		if p.Ideology != nil {
-			usedIdeologyIDs[p.Ideology.IdeologyID] = true
+		// パスワード・招待を確認（推測による失敗はコミットして数える）
+		now := time.Now()
+		if err := room.CheckJoinAccess(input.Password, input.InviteToken, now); err != nil {
+			if !entity.IsJoinFailure(err) {
+				return err
+			}
+			accessErr = err
+			room.RecordJoinFailure(now)
+			return uc.roomRepo.Update(ctx, input.RoomID, room)
 		}
-	}
 
-	// 未使用の思想を収集
-	var availableIdeologies []entity.MasterIdeology
-	for _, ideology := range allIdeologies {
-		if !usedIdeologyIDs[ideology.IdeologyID] {
-			availableIdeologies = append(availableIdeologies, ideology)
+		// 既に参加済みかチェック
+		existingPlayer, err := uc.playerRepo.FindByID(ctx, input.RoomID, input.UserID)
+		if err != nil {
+			return err
+		}
+		if existingPlayer != nil {
+			return entity.ErrPlayerAlreadyInRoom
 		}
-	}
 
-	// 思想が足りない
-	if len(availableIdeologies) == 0 {
-		return nil, entity.ErrRoomFull
-	}
+		// 現在のプレイヤー一覧を取得
+		players, err := uc.playerRepo.FindAllByRoomID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
 
-	// ランダムに思想を選択
-	selectedIdeology := availableIdeologies[rand.Intn(len(availableIdeologies))]
+		// プレイヤー上限チェック（部屋の設定）
+		if len(players) >= room.Settings.MaxPlayers {
+			return entity.ErrRoomFull
+		}
 
-	// プレイヤーを作成
-	player := entity.NewPlayer(input.DisplayName, false, &selectedIdeology)
+		// 使用済み思想IDを収集
+		usedIdeologyIDs := make(map[string]bool)
+		for _, p := range players {
+			if p.Ideology != nil {
+				usedIdeologyIDs[p.Ideology.IdeologyID] = true
+			}
+		}
 
-	// プレイヤーを保存
-	if err := uc.playerRepo.Create(ctx, input.RoomID, input.UserID, player); err != nil {
-		return nil, err
-	}
+		// 未使用の思想を収集
+		var availableIdeologies []entity.MasterIdeology
+		for _, ideology := range allIdeologies {
+			if !usedIdeologyIDs[ideology.IdeologyID] {
+				availableIdeologies = append(availableIdeologies, ideology)
+			}
+		}
+
+		// 思想が足りない
+		if len(availableIdeologies) == 0 {
+			return entity.ErrRoomFull
+		}
+
+		// 思想を選択（秘匿情報のため部屋の乱数は使わない）
+		selectedIdeology, err := entity.PickIdeology(availableIdeologies)
+		if err != nil {
+			return err
+		}
 
-	// votesマップにプレイヤーを追加
-	room.Votes[input.UserID] = ""
-	if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
+		// プレイヤーを作成
+		player = entity.NewPlayer(input.DisplayName, false, &selectedIdeology)
+		player.SetRejoinCode(rejoinCode)
+
+		// プレイヤーを保存
+		if err := uc.playerRepo.Create(ctx, input.RoomID, input.UserID, player); err != nil {
+			return err
+		}
+
+		// votesマップにプレイヤーを追加
+		room.Votes[input.UserID] = ""
+		room.Touch(now)
+		return uc.roomRepo.Update(ctx, input.RoomID, room)
+	})
+	if err != nil {
 		return nil, err
 	}
+	if accessErr != nil {
+		return nil, accessErr
+	}
+
+	event := entity.NewRoomEvent(entity.RoomEventPlayerJoined, input.RoomID, room)
+	event.PlayerID = input.UserID
+	event.Data["displayName"] = player.DisplayName
+	publishEvent(ctx, uc.publisher, event)
 
 	return &JoinRoomOutput{
-		PlayerID: input.UserID,
+		RoomID:     input.RoomID,
+		PlayerID:   input.UserID,
+		RejoinCode: rejoinCode,
 	}, nil
 }
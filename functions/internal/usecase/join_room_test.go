@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestJoinRoomUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, env *testEnv) string // 部屋IDを返す
+		userID  string
+		wantErr error
+	}{
+		{
+			name:   "LOBBYの部屋に参加できる",
+			setup:  func(t *testing.T, env *testEnv) string { return env.createRoom(t) },
+			userID: "guest",
+		},
+		{
+			name:    "存在しない部屋",
+			setup:   func(t *testing.T, env *testEnv) string { return "missing" },
+			userID:  "guest",
+			wantErr: entity.ErrRoomNotFound,
+		},
+		{
+			name:    "ゲーム開始後は参加できない",
+			setup:   func(t *testing.T, env *testEnv) string { return env.startedRoom(t, "p1") },
+			userID:  "guest",
+			wantErr: entity.ErrGameAlreadyStarted,
+		},
+		{
+			name:    "参加済みのプレイヤー",
+			setup:   func(t *testing.T, env *testEnv) string { return env.createRoom(t) },
+			userID:  "host",
+			wantErr: entity.ErrPlayerAlreadyInRoom,
+		},
+		{
+			name: "定員（4人）に達している",
+			setup: func(t *testing.T, env *testEnv) string {
+				roomID := env.createRoom(t)
+				env.join(t, roomID, "p1", "p2", "p3")
+				return roomID
+			},
+			userID:  "guest",
+			wantErr: entity.ErrRoomFull,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := tt.setup(t, env)
+
+			out, err := env.joinRoomUC().Execute(context.Background(), JoinRoomInput{RoomID: roomID, UserID: tt.userID, DisplayName: "ゲスト"})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			if out.PlayerID != tt.userID {
+				t.Errorf("PlayerID = %s, want %s", out.PlayerID, tt.userID)
+			}
+			if _, ok := env.room(t, roomID).Votes[tt.userID]; !ok {
+				t.Error("votes に参加者が登録されていない")
+			}
+			if !env.publisher.has(entity.RoomEventPlayerJoined) {
+				t.Error("PLAYER_JOINED が配信されていない")
+			}
+		})
+	}
+}
+
+func TestJoinRoomUseCase_AssignsDistinctIdeologies(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+	env.join(t, roomID, "p1", "p2", "p3")
+
+	players, err := env.playerRepo.FindAllByRoomID(context.Background(), roomID)
+	assertErr(t, err, nil)
+
+	seen := make(map[string]bool)
+	for _, p := range players {
+		if p.Ideology == nil {
+			t.Fatalf("%s に思想が割り当てられていない", p.DisplayName)
+		}
+		if seen[p.Ideology.IdeologyID] {
+			t.Errorf("思想 %s が重複して割り当てられた", p.Ideology.IdeologyID)
+		}
+		seen[p.Ideology.IdeologyID] = true
+	}
+}
@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
 )
 
 // LeaveRoomInput は部屋退出の入力
@@ -21,84 +23,123 @@ type LeaveRoomOutput struct {
 // LeaveRoomUseCase は部屋退出のユースケース
 // POST /api/rooms/{roomId}/leave
 type LeaveRoomUseCase struct {
-	roomRepo   repository.RoomRepository
-	playerRepo repository.PlayerRepository
+	roomRepo     repository.RoomRepository
+	playerRepo   repository.PlayerRepository
+	codeRepo     repository.RoomCodeRepository
+	transactor   repository.Transactor
+	imageStorage service.ImageStorage
+	jobs         repository.JobQueue
+	publisher    service.EventPublisher
 }
 
 // NewLeaveRoomUseCase は LeaveRoomUseCase を作成する
 func NewLeaveRoomUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
+	codeRepo repository.RoomCodeRepository,
+	transactor repository.Transactor,
+	imageStorage service.ImageStorage,
+	jobs repository.JobQueue,
+	publisher service.EventPublisher,
 ) *LeaveRoomUseCase {
 	return &LeaveRoomUseCase{
-		roomRepo:   roomRepo,
-		playerRepo: playerRepo,
+		roomRepo:     roomRepo,
+		playerRepo:   playerRepo,
+		codeRepo:     codeRepo,
+		transactor:   transactor,
+		imageStorage: imageStorage,
+		jobs:         jobs,
+		publisher:    publisher,
 	}
 }
 
 // Execute は部屋から退出する
 // 1. プレイヤーを削除
 // 2. votesから削除
-// 3. ホストが退出した場合、別のプレイヤーをホストに昇格（または部屋を削除）
+// 3. ホストが退出した場合、別のプレイヤーをホストに昇格（または部屋を削除して部屋コードを解放）
+// 1〜3 は1つのトランザクションで行う
+// 4. 部屋を削除した場合はコミット後に街の画像も削除
 func (uc *LeaveRoomUseCase) Execute(ctx context.Context, input LeaveRoomInput) (*LeaveRoomOutput, error) {
-	// 部屋を取得
-	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
-	if err != nil {
-		return nil, err
-	}
-	if room == nil {
-		return nil, entity.ErrRoomNotFound
-	}
-
-	// プレイヤーを取得
-	player, err := uc.playerRepo.FindByID(ctx, input.RoomID, input.UserID)
-	if err != nil {
-		return nil, err
-	}
-	if player == nil {
-		return nil, entity.ErrPlayerNotInRoom
-	}
-
-	// プレイヤーを削除
-	if err := uc.playerRepo.Delete(ctx, input.RoomID, input.UserID); err != nil {
-		return nil, err
-	}
+	var room *entity.Room
+	roomDeleted := false
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		// 部屋を取得
+		var err error
+		room, err = uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
 
-	// votesから削除
-	delete(room.Votes, input.UserID)
+		// プレイヤーを取得
+		player, err := uc.playerRepo.FindByID(ctx, input.RoomID, input.UserID)
+		if err != nil {
+			return err
+		}
+		if player == nil {
+			return entity.ErrPlayerNotInRoom
+		}
 
-	// 残りのプレイヤーを取得（IDと共に）
-	remainingPlayers, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, input.RoomID)
-	if err != nil {
-		return nil, err
-	}
+		// 残りのプレイヤーを取得（書き込み前に読み取りを済ませる）
+		allPlayers, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		var remainingPlayers []*repository.PlayerWithID
+		for _, p := range allPlayers {
+			if p.UserID != input.UserID {
+				remainingPlayers = append(remainingPlayers, p)
+			}
+		}
 
-	// プレイヤーがいなくなったら部屋を削除
-	if len(remainingPlayers) == 0 {
-		if err := uc.roomRepo.Delete(ctx, input.RoomID); err != nil {
-			return nil, err
+		// プレイヤーがいなくなったら部屋ごと削除（プレイヤーのサブコレクションも削除される）
+		if len(remainingPlayers) == 0 {
+			roomDeleted = true
+			return deleteRoomAndCode(ctx, uc.roomRepo, uc.codeRepo, input.RoomID, room)
 		}
-		return &LeaveRoomOutput{Success: true}, nil
-	}
 
-	// ホストが退出した場合、別のプレイヤーをホストに昇格
-	if player.IsHost {
-		// 最初のプレイヤーを新ホストに
-		newHostData := remainingPlayers[0]
-		newHostData.Player.IsHost = true
+		// プレイヤーを削除
+		if err := uc.playerRepo.Delete(ctx, input.RoomID, input.UserID); err != nil {
+			return err
+		}
 
-		// 新ホストのプレイヤー情報を更新
-		if err := uc.playerRepo.Update(ctx, input.RoomID, newHostData.UserID, newHostData.Player); err != nil {
-			return nil, err
+		// votes・ballots・lockedInから削除
+		delete(room.Votes, input.UserID)
+		delete(room.Ballots, input.UserID)
+		delete(room.LockedIn, input.UserID)
+
+		// ホストが退出した場合、別のプレイヤーをホストに昇格
+		if player.IsHost {
+			// 最初のプレイヤーを新ホストに
+			newHostData := remainingPlayers[0]
+			newHostData.Player.IsHost = true
+
+			// 新ホストのプレイヤー情報を更新
+			if err := uc.playerRepo.Update(ctx, input.RoomID, newHostData.UserID, newHostData.Player); err != nil {
+				return err
+			}
+			room.HostID = newHostData.UserID
 		}
-		room.HostID = newHostData.UserID
-	}
 
-	// 部屋を更新
-	if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
+		// 部屋を更新
+		room.Touch(time.Now())
+		return uc.roomRepo.Update(ctx, input.RoomID, room)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	if roomDeleted {
+		deleteCityImages(ctx, uc.imageStorage, uc.jobs, input.RoomID)
+	} else {
+		event := entity.NewRoomEvent(entity.RoomEventPlayerLeft, input.RoomID, room)
+		event.PlayerID = input.UserID
+		event.Data["hostId"] = room.HostID
+		publishEvent(ctx, uc.publisher, event)
+	}
+
 	return &LeaveRoomOutput{
 		Success: true,
 	}, nil
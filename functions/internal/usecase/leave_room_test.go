@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestLeaveRoomUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		guests       []string
+		userID       string
+		wantErr      error
+		wantDeleted  bool
+		wantHostID   string
+		wantHostFlag string // IsHost が立っているべきプレイヤー
+	}{
+		{
+			name:         "ゲストが退出してもホストは変わらない",
+			guests:       []string{"p1", "p2"},
+			userID:       "p2",
+			wantHostID:   "host",
+			wantHostFlag: "host",
+		},
+		{
+			name:         "ホストが退出すると残りのプレイヤーに引き継がれる",
+			guests:       []string{"p1", "p2"},
+			userID:       "host",
+			wantHostID:   "p1",
+			wantHostFlag: "p1",
+		},
+		{
+			name:        "最後のプレイヤーが退出すると部屋が削除される",
+			userID:      "host",
+			wantDeleted: true,
+		},
+		{
+			name:    "部屋にいないプレイヤー",
+			guests:  []string{"p1"},
+			userID:  "stranger",
+			wantErr: entity.ErrPlayerNotInRoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := env.createRoom(t)
+			env.join(t, roomID, tt.guests...)
+
+			_, err := env.leaveRoomUC().Execute(context.Background(), LeaveRoomInput{RoomID: roomID, UserID: tt.userID})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			room, err := env.roomRepo.FindByID(context.Background(), roomID)
+			assertErr(t, err, nil)
+			if tt.wantDeleted {
+				if room != nil {
+					t.Fatal("部屋が削除されていない")
+				}
+				if env.publisher.has(entity.RoomEventPlayerLeft) {
+					t.Error("削除された部屋に PLAYER_LEFT が配信された")
+				}
+				if !reflect.DeepEqual(env.imageStorage.deleted, []string{roomID}) {
+					t.Errorf("画像を削除した部屋 = %v, want [%s]", env.imageStorage.deleted, roomID)
+				}
+				return
+			}
+			if len(env.imageStorage.deleted) != 0 {
+				t.Errorf("残っている部屋の画像が削除された: %v", env.imageStorage.deleted)
+			}
+
+			if room.HostID != tt.wantHostID {
+				t.Errorf("HostID = %s, want %s", room.HostID, tt.wantHostID)
+			}
+			if _, ok := room.Votes[tt.userID]; ok {
+				t.Error("退出したプレイヤーが votes に残っている")
+			}
+			if !env.player(t, roomID, tt.wantHostFlag).IsHost {
+				t.Errorf("%s の IsHost が立っていない", tt.wantHostFlag)
+			}
+			if p, _ := env.playerRepo.FindByID(context.Background(), roomID, tt.userID); p != nil {
+				t.Error("退出したプレイヤーが削除されていない")
+			}
+		})
+	}
+}
+
+func TestLeaveRoomUseCase_RoomNotFound(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.leaveRoomUC().Execute(context.Background(), LeaveRoomInput{RoomID: "missing", UserID: "host"})
+	assertErr(t, err, entity.ErrRoomNotFound)
+}
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// DefaultLobbyLimit は公開ロビーの一覧で返す部屋の数（指定しなかった場合）
+const DefaultLobbyLimit = 20
+
+// MaxLobbyLimit は公開ロビーの一覧で返す部屋の数の上限
+const MaxLobbyLimit = 50
+
+// lobbyScanLimit は満員の部屋を除く前に読み取る公開ロビーの数
+const lobbyScanLimit = 2 * MaxLobbyLimit
+
+// Lobby は他のプレイヤーを待っている公開の部屋
+type Lobby struct {
+	RoomID      string
+	Code        string
+	HostName    string
+	PlayerCount int
+	HasPassword bool // パスワードが必要（クイックマッチでは参加しない）
+	Settings    entity.RoomSettings
+	CreatedAt   time.Time
+}
+
+// findOpenLobbies は参加できる（満員でない）公開ロビーを作成の新しい順に最大 limit 件返す
+// 新しい順に lobbyScanLimit 件までを調べるため、満員の部屋が多いと limit 件より少なくなることがある
+func findOpenLobbies(ctx context.Context, roomRepo repository.RoomRepository, playerRepo repository.PlayerRepository, limit int) ([]Lobby, error) {
+	rooms, err := roomRepo.FindPublicLobbies(ctx, lobbyScanLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	lobbies := make([]Lobby, 0, limit)
+	for _, r := range rooms {
+		if len(lobbies) >= limit {
+			break
+		}
+		playerCount, err := playerRepo.CountByRoomID(ctx, r.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if !r.Room.IsOpenLobby(playerCount) {
+			continue
+		}
+
+		host, err := playerRepo.FindByID(ctx, r.RoomID, r.Room.HostID)
+		if err != nil {
+			return nil, err
+		}
+		var hostName string
+		if host != nil {
+			hostName = host.DisplayName
+		}
+
+		lobbies = append(lobbies, Lobby{
+			RoomID:      r.RoomID,
+			Code:        r.Room.Code,
+			HostName:    hostName,
+			PlayerCount: playerCount,
+			HasPassword: r.Room.HasPassword(),
+			Settings:    r.Room.Settings,
+			CreatedAt:   r.Room.CreatedAt,
+		})
+	}
+	return lobbies, nil
+}
+
+// ListLobbiesInput は公開ロビーの一覧の入力
+type ListLobbiesInput struct {
+	Limit int // 0 なら DefaultLobbyLimit、MaxLobbyLimit を超える場合は MaxLobbyLimit
+}
+
+// ListLobbiesOutput は公開ロビーの一覧の出力
+type ListLobbiesOutput struct {
+	Lobbies []Lobby
+}
+
+// ListLobbiesUseCase は参加者を募集している公開の部屋を一覧するユースケース
+// GET /api/lobbies
+type ListLobbiesUseCase struct {
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+}
+
+// NewListLobbiesUseCase は ListLobbiesUseCase を作成する
+func NewListLobbiesUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+) *ListLobbiesUseCase {
+	return &ListLobbiesUseCase{
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+	}
+}
+
+// Execute は公開で LOBBY の部屋のうち、満員でない部屋を作成の新しい順に返す
+func (uc *ListLobbiesUseCase) Execute(ctx context.Context, input ListLobbiesInput) (*ListLobbiesOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultLobbyLimit
+	}
+	if limit > MaxLobbyLimit {
+		limit = MaxLobbyLimit
+	}
+
+	lobbies, err := findOpenLobbies(ctx, uc.roomRepo, uc.playerRepo, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ListLobbiesOutput{Lobbies: lobbies}, nil
+}
+
+// QuickMatchInput はクイックマッチの入力
+type QuickMatchInput struct {
+	UserID      string
+	DisplayName string
+	Settings    RoomSettingsInput // 指定した項目が一致する部屋だけに参加する（部屋を作成する場合はこの設定で作成）
+}
+
+// QuickMatchOutput はクイックマッチの出力
+type QuickMatchOutput struct {
+	RoomID     string
+	RoomCode   string
+	PlayerID   string
+	RejoinCode string // セッションを失ったときに同じ席に戻るためのコード（この応答でのみ返す）
+	Created    bool   // 参加できる部屋がなく、公開の部屋を作成した
+}
+
+// QuickMatchUseCase は公開ロビーに自動で参加する（なければ作成する）ユースケース
+// POST /api/quickmatch
+// 参加・作成は JoinRoomUseCase・CreateRoomUseCase に任せる
+type QuickMatchUseCase struct {
+	roomRepo     repository.RoomRepository
+	playerRepo   repository.PlayerRepository
+	joinRoomUC   *JoinRoomUseCase
+	createRoomUC *CreateRoomUseCase
+}
+
+// NewQuickMatchUseCase は QuickMatchUseCase を作成する
+func NewQuickMatchUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+	joinRoomUC *JoinRoomUseCase,
+	createRoomUC *CreateRoomUseCase,
+) *QuickMatchUseCase {
+	return &QuickMatchUseCase{
+		roomRepo:     roomRepo,
+		playerRepo:   playerRepo,
+		joinRoomUC:   joinRoomUC,
+		createRoomUC: createRoomUC,
+	}
+}
+
+// Execute はプレイヤーを公開ロビーに参加させる
+// 1. 設定が一致する（指定した項目のみ比較）、パスワードのない参加できる公開ロビーを探す
+// 2. 参加人数の多い順（同じなら先に作成された順）に参加を試す
+// 3. 探している間に満員・開始・削除された部屋は飛ばす
+// 4. 参加できる部屋がなければ、指定した設定で公開の部屋を作成する
+func (uc *QuickMatchUseCase) Execute(ctx context.Context, input QuickMatchInput) (*QuickMatchOutput, error) {
+	lobbies, err := findOpenLobbies(ctx, uc.roomRepo, uc.playerRepo, MaxLobbyLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]Lobby, 0, len(lobbies))
+	for _, lobby := range lobbies {
+		if !lobby.HasPassword && input.Settings.matches(lobby.Settings) {
+			candidates = append(candidates, lobby)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].PlayerCount != candidates[j].PlayerCount {
+			return candidates[i].PlayerCount > candidates[j].PlayerCount
+		}
+		return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
+	})
+
+	for _, lobby := range candidates {
+		out, err := uc.joinRoomUC.Execute(ctx, JoinRoomInput{
+			RoomID:      lobby.RoomID,
+			UserID:      input.UserID,
+			DisplayName: input.DisplayName,
+		})
+		if err == nil {
+			return &QuickMatchOutput{
+				RoomID:     out.RoomID,
+				RoomCode:   lobby.Code,
+				PlayerID:   out.PlayerID,
+				RejoinCode: out.RejoinCode,
+			}, nil
+		}
+		if !quickMatchSkippable(err) {
+			return nil, err
+		}
+	}
+
+	created, err := uc.createRoomUC.Execute(ctx, CreateRoomInput{
+		UserID:      input.UserID,
+		DisplayName: input.DisplayName,
+		Settings:    input.Settings,
+		Visibility:  entity.RoomVisibilityPublic,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &QuickMatchOutput{
+		RoomID:     created.RoomID,
+		RoomCode:   created.RoomCode,
+		PlayerID:   created.PlayerID,
+		RejoinCode: created.RejoinCode,
+		Created:    true,
+	}, nil
+}
+
+// quickMatchSkippable は参加に失敗した部屋を飛ばして次の部屋を試すかを判定する
+// 一覧を取得してから参加するまでに部屋の状態が変わった場合（と既に参加している部屋）は飛ばす
+func quickMatchSkippable(err error) bool {
+	return errors.Is(err, entity.ErrRoomFull) ||
+		errors.Is(err, entity.ErrGameAlreadyStarted) ||
+		errors.Is(err, entity.ErrRoomNotFound) ||
+		errors.Is(err, entity.ErrPlayerAlreadyInRoom)
+}
+
+// matches は指定された項目が全て settings と一致するかを判定する（何も指定しなければ常に一致）
+func (in RoomSettingsInput) matches(settings entity.RoomSettings) bool {
+	return reflect.DeepEqual(in.applyTo(settings), settings)
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestCreateRoomUseCase_Visibility(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	out, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト"})
+	assertErr(t, err, nil)
+	if got := env.room(t, out.RoomID).Visibility; got != entity.RoomVisibilityPrivate {
+		t.Errorf("Visibility = %s, want PRIVATE", got)
+	}
+
+	_, err = env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト", Visibility: "FRIENDS"})
+	assertErr(t, err, entity.ErrInvalidSettings)
+}
+
+func TestListLobbiesUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	now := time.Now()
+
+	older := env.createPublicRoom(t, "alice", RoomSettingsInput{})
+	env.join(t, older, "p1")
+	env.updateRoom(t, older, func(room *entity.Room) { room.CreatedAt = now.Add(-time.Minute) })
+	newer := env.createPublicRoom(t, "bob", RoomSettingsInput{})
+	env.updateRoom(t, newer, func(room *entity.Room) { room.CreatedAt = now })
+
+	// 一覧に出ない部屋: 非公開・ゲーム中・満員
+	env.createRoom(t)
+	started := env.createPublicRoom(t, "carol", RoomSettingsInput{})
+	env.join(t, started, "p2")
+	if _, err := env.startGameUC().Execute(ctx, StartGameInput{RoomID: started, UserID: "carol"}); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+	full := env.createPublicRoom(t, "dave", RoomSettingsInput{MaxPlayers: intPtr(2)})
+	env.join(t, full, "p3")
+
+	out, err := env.listLobbiesUC().Execute(ctx, ListLobbiesInput{})
+	assertErr(t, err, nil)
+	if len(out.Lobbies) != 2 {
+		t.Fatalf("len(Lobbies) = %d, want 2: %+v", len(out.Lobbies), out.Lobbies)
+	}
+	first, second := out.Lobbies[0], out.Lobbies[1]
+	if first.RoomID != newer || second.RoomID != older {
+		t.Errorf("順序 = [%s %s], want [%s %s]", first.RoomID, second.RoomID, newer, older)
+	}
+	if second.HostName != "alice" || second.PlayerCount != 2 || second.Code == "" {
+		t.Errorf("Lobby = %+v, want hostName alice, 2 players and a room code", second)
+	}
+
+	out, err = env.listLobbiesUC().Execute(ctx, ListLobbiesInput{Limit: 1})
+	assertErr(t, err, nil)
+	if len(out.Lobbies) != 1 || out.Lobbies[0].RoomID != newer {
+		t.Errorf("Limit 1 = %+v, want [%s]", out.Lobbies, newer)
+	}
+}
+
+func TestQuickMatchUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	// 参加できる部屋がなければ公開の部屋を作成する
+	first, err := env.quickMatchUC().Execute(ctx, QuickMatchInput{UserID: "u1", DisplayName: "u1"})
+	assertErr(t, err, nil)
+	if !first.Created || first.RoomCode == "" {
+		t.Fatalf("Created, RoomCode = %v, %q, want a new room with a code", first.Created, first.RoomCode)
+	}
+	if got := env.room(t, first.RoomID).Visibility; got != entity.RoomVisibilityPublic {
+		t.Errorf("Visibility = %s, want PUBLIC", got)
+	}
+
+	// 参加人数の多い部屋に参加する
+	emptier := env.createPublicRoom(t, "host2", RoomSettingsInput{})
+	second, err := env.quickMatchUC().Execute(ctx, QuickMatchInput{UserID: "u2", DisplayName: "u2"})
+	assertErr(t, err, nil)
+	if second.Created || second.RoomID != first.RoomID {
+		t.Fatalf("RoomID = %s (created %v), want %s", second.RoomID, second.Created, first.RoomID)
+	}
+	if second.RoomCode != first.RoomCode || second.RejoinCode == "" {
+		t.Errorf("RoomCode, RejoinCode = %q, %q", second.RoomCode, second.RejoinCode)
+	}
+	env.player(t, first.RoomID, "u2")
+
+	// 設定が一致する部屋だけに参加する
+	borda := entity.VotingMethodBorda
+	env.updateRoom(t, emptier, func(room *entity.Room) { room.Settings.VotingMethod = borda })
+	third, err := env.quickMatchUC().Execute(ctx, QuickMatchInput{
+		UserID:      "u3",
+		DisplayName: "u3",
+		Settings:    RoomSettingsInput{VotingMethod: &borda},
+	})
+	assertErr(t, err, nil)
+	if third.RoomID != emptier {
+		t.Errorf("RoomID = %s, want %s (BORDA の部屋)", third.RoomID, emptier)
+	}
+
+	// 一致する部屋がなければその設定で作成する
+	runoff := entity.VotingMethodInstantRunoff
+	fourth, err := env.quickMatchUC().Execute(ctx, QuickMatchInput{
+		UserID:      "u4",
+		DisplayName: "u4",
+		Settings:    RoomSettingsInput{VotingMethod: &runoff},
+	})
+	assertErr(t, err, nil)
+	if !fourth.Created || env.room(t, fourth.RoomID).Settings.VotingMethod != runoff {
+		t.Errorf("Created = %v, want a new INSTANT_RUNOFF room", fourth.Created)
+	}
+}
+
+func TestQuickMatchUseCase_SkipsRoomAlreadyJoined(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	roomID := env.createPublicRoom(t, "host", RoomSettingsInput{})
+
+	// 自分が作成した部屋には参加しない（別の部屋を作成する）
+	out, err := env.quickMatchUC().Execute(ctx, QuickMatchInput{UserID: "host", DisplayName: "ホスト"})
+	assertErr(t, err, nil)
+	if !out.Created || out.RoomID == roomID {
+		t.Errorf("RoomID = %s (created %v), want a new room", out.RoomID, out.Created)
+	}
+}
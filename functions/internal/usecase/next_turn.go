@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
 )
 
 // NextTurnInput は次ターンの入力
@@ -14,8 +16,9 @@ type NextTurnInput struct {
 
 // NextTurnOutput は次ターンの出力
 type NextTurnOutput struct {
-	Status entity.RoomStatus
-	Turn   int
+	Status     entity.RoomStatus
+	Turn       int
+	IsGameOver bool // 提示できる政策が尽きて終了した場合 true
 }
 
 // NextTurnUseCase は次ターンへ進むユースケース
@@ -23,73 +26,118 @@ type NextTurnOutput struct {
 type NextTurnUseCase struct {
 	roomRepo   repository.RoomRepository
 	playerRepo repository.PlayerRepository
+	transactor repository.Transactor
+	jobs       repository.JobQueue
+	publisher  service.EventPublisher
 }
 
 // NewNextTurnUseCase は NextTurnUseCase を作成する
 func NewNextTurnUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
+	transactor repository.Transactor,
+	jobs repository.JobQueue,
+	publisher service.EventPublisher,
 ) *NextTurnUseCase {
 	return &NextTurnUseCase{
 		roomRepo:   roomRepo,
 		playerRepo: playerRepo,
+		transactor: transactor,
+		jobs:       jobs,
+		publisher:  publisher,
 	}
 }
 
 // Execute は次のターンに進める
 // フロントエンドから自動でトリガーされる（ホストチェックなし）
 // 1. RESULT状態であることを確認
-// 2. turnをインクリメント
-// 3. statusをVOTINGに
-// 4. 次の3枚の政策をセット
-// 5. votesをリセット
+// 2〜5 は advanceTurn を参照
+// 1〜5 は1つのトランザクションで行う（複数クライアントから同時に呼ばれても1ターンだけ進む）
+// resultTimeLimit がある部屋では、期限を過ぎるとクライアントが呼ばなくても HandleTimeoutsUseCase が次のターンへ進める
 func (uc *NextTurnUseCase) Execute(ctx context.Context, input NextTurnInput) (*NextTurnOutput, error) {
-	// 部屋を取得
-	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+	var room *entity.Room
+	var turn turnAdvance
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		// 部屋を取得
+		var err error
+		room, err = uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+
+		// RESULT状態でないと次ターンに進めない
+		if room.Status != entity.RoomStatusResult {
+			return entity.ErrInvalidPhase
+		}
+
+		turn, err = advanceTurn(ctx, uc.playerRepo, input.RoomID, room)
+		if err != nil {
+			return err
+		}
+
+		// 部屋を更新
+		room.Touch(time.Now())
+		return uc.roomRepo.Update(ctx, input.RoomID, room)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if room == nil {
-		return nil, entity.ErrRoomNotFound
-	}
 
-	// RESULT状態でないと次ターンに進めない
-	if room.Status != entity.RoomStatusResult {
-		return nil, entity.ErrInvalidPhase
+	scheduleDeadline(ctx, uc.jobs, input.RoomID, room)
+	publishTurnAdvanced(ctx, uc.publisher, input.RoomID, room, turn)
+
+	return &NextTurnOutput{
+		Status:     room.Status,
+		Turn:       room.Turn,
+		IsGameOver: turn.isGameOver,
+	}, nil
+}
+
+// turnAdvance は次のターンへの移行の結果を表す
+type turnAdvance struct {
+	reshuffled bool // 捨て札を山札に戻したか
+	isGameOver bool // 提示できる政策が尽きて終了したか
+}
+
+// advanceTurn は RESULT の部屋を次のターンに進める
+// トランザクション内で呼び出す前提で、部屋の保存は呼び出し元で行う
+// 2. turnをインクリメント
+// 3. statusをVOTINGに（votingTimeLimit があれば投票の期限を設定）
+// 4. 次の optionsPerTurn 枚の政策をセット（山札が足りなければ捨て札を戻す）
+// 5. votesをリセット
+// 提示できる政策が1枚も残っていなければ、ターンを進めずにゲームを終了する（DECK_EXHAUSTED）
+func advanceTurn(ctx context.Context, playerRepo repository.PlayerRepository, roomID string, room *entity.Room) (turnAdvance, error) {
+	// 提示できる政策が残っていなければゲームを終了する（最終結果の計算にプレイヤーが必要）
+	if !room.HasPoliciesLeft() {
+		players, err := playerRepo.FindAllWithIDsByRoomID(ctx, roomID)
+		if err != nil {
+			return turnAdvance{}, err
+		}
+		finishGame(room, players)
+		return turnAdvance{isGameOver: true}, nil
 	}
 
-	// 次の3枚の政策をセット
-	currentCount := 3
-	if len(room.DeckIDs) < currentCount {
-		currentCount = len(room.DeckIDs)
+	// 全プレイヤーの投票状態をリセット（プレイヤーの読み取りを伴うため部屋の更新より先に行う）
+	if err := playerRepo.ClearAllVotes(ctx, roomID); err != nil {
+		return turnAdvance{}, err
 	}
-	room.CurrentPolicyIDs = room.DeckIDs[:currentCount]
-	room.DeckIDs = room.DeckIDs[currentCount:]
+
+	// 次の optionsPerTurn 枚の政策をセット
+	reshuffled := room.DealPolicies()
 
 	// turnをインクリメント
 	room.Turn++
 
-	// votesをリセット
-	for userID := range room.Votes {
-		room.Votes[userID] = ""
-	}
+	// votes・ballotsをリセット
+	room.ResetVotes()
 
 	// statusをVOTINGに
 	room.Status = entity.RoomStatusVoting
+	room.ScheduleDeadline(time.Now())
 	// LastResult は次の投票結果が出るまで保持する
 
-	// 部屋を更新
-	if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
-		return nil, err
-	}
-
-	// 全プレイヤーの投票状態をリセット
-	if err := uc.playerRepo.ClearAllVotes(ctx, input.RoomID); err != nil {
-		return nil, err
-	}
-
-	return &NextTurnOutput{
-		Status: room.Status,
-		Turn:   room.Turn,
-	}, nil
+	return turnAdvance{reshuffled: reshuffled}, nil
 }
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+// resultRoom は1ターン目の集計が終わった（RESULT 状態の）部屋を作成する
+func resultRoom(t *testing.T, env *testEnv) string {
+	t.Helper()
+	roomID := env.startedRoom(t, "p1")
+	policyID := env.room(t, roomID).CurrentPolicyIDs[0]
+	env.voteAll(t, roomID, map[string]string{"host": policyID, "p1": policyID})
+	return roomID
+}
+
+func TestNextTurnUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name           string
+		setup          func(t *testing.T, env *testEnv) string
+		deckSize       int // -1 なら山札を変更しない
+		discardSize    int // -1 なら捨て札を変更しない（1ターン目の集計後は2枚）
+		wantErr        error
+		wantOptions    int
+		wantReshuffled bool
+	}{
+		{
+			name:        "次のターンに進み3枚引く",
+			setup:       resultRoom,
+			deckSize:    -1,
+			discardSize: -1,
+			wantOptions: 3,
+		},
+		{
+			name:           "山札が3枚未満なら捨て札を戻して引く",
+			setup:          resultRoom,
+			deckSize:       2,
+			discardSize:    -1,
+			wantOptions:    3,
+			wantReshuffled: true,
+		},
+		{
+			name:           "山札と捨て札を合わせても足りなければ残りだけ引く",
+			setup:          resultRoom,
+			deckSize:       0,
+			discardSize:    2,
+			wantOptions:    2,
+			wantReshuffled: true,
+		},
+		{
+			name:        "RESULT以外では進めない",
+			setup:       func(t *testing.T, env *testEnv) string { return env.startedRoom(t, "p1") },
+			deckSize:    -1,
+			discardSize: -1,
+			wantErr:     entity.ErrInvalidPhase,
+		},
+		{
+			name:        "存在しない部屋",
+			setup:       func(t *testing.T, env *testEnv) string { return "missing" },
+			deckSize:    -1,
+			discardSize: -1,
+			wantErr:     entity.ErrRoomNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := tt.setup(t, env)
+			if tt.deckSize >= 0 || tt.discardSize >= 0 {
+				env.updateRoom(t, roomID, func(room *entity.Room) {
+					if tt.deckSize >= 0 {
+						room.DeckIDs = room.DeckIDs[:tt.deckSize]
+					}
+					if tt.discardSize >= 0 {
+						room.DiscardIDs = room.DiscardIDs[:tt.discardSize]
+					}
+				})
+			}
+			var available []string
+			if tt.wantErr == nil {
+				before := env.room(t, roomID)
+				available = append(append([]string(nil), before.DeckIDs...), before.DiscardIDs...)
+			}
+
+			out, err := env.nextTurnUC().Execute(context.Background(), NextTurnInput{RoomID: roomID})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			if out.Status != entity.RoomStatusVoting || out.Turn != 2 || out.IsGameOver {
+				t.Errorf("status = %s, turn = %d, isGameOver = %v, want VOTING, 2, false", out.Status, out.Turn, out.IsGameOver)
+			}
+			room := env.room(t, roomID)
+			if len(room.CurrentPolicyIDs) != tt.wantOptions {
+				t.Errorf("currentPolicyIds = %d枚, want %d枚", len(room.CurrentPolicyIDs), tt.wantOptions)
+			}
+			for _, policyID := range room.CurrentPolicyIDs {
+				if !contains(available, policyID) {
+					t.Errorf("%s は山札にも捨て札にもなかった", policyID)
+				}
+			}
+			if got := len(room.DeckIDs) + len(room.DiscardIDs) + len(room.CurrentPolicyIDs); got != len(available) {
+				t.Errorf("山札+捨て札+提示中 = %d枚, want %d枚（政策が失われた・重複した）", got, len(available))
+			}
+			if tt.wantReshuffled && len(room.DiscardIDs) != 0 {
+				t.Errorf("捨て札が山札に戻されていない: %v", room.DiscardIDs)
+			}
+			for userID, vote := range room.Votes {
+				if vote != "" {
+					t.Errorf("votes[%s] がリセットされていない", userID)
+				}
+			}
+			for _, userID := range []string{"host", "p1"} {
+				if env.player(t, roomID, userID).CurrentVote != "" {
+					t.Errorf("%s の currentVote がリセットされていない", userID)
+				}
+			}
+			if room.LastResult == nil {
+				t.Error("lastResult は次の集計まで保持される")
+			}
+		})
+	}
+}
+
+func TestNextTurnUseCase_ExhaustedDeckFinishesGame(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := resultRoom(t, env)
+	env.updateRoom(t, roomID, func(room *entity.Room) {
+		room.DeckIDs = nil
+		room.DiscardIDs = nil
+	})
+
+	out, err := env.nextTurnUC().Execute(context.Background(), NextTurnInput{RoomID: roomID})
+	assertErr(t, err, nil)
+	if !out.IsGameOver || out.Status != entity.RoomStatusFinished || out.Turn != 1 {
+		t.Errorf("status = %s, turn = %d, isGameOver = %v, want FINISHED, 1, true", out.Status, out.Turn, out.IsGameOver)
+	}
+	room := env.room(t, roomID)
+	if room.FinalResult == nil || room.FinalResult.Reason != entity.GameEndReasonDeckExhausted {
+		t.Fatalf("finalResult = %+v, want reason DECK_EXHAUSTED", room.FinalResult)
+	}
+	if !env.publisher.has(entity.RoomEventGameFinished) {
+		t.Error("GAME_FINISHED が配信されていない")
+	}
+
+	_, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", PolicyID: "policy_001"})
+	assertErr(t, err, entity.ErrInvalidPhase)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
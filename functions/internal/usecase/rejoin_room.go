@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// RejoinRoomInput は部屋への再参加の入力
+// UserID（有効なセッションのプレイヤー）か RejoinCode（参加時に発行した再参加コード）のどちらかで席を探す
+type RejoinRoomInput struct {
+	RoomID     string
+	UserID     string
+	RejoinCode string // 指定した場合は UserID より優先する
+}
+
+// RejoinRoomOutput は部屋への再参加の出力
+// 秘匿情報（ideology, currentVote）は本人の再参加なのでそのまま返す
+type RejoinRoomOutput struct {
+	PlayerID string
+	Player   *entity.Player
+	Room     *entity.Room
+}
+
+// RejoinRoomUseCase は接続が切れたプレイヤーが同じ席に戻るユースケース
+// POST /api/rooms/{roomId}/rejoin
+type RejoinRoomUseCase struct {
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	transactor repository.Transactor
+	publisher  service.EventPublisher
+}
+
+// NewRejoinRoomUseCase は RejoinRoomUseCase を作成する
+func NewRejoinRoomUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+	transactor repository.Transactor,
+	publisher service.EventPublisher,
+) *RejoinRoomUseCase {
+	return &RejoinRoomUseCase{
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+		transactor: transactor,
+		publisher:  publisher,
+	}
+}
+
+// Execute は既存の席に戻る
+// 1. ルームの存在確認（状態は問わない。ゲーム中・終了後も戻れる）
+// 2. 再参加コード、またはセッションのプレイヤーIDで席を探す
+// 3. 部屋の lastActivityAt を更新
+// 席は変わらないため、思想・投票・陳情の回数・ホストはそのまま
+// 再参加コードの不一致はパスワード・招待の不一致と同じく部屋に記録する（多すぎれば entity.ErrTooManyJoinAttempts）
+func (uc *RejoinRoomUseCase) Execute(ctx context.Context, input RejoinRoomInput) (*RejoinRoomOutput, error) {
+	var room *entity.Room
+	var playerID string
+	var player *entity.Player
+	var err error
+	switch {
+	case input.RejoinCode != "":
+		room, playerID, player, err = uc.findByRejoinCode(ctx, input.RoomID, input.RejoinCode)
+		if err != nil {
+			return nil, err
+		}
+	case input.UserID != "":
+		room, err = uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if room == nil {
+			return nil, entity.ErrRoomNotFound
+		}
+		player, err = uc.playerRepo.FindByID(ctx, input.RoomID, input.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil {
+			return nil, entity.ErrPlayerNotInRoom
+		}
+		playerID = input.UserID
+	default:
+		return nil, entity.ErrUnauthenticated
+	}
+
+	if err := uc.roomRepo.Touch(ctx, input.RoomID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	event := entity.NewRoomEvent(entity.RoomEventPlayerRejoined, input.RoomID, room)
+	event.PlayerID = playerID
+	event.Data["displayName"] = player.DisplayName
+	publishEvent(ctx, uc.publisher, event)
+
+	return &RejoinRoomOutput{
+		PlayerID: playerID,
+		Player:   player,
+		Room:     room,
+	}, nil
+}
+
+// findByRejoinCode は再参加コードの一致する席を探す
+// 失敗の記録と確認を1つのトランザクションで行う（同時に試しても回数の上限を超えて試せないようにする）
+// 参加を受け付けない期間は、コードが一致しても entity.ErrTooManyJoinAttempts を返す
+func (uc *RejoinRoomUseCase) findByRejoinCode(ctx context.Context, roomID, rejoinCode string) (*entity.Room, string, *entity.Player, error) {
+	var room *entity.Room
+	var playerID string
+	var player *entity.Player
+	var matchErr error
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		playerID, player, matchErr = "", nil, nil
+
+		var err error
+		room, err = uc.roomRepo.FindByID(ctx, roomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+
+		now := time.Now()
+		if room.JoinLockedOut(now) {
+			return entity.ErrTooManyJoinAttempts
+		}
+
+		players, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, roomID)
+		if err != nil {
+			return err
+		}
+		for _, p := range players {
+			if p.Player.MatchesRejoinCode(rejoinCode) {
+				playerID, player = p.UserID, p.Player
+				return nil
+			}
+		}
+
+		// 推測による失敗はコミットして数える
+		matchErr = entity.ErrInvalidRejoinCode
+		room.RecordJoinFailure(now)
+		return uc.roomRepo.Update(ctx, roomID, room)
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if matchErr != nil {
+		return nil, "", nil, matchErr
+	}
+	return room, playerID, player, nil
+}
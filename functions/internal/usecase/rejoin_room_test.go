@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestRejoinRoomUseCase_ByRejoinCode(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+	joined, err := env.joinRoomUC().Execute(context.Background(), JoinRoomInput{RoomID: roomID, UserID: "guest", DisplayName: "ゲスト"})
+	if err != nil {
+		t.Fatalf("JoinRoom: %v", err)
+	}
+	if len(joined.RejoinCode) != entity.RejoinCodeLength {
+		t.Fatalf("RejoinCode = %q, want %d chars", joined.RejoinCode, entity.RejoinCodeLength)
+	}
+	if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+	policyID := env.room(t, roomID).CurrentPolicyIDs[0]
+	if _, err := env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "guest", PolicyID: policyID}); err != nil {
+		t.Fatalf("Vote: %v", err)
+	}
+
+	// 小文字・区切りを入れても同じコードとして扱う
+	code := strings.ToLower(joined.RejoinCode[:4] + "-" + joined.RejoinCode[4:])
+	out, err := env.rejoinRoomUC().Execute(context.Background(), RejoinRoomInput{RoomID: roomID, RejoinCode: code})
+	if err != nil {
+		t.Fatalf("RejoinRoom: %v", err)
+	}
+	if out.PlayerID != "guest" {
+		t.Errorf("PlayerID = %s, want guest", out.PlayerID)
+	}
+	if out.Player.Ideology == nil || out.Player.Ideology.IdeologyID != env.player(t, roomID, "guest").Ideology.IdeologyID {
+		t.Error("思想が元の席のものと違う")
+	}
+	if out.Player.CurrentVote != policyID {
+		t.Errorf("CurrentVote = %s, want %s", out.Player.CurrentVote, policyID)
+	}
+	if out.Room.Status != entity.RoomStatusVoting {
+		t.Errorf("Status = %s, want VOTING", out.Room.Status)
+	}
+	if !env.publisher.has(entity.RoomEventPlayerRejoined) {
+		t.Error("PLAYER_REJOINED が配信されていない")
+	}
+}
+
+func TestRejoinRoomUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   func(roomID string) RejoinRoomInput
+		wantID  string
+		wantErr error
+	}{
+		{
+			name:   "セッションのプレイヤーIDで戻れる",
+			input:  func(roomID string) RejoinRoomInput { return RejoinRoomInput{RoomID: roomID, UserID: "p1"} },
+			wantID: "p1",
+		},
+		{
+			name:    "部屋にいないプレイヤー",
+			input:   func(roomID string) RejoinRoomInput { return RejoinRoomInput{RoomID: roomID, UserID: "stranger"} },
+			wantErr: entity.ErrPlayerNotInRoom,
+		},
+		{
+			name:    "一致しない再参加コード",
+			input:   func(roomID string) RejoinRoomInput { return RejoinRoomInput{RoomID: roomID, RejoinCode: "ZZZZZZZZ"} },
+			wantErr: entity.ErrInvalidRejoinCode,
+		},
+		{
+			name:    "セッションも再参加コードもない",
+			input:   func(roomID string) RejoinRoomInput { return RejoinRoomInput{RoomID: roomID} },
+			wantErr: entity.ErrUnauthenticated,
+		},
+		{
+			name:    "存在しない部屋",
+			input:   func(roomID string) RejoinRoomInput { return RejoinRoomInput{RoomID: "missing", UserID: "p1"} },
+			wantErr: entity.ErrRoomNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := env.startedRoom(t, "p1")
+
+			out, err := env.rejoinRoomUC().Execute(context.Background(), tt.input(roomID))
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if out.PlayerID != tt.wantID {
+				t.Errorf("PlayerID = %s, want %s", out.PlayerID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestRejoinRoomUseCase_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	created, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト"})
+	assertErr(t, err, nil)
+	roomID := created.RoomID
+
+	for i := 0; i < entity.MaxJoinFailures; i++ {
+		_, err := env.rejoinRoomUC().Execute(ctx, RejoinRoomInput{RoomID: roomID, RejoinCode: "ZZZZZZZZ"})
+		assertErr(t, err, entity.ErrInvalidRejoinCode)
+	}
+	if failures := env.room(t, roomID).JoinFailures; failures == nil || failures.Count != entity.MaxJoinFailures {
+		t.Fatalf("JoinFailures = %+v, want %d回", failures, entity.MaxJoinFailures)
+	}
+
+	// 正しいコードでも、失敗が多すぎる間は戻れない（パスワードでの参加も同じく受け付けない）
+	_, err = env.rejoinRoomUC().Execute(ctx, RejoinRoomInput{RoomID: roomID, RejoinCode: created.RejoinCode})
+	assertErr(t, err, entity.ErrTooManyJoinAttempts)
+	_, err = env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: roomID, UserID: "guest", DisplayName: "ゲスト"})
+	assertErr(t, err, entity.ErrTooManyJoinAttempts)
+
+	// セッションでの再参加はコードを推測しないため制限しない
+	_, err = env.rejoinRoomUC().Execute(ctx, RejoinRoomInput{RoomID: roomID, UserID: "host"})
+	assertErr(t, err, nil)
+
+	// 期間が過ぎれば戻れる
+	env.updateRoom(t, roomID, func(room *entity.Room) {
+		room.JoinFailures.WindowStart = time.Now().Add(-entity.JoinFailureWindow - time.Second)
+	})
+	out, err := env.rejoinRoomUC().Execute(ctx, RejoinRoomInput{RoomID: roomID, RejoinCode: created.RejoinCode})
+	assertErr(t, err, nil)
+	if out.PlayerID != "host" {
+		t.Errorf("PlayerID = %s, want host", out.PlayerID)
+	}
+}
+
+func TestCreateRoomUseCase_IssuesRejoinCode(t *testing.T) {
+	env := newTestEnv(t)
+	out, err := env.createRoomUC().Execute(context.Background(), CreateRoomInput{UserID: "host", DisplayName: "ホスト"})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+
+	host := env.player(t, out.RoomID, "host")
+	if !host.MatchesRejoinCode(out.RejoinCode) {
+		t.Error("ホストの再参加コードが保存されていない")
+	}
+	if host.RejoinCodeHash == out.RejoinCode {
+		t.Error("再参加コードがハッシュにせずに保存されている")
+	}
+}
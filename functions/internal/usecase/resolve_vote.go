@@ -2,8 +2,7 @@ package usecase
 
 import (
 	"context"
-	"encoding/base64"
-	"log/slog"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
@@ -18,17 +17,19 @@ type ResolveVoteInput struct {
 // ResolveVoteOutput は投票集計の出力
 type ResolveVoteOutput struct {
 	Room       *entity.Room
+	IsResolved bool // 政策が可決されたか（false なら同数で決着待ち）
 	IsGameOver bool
+	PendingTie *entity.PendingTie // 同数で決着待ちになった場合（HOST_DECIDES・REVOTE）
 }
 
 // ResolveVoteUseCase は投票集計のユースケース
 // POST /api/rooms/{roomId}/resolve
 type ResolveVoteUseCase struct {
-	roomRepo       repository.RoomRepository
-	playerRepo     repository.PlayerRepository
-	policyRepo     repository.PolicyRepository
-	imageGenerator service.ImageGenerator
-	imageStorage   service.ImageStorage
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	transactor repository.Transactor
+	resolver   *turnResolver
+	publisher  service.EventPublisher
 }
 
 // NewResolveVoteUseCase は ResolveVoteUseCase を作成する
@@ -36,142 +37,108 @@ func NewResolveVoteUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
 	policyRepo repository.PolicyRepository,
+	transactor repository.Transactor,
 	imageGenerator service.ImageGenerator,
 	imageStorage service.ImageStorage,
+	jobs repository.JobQueue,
+	publisher service.EventPublisher,
 ) *ResolveVoteUseCase {
 	return &ResolveVoteUseCase{
-		roomRepo:       roomRepo,
-		playerRepo:     playerRepo,
-		policyRepo:     policyRepo,
-		imageGenerator: imageGenerator,
-		imageStorage:   imageStorage,
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+		transactor: transactor,
+		resolver: &turnResolver{
+			roomRepo:       roomRepo,
+			policyRepo:     policyRepo,
+			imageGenerator: imageGenerator,
+			imageStorage:   imageStorage,
+			jobs:           jobs,
+		},
+		publisher: publisher,
 	}
 }
 
 // Execute は投票を集計し、結果を反映する
-// 1. votes を集計して最多得票の政策を決定（同数の場合はランダム）
+// 1. 票を集計して政策を決定（同数の場合は tieBreakRule に従い、HOST_DECIDES・REVOTE なら決着待ちにして終了）
 // 2. master_policies から effects を取得
 // 3. cityParams に効果を適用
 // 4. isCollapsed をチェック（いずれかのパラメータが 0 以下 or 100 以上）
 // 5. lastResult を設定
 // 6. status を RESULT に
-// 7. ゲーム終了判定: turn >= maxTurns or isCollapsed → FINISHED
+// 7. ゲーム終了判定: turn >= maxTurns or isCollapsed → FINISHED（最終結果を記録）
+// 1〜7 は1つのトランザクションで行う（Vote の自動resolveと同時に呼ばれても集計は1回だけ）
 // ※ 次のターンの準備（カード引き、投票リセット）は next_turn.go で行う
 func (uc *ResolveVoteUseCase) Execute(ctx context.Context, input ResolveVoteInput) (*ResolveVoteOutput, error) {
-	// 部屋を取得
-	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
-	if err != nil {
-		return nil, err
-	}
-	if room == nil {
-		return nil, entity.ErrRoomNotFound
-	}
-
-	// VOTING状態でないと集計できない
-	if room.Status != entity.RoomStatusVoting {
-		return nil, entity.ErrInvalidPhase
-	}
-
-	// プレイヤー数を取得
-	players, err := uc.playerRepo.FindAllByRoomID(ctx, input.RoomID)
-	if err != nil {
-		return nil, err
-	}
+	var output *ResolveVoteOutput
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		// 部屋を取得
+		room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
 
-	// 全員が投票しているか確認
-	if !room.AllPlayersVoted(len(players)) {
-		return nil, entity.ErrNotAllVoted
-	}
+		// VOTING状態でないと集計できない（集計済みの場合・ホストが同数の政策から選ぶ間もここで弾かれる）
+		if room.Status != entity.RoomStatusVoting || room.AwaitingHost() {
+			return entity.ErrInvalidPhase
+		}
 
-	// 投票集計
-	winningPolicyID := room.CountVotes()
+		// プレイヤーを取得
+		players, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
 
-	// 可決された政策を取得
-	winningPolicy, err := findPolicy(ctx, room, uc.policyRepo, winningPolicyID)
-	if err != nil {
-		return nil, err
-	}
-	if winningPolicy == nil {
-		return nil, entity.ErrPolicyNotFound
-	}
+		// 全員が投票しているか確認（requireLockIn の場合は全員が確定しているか）
+		if !room.ReadyToResolve(len(players)) {
+			return entity.ErrNotAllVoted
+		}
 
-	// 政策の効果を街に適用
-	room.ApplyPolicyEffects(winningPolicy.Effects)
+		// 投票集計
+		outcome, err := uc.resolver.resolve(ctx, room, players)
+		if err != nil {
+			return err
+		}
 
-	// 可決された政策を履歴に追加
-	room.PassedPolicyIDs = append(room.PassedPolicyIDs, winningPolicy.PolicyID)
+		// 再投票になった場合は全員の投票をリセット
+		if room.Revoting() && !outcome.resolved {
+			if err := clearPlayerVotes(ctx, uc.playerRepo, input.RoomID, players); err != nil {
+				return err
+			}
+		}
 
-	// 投票結果を設定
-	room.LastResult = &entity.VoteResult{
-		PassedPolicyID:    winningPolicy.PolicyID,
-		PassedPolicyTitle: winningPolicy.Title,
-		ActualEffects:     winningPolicy.Effects,
-		NewsFlash:         winningPolicy.NewsFlash,
-		VoteDetails:       room.Votes,
-	}
+		// 部屋を更新
+		room.Touch(time.Now())
+		if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
+			return err
+		}
 
-	// 街の画像を生成
-	if uc.imageGenerator != nil {
-		passedPolicies, err := uc.getPassedPolicies(ctx, room)
-		if err != nil {
-			slog.Warn("failed to get passed policies for image generation", slog.Any("error", err))
-		} else {
-			imageResult, err := uc.imageGenerator.GenerateCityImage(ctx, &room.CityParams, passedPolicies)
-			if err != nil {
-				slog.Warn("failed to generate city image", slog.Any("error", err))
-			} else {
-				room.LastResult.CityImage = imageResult.Image
-
-				// GCSにアップロードしてsigned URLを取得
-				if uc.imageStorage != nil {
-					imageData, err := base64.StdEncoding.DecodeString(imageResult.Image)
-					if err != nil {
-						slog.Warn("failed to decode base64 image", slog.Any("error", err))
-					} else {
-						signedURL, err := uc.imageStorage.UploadCityImage(ctx, input.RoomID, room.Turn, imageData)
-						if err != nil {
-							slog.Warn("failed to upload city image to GCS", slog.Any("error", err))
-						} else {
-							room.LastResult.CityImageURL = signedURL
-							slog.Info("city image uploaded to GCS", slog.String("url", signedURL))
-						}
-					}
-				}
-			}
+		output = &ResolveVoteOutput{
+			Room:       room,
+			IsResolved: outcome.resolved,
+			IsGameOver: outcome.isGameOver,
+			PendingTie: room.PendingTie,
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 結果発表フェーズに移行
-	room.Status = entity.RoomStatusResult
+	// 次の期限（結果発表・決着待ち）に期限切れの処理を予約
+	scheduleDeadline(ctx, uc.resolver.jobs, input.RoomID, output.Room)
 
-	// ゲーム終了判定
-	isGameOver := room.IsGameOver()
-	if isGameOver {
-		room.Finish()
+	if !output.IsResolved {
+		publishTiePending(ctx, uc.publisher, input.RoomID, output.Room)
+		return output, nil
 	}
 
-	// 部屋を更新
-	if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
-		return nil, err
-	}
+	// 街の画像を生成（外部API呼び出しのためコミット後に行う）
+	uc.resolver.attachCityImage(ctx, input.RoomID, output.Room)
 
-	return &ResolveVoteOutput{
-		Room:       room,
-		IsGameOver: isGameOver,
-	}, nil
-}
+	publishTurnResolved(ctx, uc.publisher, input.RoomID, output.Room, output.IsGameOver)
 
-// getPassedPolicies は可決された政策のリストを取得する
-func (uc *ResolveVoteUseCase) getPassedPolicies(ctx context.Context, room *entity.Room) ([]*entity.MasterPolicy, error) {
-	policies := make([]*entity.MasterPolicy, 0, len(room.PassedPolicyIDs))
-	for _, policyID := range room.PassedPolicyIDs {
-		policy, err := findPolicy(ctx, room, uc.policyRepo, policyID)
-		if err != nil {
-			return nil, err
-		}
-		if policy != nil {
-			policies = append(policies, policy)
-		}
-	}
-	return policies, nil
+	return output, nil
 }
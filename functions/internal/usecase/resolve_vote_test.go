@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestResolveVoteUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		started    bool
+		mutate     func(room *entity.Room)
+		wantErr    error
+		wantStatus entity.RoomStatus
+		wantReason entity.GameEndReason
+	}{
+		{
+			name:    "全員投票済みなら集計できる",
+			started: true,
+			mutate: func(room *entity.Room) {
+				room.Votes = map[string]string{"host": "policy_001", "p1": "policy_001"}
+			},
+			wantStatus: entity.RoomStatusResult,
+		},
+		{
+			name:    "VOTING以外では集計できない",
+			wantErr: entity.ErrInvalidPhase,
+		},
+		{
+			name:    "未投票のプレイヤーがいる",
+			started: true,
+			mutate: func(room *entity.Room) {
+				room.Votes = map[string]string{"host": "policy_001", "p1": ""}
+			},
+			wantErr: entity.ErrNotAllVoted,
+		},
+		{
+			name:    "可決された政策が存在しない",
+			started: true,
+			mutate: func(room *entity.Room) {
+				room.Votes = map[string]string{"host": "policy_missing", "p1": "policy_missing"}
+			},
+			wantErr: entity.ErrPolicyNotFound,
+		},
+		{
+			name:    "パラメータが0以下になると崩壊して終了する",
+			started: true,
+			mutate: func(room *entity.Room) {
+				room.CityParams.Economy = 5
+				room.Votes = map[string]string{"host": "policy_005", "p1": "policy_005"} // 経済 -15
+			},
+			wantStatus: entity.RoomStatusFinished,
+			wantReason: entity.GameEndReasonCollapsed,
+		},
+		{
+			name:    "次に提示できる政策がなければ終了する",
+			started: true,
+			mutate: func(room *entity.Room) {
+				room.CurrentPolicyIDs = []string{"policy_001"}
+				room.DeckIDs = nil
+				room.Votes = map[string]string{"host": "policy_001", "p1": "policy_001"}
+			},
+			wantStatus: entity.RoomStatusFinished,
+			wantReason: entity.GameEndReasonDeckExhausted,
+		},
+		{
+			name:    "最終ターンの集計で終了する",
+			started: true,
+			mutate: func(room *entity.Room) {
+				room.Turn = room.MaxTurns
+				room.Votes = map[string]string{"host": "policy_001", "p1": "policy_001"}
+			},
+			wantStatus: entity.RoomStatusFinished,
+			wantReason: entity.GameEndReasonMaxTurns,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			var roomID string
+			if tt.started {
+				roomID = env.startedRoom(t, "p1")
+			} else {
+				roomID = env.createRoom(t)
+				env.join(t, roomID, "p1")
+			}
+			if tt.mutate != nil {
+				env.updateRoom(t, roomID, tt.mutate)
+			}
+
+			out, err := env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				// 失敗した場合は部屋が変更されていない
+				if room := env.room(t, roomID); room.LastResult != nil {
+					t.Error("失敗したのに lastResult が設定されている")
+				}
+				return
+			}
+
+			room := env.room(t, roomID)
+			if room.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", room.Status, tt.wantStatus)
+			}
+			wantGameOver := tt.wantStatus == entity.RoomStatusFinished
+			if out.IsGameOver != wantGameOver {
+				t.Errorf("IsGameOver = %v, want %v", out.IsGameOver, wantGameOver)
+			}
+			if !wantGameOver {
+				return
+			}
+			if room.FinalResult == nil {
+				t.Fatal("最終結果が記録されていない")
+			}
+			if room.FinalResult.Reason != tt.wantReason {
+				t.Errorf("reason = %s, want %s", room.FinalResult.Reason, tt.wantReason)
+			}
+			if len(room.FinalResult.Rankings) != 2 {
+				t.Errorf("rankings = %d人, want 2人", len(room.FinalResult.Rankings))
+			}
+			if !env.publisher.has(entity.RoomEventGameFinished) {
+				t.Error("GAME_FINISHED が配信されていない")
+			}
+		})
+	}
+}
+
+func TestResolveVoteUseCase_RoomNotFound(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: "missing"})
+	assertErr(t, err, entity.ErrRoomNotFound)
+}
+
+func TestResolveVoteUseCase_ResolvesOnce(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := env.startedRoom(t, "p1")
+	env.updateRoom(t, roomID, func(room *entity.Room) {
+		room.Votes = map[string]string{"host": "policy_001", "p1": "policy_001"}
+	})
+
+	_, err := env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID})
+	assertErr(t, err, nil)
+	_, err = env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID})
+	assertErr(t, err, entity.ErrInvalidPhase)
+
+	if got := env.room(t, roomID).PassedPolicyIDs; len(got) != 1 {
+		t.Errorf("passedPolicyIds = %v, want 1件", got)
+	}
+}
+
+func TestResolveVoteUseCase_ImageFailureDoesNotFail(t *testing.T) {
+	env := newTestEnv(t)
+	env.imageGenerator.err = errors.New("image api down")
+	roomID := env.startedRoom(t, "p1")
+	env.updateRoom(t, roomID, func(room *entity.Room) {
+		room.Votes = map[string]string{"host": "policy_001", "p1": "policy_001"}
+	})
+
+	_, err := env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID})
+	assertErr(t, err, nil)
+
+	room := env.room(t, roomID)
+	if room.Status != entity.RoomStatusResult {
+		t.Errorf("status = %s, want RESULT", room.Status)
+	}
+	if room.LastResult.CityImageURL != "" {
+		t.Error("画像生成に失敗したのに URL が設定されている")
+	}
+}
+
+func TestResolveVoteUseCase_CityRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		economyRule entity.ParamRule
+		wantStatus  entity.RoomStatus
+		wantApplied int
+		wantCause   *entity.CollapseCause
+	}{
+		{
+			name:        "崩壊した分野が結果に記録される",
+			economyRule: entity.DefaultParamRule(),
+			wantStatus:  entity.RoomStatusFinished,
+			wantApplied: -15,
+			wantCause:   &entity.CollapseCause{Param: "economy", Value: -10, Bound: entity.CollapseBoundFloor, Limit: 0},
+		},
+		{
+			name:        "CLAMP なら下限で止まりゲームが続く",
+			economyRule: entity.ParamRule{Floor: 0, Ceiling: 100, FloorMode: entity.BoundModeClamp, CeilingMode: entity.BoundModeCollapse},
+			wantStatus:  entity.RoomStatusResult,
+			wantApplied: -5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := env.startedRoom(t, "p1")
+			env.updateRoom(t, roomID, func(room *entity.Room) {
+				room.Settings.CityRules["economy"] = tt.economyRule
+				room.CityParams.Economy = 5
+				room.Votes = map[string]string{"host": "policy_005", "p1": "policy_005"} // 経済 -15
+			})
+
+			if _, err := env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+
+			room := env.room(t, roomID)
+			if room.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", room.Status, tt.wantStatus)
+			}
+			if got := room.LastResult.ActualEffects["economy"]; got != tt.wantApplied {
+				t.Errorf("actualEffects.economy = %d, want %d", got, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(room.LastResult.CollapseCause, tt.wantCause) {
+				t.Errorf("collapseCause = %+v, want %+v", room.LastResult.CollapseCause, tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestResolveVoteUseCase_TieBreakRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        entity.TieBreakRule
+		wantStatus  entity.RoomStatus
+		wantPassed  string
+		wantPending bool
+	}{
+		{
+			// policy_001 の効果の大きさは 45/6、policy_002 は 40/6
+			name:       "LOWEST_MAGNITUDE は効果の小さい政策を選ぶ",
+			rule:       entity.TieBreakLowestMagnitude,
+			wantStatus: entity.RoomStatusResult,
+			wantPassed: "policy_002",
+		},
+		{
+			name:        "HOST_DECIDES はホストの選択を待つ",
+			rule:        entity.TieBreakHostDecides,
+			wantStatus:  entity.RoomStatusVoting,
+			wantPending: true,
+		},
+		{
+			name:        "REVOTE は同数の政策で再投票する",
+			rule:        entity.TieBreakRevote,
+			wantStatus:  entity.RoomStatusVoting,
+			wantPending: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := env.startedRoom(t, "p1")
+			env.updateRoom(t, roomID, func(room *entity.Room) {
+				room.Settings.TieBreakRule = tt.rule
+				room.CurrentPolicyIDs = []string{"policy_001", "policy_002", "policy_003"}
+				room.Votes = map[string]string{"host": "policy_001", "p1": "policy_002"}
+			})
+
+			out, err := env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID})
+			assertErr(t, err, nil)
+
+			room := env.room(t, roomID)
+			if room.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", room.Status, tt.wantStatus)
+			}
+			if out.IsResolved == tt.wantPending {
+				t.Errorf("IsResolved = %v, want %v", out.IsResolved, !tt.wantPending)
+			}
+			if !tt.wantPending {
+				if room.LastResult.PassedPolicyID != tt.wantPassed {
+					t.Errorf("passed = %s, want %s", room.LastResult.PassedPolicyID, tt.wantPassed)
+				}
+				return
+			}
+
+			if room.PendingTie == nil {
+				t.Fatal("pendingTie が設定されていない")
+			}
+			if want := []string{"policy_001", "policy_002"}; !reflect.DeepEqual(room.PendingTie.PolicyIDs, want) {
+				t.Errorf("pendingTie.policyIds = %v, want %v", room.PendingTie.PolicyIDs, want)
+			}
+			if !env.publisher.has(entity.RoomEventTiePending) {
+				t.Error("TIE_PENDING が配信されていない")
+			}
+			// 決着待ちの間は集計できない（再投票は全員の投票がリセットされている）
+			_, err = env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID})
+			if tt.rule == entity.TieBreakRevote {
+				assertErr(t, err, entity.ErrNotAllVoted)
+				if vote := env.player(t, roomID, "host").CurrentVote; vote != "" {
+					t.Errorf("host.currentVote = %q, want 空", vote)
+				}
+			} else {
+				assertErr(t, err, entity.ErrInvalidPhase)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// RetractVoteInput は投票の取り消しの入力
+type RetractVoteInput struct {
+	RoomID string
+	UserID string
+}
+
+// RetractVoteOutput は投票の取り消しの出力
+type RetractVoteOutput struct {
+	Success   bool
+	Undecided int // まだ決めていないプレイヤーの数
+}
+
+// RetractVoteUseCase は投票を取り消すユースケース（集計前・確定前のみ）
+// DELETE /api/rooms/{roomId}/vote
+type RetractVoteUseCase struct {
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	transactor repository.Transactor
+	publisher  service.EventPublisher
+}
+
+// NewRetractVoteUseCase は RetractVoteUseCase を作成する
+func NewRetractVoteUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+	transactor repository.Transactor,
+	publisher service.EventPublisher,
+) *RetractVoteUseCase {
+	return &RetractVoteUseCase{
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+		transactor: transactor,
+		publisher:  publisher,
+	}
+}
+
+// Execute は投票を取り消す
+// 1. VOTING状態であること（ホストが同数の政策から選ぶのを待っていないこと）を確認
+// 2. 投票済みで、確定していないことを確認
+// 3. プレイヤーのcurrentVote・currentBallotをリセット
+// 4. Roomのvotes・ballotsを未投票に戻す
+// 1〜4 は1つのトランザクションで行う（最後の投票による集計と同時に実行されても票が残らない）
+func (uc *RetractVoteUseCase) Execute(ctx context.Context, input RetractVoteInput) (*RetractVoteOutput, error) {
+	var output *RetractVoteOutput
+	var turn int
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		// 部屋を取得
+		room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+
+		// VOTING状態でないと取り消せない（集計済みの場合もここで弾かれる）
+		if room.Status != entity.RoomStatusVoting || room.AwaitingHost() {
+			return entity.ErrInvalidPhase
+		}
+		turn = room.Turn
+
+		// プレイヤーを取得
+		player, err := uc.playerRepo.FindByID(ctx, input.RoomID, input.UserID)
+		if err != nil {
+			return err
+		}
+		if player == nil {
+			return entity.ErrPlayerNotInRoom
+		}
+
+		// 投票済みで、確定していないこと
+		if !room.HasVoted(input.UserID) {
+			return entity.ErrNotVoted
+		}
+		if room.IsLockedIn(input.UserID) {
+			return entity.ErrVoteLockedIn
+		}
+
+		// まだ決めていない人数を数えるためにプレイヤー数を取得（書き込み前に読み取りを済ませる）
+		playerCount, err := uc.playerRepo.CountByRoomID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+
+		// プレイヤーの投票とRoomのvotes・ballotsを未投票に戻す
+		room.ClearBallot(input.UserID)
+		room.Touch(time.Now())
+		if err := uc.playerRepo.UpdateCurrentVote(ctx, input.RoomID, input.UserID, nil); err != nil {
+			return err
+		}
+		if err := uc.roomRepo.UpdateVote(ctx, input.RoomID, input.UserID, nil, false, room.LastActivityAt); err != nil {
+			return err
+		}
+
+		output = &RetractVoteOutput{
+			Success:   true,
+			Undecided: room.Undecided(playerCount),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := entity.NewRoomEvent(entity.RoomEventVoteRetracted, input.RoomID, nil)
+	event.PlayerID = input.UserID
+	event.Turn = turn
+	event.Data["undecided"] = output.Undecided
+	publishEvent(ctx, uc.publisher, event)
+
+	return output, nil
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestRetractVoteUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, env *testEnv, roomID string)
+		userID  string
+		wantErr error
+	}{
+		{
+			name: "投票を取り消せる",
+			setup: func(t *testing.T, env *testEnv, roomID string) {
+				env.updateRoom(t, roomID, func(room *entity.Room) { room.CastBallot("host", []string{room.CurrentPolicyIDs[0]}) })
+			},
+			userID: "host",
+		},
+		{
+			name:    "未投票なら取り消せない",
+			userID:  "host",
+			wantErr: entity.ErrNotVoted,
+		},
+		{
+			name: "確定した投票は取り消せない",
+			setup: func(t *testing.T, env *testEnv, roomID string) {
+				env.updateRoom(t, roomID, func(room *entity.Room) {
+					room.CastBallot("host", []string{room.CurrentPolicyIDs[0]})
+					room.LockIn("host")
+				})
+			},
+			userID:  "host",
+			wantErr: entity.ErrVoteLockedIn,
+		},
+		{
+			name:    "部屋にいないプレイヤー",
+			userID:  "stranger",
+			wantErr: entity.ErrPlayerNotInRoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := env.startedRoom(t, "p1")
+			if tt.setup != nil {
+				tt.setup(t, env, roomID)
+			}
+
+			out, err := env.retractVoteUC().Execute(context.Background(), RetractVoteInput{RoomID: roomID, UserID: tt.userID})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			if out.Undecided != 2 {
+				t.Errorf("Undecided = %d, want 2", out.Undecided)
+			}
+			room := env.room(t, roomID)
+			if room.HasVoted("host") || len(room.Ballots["host"]) != 0 {
+				t.Errorf("votes = %v, ballots = %v, want 未投票", room.Votes, room.Ballots)
+			}
+			if got := env.player(t, roomID, "host").CurrentVote; got != "" {
+				t.Errorf("currentVote = %q, want 空", got)
+			}
+			if !env.publisher.has(entity.RoomEventVoteRetracted) {
+				t.Error("VOTE_RETRACTED が配信されていない")
+			}
+		})
+	}
+}
+
+func TestRetractVoteUseCase_NotVoting(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+	_, err := env.retractVoteUC().Execute(context.Background(), RetractVoteInput{RoomID: roomID, UserID: "host"})
+	assertErr(t, err, entity.ErrInvalidPhase)
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// RetryCityImageInput は街の画像のやり直しの入力
+type RetryCityImageInput struct {
+	RoomID string
+	Turn   int // 画像を生成するターン（部屋がこのターンの結果を表示している間だけやり直す）
+}
+
+// RetryCityImageOutput は街の画像のやり直しの出力
+type RetryCityImageOutput struct {
+	CityImageURL string // アップロードした画像の signed URL（やり直す必要がなかった場合は空）
+}
+
+// RetryCityImageUseCase は集計後に失敗した街の画像の生成・アップロードをやり直すユースケース
+// CITY_IMAGE ジョブから呼び出す（エンドポイントはない）
+type RetryCityImageUseCase struct {
+	roomRepo repository.RoomRepository
+	resolver *turnResolver
+}
+
+// NewRetryCityImageUseCase は RetryCityImageUseCase を作成する
+func NewRetryCityImageUseCase(
+	roomRepo repository.RoomRepository,
+	policyRepo repository.PolicyRepository,
+	imageGenerator service.ImageGenerator,
+	imageStorage service.ImageStorage,
+) *RetryCityImageUseCase {
+	return &RetryCityImageUseCase{
+		roomRepo: roomRepo,
+		resolver: &turnResolver{
+			roomRepo:       roomRepo,
+			policyRepo:     policyRepo,
+			imageGenerator: imageGenerator,
+			imageStorage:   imageStorage,
+		},
+	}
+}
+
+// Execute は街の画像を生成・アップロードし、lastResult.cityImageUrl を保存する
+// 部屋がなくなった・次のターンに進んだ・既に画像がある場合は何もしない（同じジョブが重複して実行されても1回だけ保存する）
+// 失敗した場合はエラーを返す（ジョブを再試行させる）
+func (uc *RetryCityImageUseCase) Execute(ctx context.Context, input RetryCityImageInput) (*RetryCityImageOutput, error) {
+	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil || room.LastResult == nil || room.Turn != input.Turn || room.LastResult.CityImageURL != "" {
+		return &RetryCityImageOutput{}, nil
+	}
+	if room.Status != entity.RoomStatusResult && room.Status != entity.RoomStatusFinished {
+		return &RetryCityImageOutput{}, nil
+	}
+	if uc.resolver.imageGenerator == nil || uc.resolver.imageStorage == nil {
+		slog.Warn("image generator or storage is not configured, skipping city image retry", slog.String("roomId", input.RoomID))
+		return &RetryCityImageOutput{}, nil
+	}
+
+	if err := uc.resolver.generateCityImage(ctx, input.RoomID, room); err != nil {
+		return nil, err
+	}
+	return &RetryCityImageOutput{CityImageURL: room.LastResult.CityImageURL}, nil
+}
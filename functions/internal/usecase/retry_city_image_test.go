@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestRetryCityImageUseCase_Execute(t *testing.T) {
+	env := newTestEnv(t)
+	env.imageGenerator.err = errors.New("image api down")
+	roomID := resultRoom(t, env)
+	ctx := context.Background()
+
+	// 集計時に失敗した画像はジョブでやり直す
+	jobs, err := env.jobs.Claim(ctx, time.Now().Add(time.Hour), time.Minute, 10)
+	assertErr(t, err, nil)
+	var imageJob *entity.Job
+	for _, job := range jobs {
+		if job.Type == entity.JobTypeCityImage {
+			imageJob = job
+		}
+	}
+	if imageJob == nil || imageJob.Payload["roomId"] != roomID || imageJob.Payload["turn"] != "1" {
+		t.Fatalf("jobs = %+v, want 部屋のターン1の CITY_IMAGE ジョブ", jobs)
+	}
+
+	// まだ失敗する間はエラーを返す（ジョブを再試行させる）
+	_, err = env.retryCityImageUC().Execute(ctx, RetryCityImageInput{RoomID: roomID, Turn: 1})
+	if err == nil {
+		t.Fatal("画像の生成に失敗したのにエラーにならない")
+	}
+
+	env.imageGenerator.err = nil
+	out, err := env.retryCityImageUC().Execute(ctx, RetryCityImageInput{RoomID: roomID, Turn: 1})
+	assertErr(t, err, nil)
+	if out.CityImageURL == "" || env.room(t, roomID).LastResult.CityImageURL != out.CityImageURL {
+		t.Fatalf("cityImageUrl = %q, want アップロードした URL", env.room(t, roomID).LastResult.CityImageURL)
+	}
+
+	// 既に画像がある・別のターンなら何もしない
+	uploads := len(env.imageStorage.uploads)
+	for _, turn := range []int{1, 2} {
+		out, err := env.retryCityImageUC().Execute(ctx, RetryCityImageInput{RoomID: roomID, Turn: turn})
+		assertErr(t, err, nil)
+		if out.CityImageURL != "" {
+			t.Errorf("turn %d: やり直す必要がないのにアップロードされた", turn)
+		}
+	}
+	if len(env.imageStorage.uploads) != uploads {
+		t.Errorf("uploads = %d, want %d", len(env.imageStorage.uploads), uploads)
+	}
+}
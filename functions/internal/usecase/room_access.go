@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// UpdateRoomAccessInput は部屋への参加の制限の変更の入力（nil の項目は変更しない）
+type UpdateRoomAccessInput struct {
+	RoomID     string
+	UserID     string  // ホストチェック用
+	Password   *string // 空文字ならパスワードを解除する
+	InviteOnly *bool
+}
+
+// UpdateRoomAccessOutput は部屋への参加の制限の変更の出力
+type UpdateRoomAccessOutput struct {
+	HasPassword bool
+	InviteOnly  bool
+}
+
+// UpdateRoomAccessUseCase は部屋のパスワード・招待のみの設定を変更するユースケース
+// POST /api/rooms/{roomId}/access
+type UpdateRoomAccessUseCase struct {
+	roomRepo   repository.RoomRepository
+	transactor repository.Transactor
+}
+
+// NewUpdateRoomAccessUseCase は UpdateRoomAccessUseCase を作成する
+func NewUpdateRoomAccessUseCase(
+	roomRepo repository.RoomRepository,
+	transactor repository.Transactor,
+) *UpdateRoomAccessUseCase {
+	return &UpdateRoomAccessUseCase{
+		roomRepo:   roomRepo,
+		transactor: transactor,
+	}
+}
+
+// Execute は部屋への参加の制限を変更する（ホストのみ、LOBBY のみ）
+// パスワードのハッシュはトランザクションの外で作る（時間がかかるため）
+func (uc *UpdateRoomAccessUseCase) Execute(ctx context.Context, input UpdateRoomAccessInput) (*UpdateRoomAccessOutput, error) {
+	var passwordHash string
+	if input.Password != nil {
+		var hashed entity.Room
+		if err := hashed.SetPassword(*input.Password); err != nil {
+			return nil, err
+		}
+		passwordHash = hashed.PasswordHash
+	}
+
+	var room *entity.Room
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		room, err = uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+
+		// ホストチェック
+		if room.HostID != input.UserID {
+			return entity.ErrNotHost
+		}
+
+		// LOBBY状態でないと変更できない
+		if room.Status != entity.RoomStatusLobby {
+			return entity.ErrInvalidPhase
+		}
+
+		if input.Password != nil {
+			room.PasswordHash = passwordHash
+		}
+		if input.InviteOnly != nil {
+			room.InviteOnly = *input.InviteOnly
+		}
+
+		room.Touch(time.Now())
+		return uc.roomRepo.Update(ctx, input.RoomID, room)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateRoomAccessOutput{
+		HasPassword: room.HasPassword(),
+		InviteOnly:  room.InviteOnly,
+	}, nil
+}
+
+// CreateInviteInput は招待の発行の入力
+type CreateInviteInput struct {
+	RoomID string
+	UserID string        // ホストチェック用
+	TTL    time.Duration // 0 なら entity.DefaultInviteTTL
+}
+
+// CreateInviteOutput は招待の発行の出力
+type CreateInviteOutput struct {
+	Token     string // 招待トークン（この応答でのみ返す。サーバーにはハッシュのみ保存）
+	ExpiresAt time.Time
+}
+
+// CreateInviteUseCase はホストが1回だけ使える招待を発行するユースケース
+// POST /api/rooms/{roomId}/invites
+type CreateInviteUseCase struct {
+	roomRepo   repository.RoomRepository
+	transactor repository.Transactor
+}
+
+// NewCreateInviteUseCase は CreateInviteUseCase を作成する
+func NewCreateInviteUseCase(
+	roomRepo repository.RoomRepository,
+	transactor repository.Transactor,
+) *CreateInviteUseCase {
+	return &CreateInviteUseCase{
+		roomRepo:   roomRepo,
+		transactor: transactor,
+	}
+}
+
+// Execute は招待を発行する（ホストのみ、LOBBY のみ）
+// 招待で参加するとパスワード・招待のみの制限を通れる。使った招待・期限切れの招待は使えない
+func (uc *CreateInviteUseCase) Execute(ctx context.Context, input CreateInviteInput) (*CreateInviteOutput, error) {
+	ttl := input.TTL
+	if ttl == 0 {
+		ttl = entity.DefaultInviteTTL
+	}
+
+	var token string
+	var invite *entity.RoomInvite
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+
+		// ホストチェック
+		if room.HostID != input.UserID {
+			return entity.ErrNotHost
+		}
+
+		// 参加できるのは LOBBY のみ
+		if room.Status != entity.RoomStatusLobby {
+			return entity.ErrGameAlreadyStarted
+		}
+
+		now := time.Now()
+		token, invite, err = room.CreateInvite(now, ttl)
+		if err != nil {
+			return err
+		}
+
+		room.Touch(now)
+		return uc.roomRepo.Update(ctx, input.RoomID, room)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateInviteOutput{
+		Token:     token,
+		ExpiresAt: invite.ExpiresAt,
+	}, nil
+}
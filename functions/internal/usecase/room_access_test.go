@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestJoinRoomUseCase_Password(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	created, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト", Password: "secret"})
+	assertErr(t, err, nil)
+	if !created.HasPassword {
+		t.Fatal("HasPassword = false, want true")
+	}
+	if hash := env.room(t, created.RoomID).PasswordHash; hash == "" || hash == "secret" {
+		t.Errorf("PasswordHash = %q, want a hash", hash)
+	}
+
+	join := func(password string) error {
+		_, err := env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: created.RoomID, UserID: "guest", DisplayName: "ゲスト", Password: password})
+		return err
+	}
+	assertErr(t, join(""), entity.ErrRoomPasswordRequired)
+	assertErr(t, join("wrong"), entity.ErrInvalidRoomPassword)
+	assertErr(t, join("secret"), nil)
+
+	if player, err := env.playerRepo.FindByID(ctx, created.RoomID, "guest"); err != nil || player == nil {
+		t.Fatalf("FindByID = %v, %v, want the guest", player, err)
+	}
+
+	_, err = env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト", Password: "abc"})
+	assertErr(t, err, entity.ErrInvalidSettings)
+}
+
+func TestJoinRoomUseCase_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	created, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト", Password: "secret"})
+	assertErr(t, err, nil)
+
+	for i := 0; i < entity.MaxJoinFailures; i++ {
+		_, err := env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: created.RoomID, UserID: "guest", DisplayName: "ゲスト", Password: "wrong"})
+		assertErr(t, err, entity.ErrInvalidRoomPassword)
+	}
+
+	// 正しいパスワードでも、失敗が多すぎる間は参加できない
+	_, err = env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: created.RoomID, UserID: "guest", DisplayName: "ゲスト", Password: "secret"})
+	assertErr(t, err, entity.ErrTooManyJoinAttempts)
+
+	// 有効な招待なら参加を受け付けない期間でも参加できる
+	invite, err := env.createInviteUC().Execute(ctx, CreateInviteInput{RoomID: created.RoomID, UserID: "host"})
+	assertErr(t, err, nil)
+	_, err = env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: created.RoomID, UserID: "invited", DisplayName: "招待", InviteToken: invite.Token})
+	assertErr(t, err, nil)
+
+	// 期間が過ぎれば参加できる
+	env.updateRoom(t, created.RoomID, func(room *entity.Room) {
+		room.JoinFailures.WindowStart = time.Now().Add(-entity.JoinFailureWindow - time.Second)
+	})
+	_, err = env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: created.RoomID, UserID: "guest", DisplayName: "ゲスト", Password: "secret"})
+	assertErr(t, err, nil)
+}
+
+func TestCreateInviteUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	created, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト", Password: "secret", InviteOnly: true})
+	assertErr(t, err, nil)
+	roomID := created.RoomID
+
+	_, err = env.createInviteUC().Execute(ctx, CreateInviteInput{RoomID: roomID, UserID: "guest"})
+	assertErr(t, err, entity.ErrNotHost)
+	_, err = env.createInviteUC().Execute(ctx, CreateInviteInput{RoomID: roomID, UserID: "host", TTL: time.Second})
+	assertErr(t, err, entity.ErrInvalidSettings)
+
+	invite, err := env.createInviteUC().Execute(ctx, CreateInviteInput{RoomID: roomID, UserID: "host"})
+	assertErr(t, err, nil)
+	if invite.Token == "" || !invite.ExpiresAt.After(time.Now()) {
+		t.Fatalf("invite = %+v, want a token that has not expired", invite)
+	}
+
+	join := func(userID, password, token string) error {
+		_, err := env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: roomID, UserID: userID, DisplayName: userID, Password: password, InviteToken: token})
+		return err
+	}
+
+	// 招待のみの部屋はパスワードだけでは参加できない
+	assertErr(t, join("p1", "secret", ""), entity.ErrInviteRequired)
+	assertErr(t, join("p1", "", "NOPE-NOPE"), entity.ErrInvalidInvite)
+
+	// 招待はパスワードなしで使え、1回しか使えない
+	assertErr(t, join("p1", "", invite.Token), nil)
+	assertErr(t, join("p2", "", invite.Token), entity.ErrInvalidInvite)
+
+	// 期限切れの招待は使えない
+	expired, err := env.createInviteUC().Execute(ctx, CreateInviteInput{RoomID: roomID, UserID: "host"})
+	assertErr(t, err, nil)
+	env.updateRoom(t, roomID, func(room *entity.Room) {
+		for _, inv := range room.Invites {
+			inv.ExpiresAt = time.Now().Add(-time.Second)
+		}
+	})
+	assertErr(t, join("p2", "", expired.Token), entity.ErrInvalidInvite)
+}
+
+func TestJoinRoomUseCase_InviteKeptOnFailedJoin(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	created, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト", InviteOnly: true})
+	assertErr(t, err, nil)
+	invite, err := env.createInviteUC().Execute(ctx, CreateInviteInput{RoomID: created.RoomID, UserID: "host"})
+	assertErr(t, err, nil)
+
+	// 参加に失敗した場合は招待を使ったことにならない
+	_, err = env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: created.RoomID, UserID: "host", DisplayName: "ホスト", InviteToken: invite.Token})
+	assertErr(t, err, entity.ErrPlayerAlreadyInRoom)
+	_, err = env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomID: created.RoomID, UserID: "guest", DisplayName: "ゲスト", InviteToken: invite.Token})
+	assertErr(t, err, nil)
+}
+
+func TestUpdateRoomAccessUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+
+	password, inviteOnly := "secret", true
+	_, err := env.updateRoomAccessUC().Execute(ctx, UpdateRoomAccessInput{RoomID: roomID, UserID: "guest", Password: &password})
+	assertErr(t, err, entity.ErrNotHost)
+
+	out, err := env.updateRoomAccessUC().Execute(ctx, UpdateRoomAccessInput{RoomID: roomID, UserID: "host", Password: &password})
+	assertErr(t, err, nil)
+	if !out.HasPassword || out.InviteOnly {
+		t.Errorf("out = %+v, want password only", out)
+	}
+	if !env.room(t, roomID).CheckPassword("secret") {
+		t.Error("CheckPassword(secret) = false, want true")
+	}
+
+	cleared := ""
+	out, err = env.updateRoomAccessUC().Execute(ctx, UpdateRoomAccessInput{RoomID: roomID, UserID: "host", Password: &cleared, InviteOnly: &inviteOnly})
+	assertErr(t, err, nil)
+	if out.HasPassword || !out.InviteOnly {
+		t.Errorf("out = %+v, want invite-only without password", out)
+	}
+}
+
+func TestQuickMatchUseCase_SkipsRestrictedRooms(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	locked, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "alice", DisplayName: "alice", Visibility: entity.RoomVisibilityPublic, Password: "secret"})
+	assertErr(t, err, nil)
+	inviteOnly, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "bob", DisplayName: "bob", Visibility: entity.RoomVisibilityPublic, InviteOnly: true})
+	assertErr(t, err, nil)
+
+	lobbies, err := env.listLobbiesUC().Execute(ctx, ListLobbiesInput{})
+	assertErr(t, err, nil)
+	if len(lobbies.Lobbies) != 1 || lobbies.Lobbies[0].RoomID != locked.RoomID || !lobbies.Lobbies[0].HasPassword {
+		t.Errorf("Lobbies = %+v, want only the password room", lobbies.Lobbies)
+	}
+
+	out, err := env.quickMatchUC().Execute(ctx, QuickMatchInput{UserID: "carol", DisplayName: "carol"})
+	assertErr(t, err, nil)
+	if !out.Created || out.RoomID == locked.RoomID || out.RoomID == inviteOnly.RoomID {
+		t.Errorf("out = %+v, want a new room", out)
+	}
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+// roomCodeAttempts は部屋コードの文字数ごとに予約を試す回数
+// 埋まっている割合が高くなったら次の文字数（より長いコード）に移る
+const roomCodeAttempts = 8
+
+// allocateRoomCode は部屋コードを予約して返す
+// 短いコードから試し、予約済みのコードが削除済みの部屋のもの（解放に失敗した予約）なら解放して使う
+// どの文字数でも予約できなければ entity.ErrNoRoomCodeLeft を返す
+func allocateRoomCode(
+	ctx context.Context,
+	codeRepo repository.RoomCodeRepository,
+	roomRepo repository.RoomRepository,
+	transactor repository.Transactor,
+	roomID string,
+	now time.Time,
+) (string, error) {
+	for _, length := range entity.RoomCodeLengths {
+		for i := 0; i < roomCodeAttempts; i++ {
+			code, err := entity.NewRoomCode(length)
+			if err != nil {
+				return "", err
+			}
+
+			err = codeRepo.Reserve(ctx, code, roomID, now)
+			if err == nil {
+				return code, nil
+			}
+			if !errors.Is(err, entity.ErrRoomCodeTaken) {
+				return "", err
+			}
+
+			reclaimed, err := reclaimRoomCode(ctx, codeRepo, roomRepo, transactor, code)
+			if err != nil {
+				return "", err
+			}
+			if !reclaimed {
+				continue
+			}
+			// 解放した直後に他の部屋が予約した場合は次のコードを試す
+			err = codeRepo.Reserve(ctx, code, roomID, now)
+			if err == nil {
+				return code, nil
+			}
+			if !errors.Is(err, entity.ErrRoomCodeTaken) {
+				return "", err
+			}
+		}
+	}
+	return "", entity.ErrNoRoomCodeLeft
+}
+
+// reclaimRoomCode は削除済みの部屋が予約したままの部屋コードを解放する
+// 予約と部屋の確認から解放までは1つのトランザクションで行う（その間に予約し直されたコードは解放しない）
+func reclaimRoomCode(
+	ctx context.Context,
+	codeRepo repository.RoomCodeRepository,
+	roomRepo repository.RoomRepository,
+	transactor repository.Transactor,
+	code string,
+) (bool, error) {
+	reclaimed := false
+	err := transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		reclaimed = false
+
+		roomID, err := codeRepo.FindRoomID(ctx, code)
+		if err != nil {
+			return err
+		}
+		if roomID == "" {
+			// 既に解放されている
+			reclaimed = true
+			return nil
+		}
+
+		room, err := roomRepo.FindByID(ctx, roomID)
+		if err != nil {
+			return err
+		}
+		if room != nil {
+			return nil
+		}
+
+		reclaimed = true
+		return codeRepo.Release(ctx, code)
+	})
+	if err != nil {
+		return false, err
+	}
+	return reclaimed, nil
+}
+
+// resolveRoomCode は入力された部屋コードを部屋IDに変換する
+// 形式が正しくなければ entity.ErrInvalidRoomCode、予約されていなければ entity.ErrRoomNotFound を返す
+func resolveRoomCode(ctx context.Context, codeRepo repository.RoomCodeRepository, input string) (string, error) {
+	code := entity.NormalizeRoomCode(input)
+	if !entity.ValidRoomCode(code) {
+		return "", entity.ErrInvalidRoomCode
+	}
+
+	roomID, err := codeRepo.FindRoomID(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	if roomID == "" {
+		return "", entity.ErrRoomNotFound
+	}
+	return roomID, nil
+}
+
+// FindRoomByCodeInput は部屋コードによる部屋の検索の入力
+type FindRoomByCodeInput struct {
+	Code string // 入力されたままの部屋コード（小文字・ハイフンを含んでもよい）
+}
+
+// FindRoomByCodeOutput は部屋コードによる部屋の検索の出力
+// 参加前に見せる情報のみ（プレイヤーの一覧や秘匿情報は含めない）
+type FindRoomByCodeOutput struct {
+	RoomID      string
+	Code        string
+	Status      entity.RoomStatus
+	PlayerCount int
+	MaxPlayers  int
+}
+
+// FindRoomByCodeUseCase は部屋コードから部屋を探すユースケース
+// GET /api/codes/{code}
+type FindRoomByCodeUseCase struct {
+	codeRepo   repository.RoomCodeRepository
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+}
+
+// NewFindRoomByCodeUseCase は FindRoomByCodeUseCase を作成する
+func NewFindRoomByCodeUseCase(
+	codeRepo repository.RoomCodeRepository,
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+) *FindRoomByCodeUseCase {
+	return &FindRoomByCodeUseCase{
+		codeRepo:   codeRepo,
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+	}
+}
+
+// Execute は部屋コードを予約している部屋を返す
+func (uc *FindRoomByCodeUseCase) Execute(ctx context.Context, input FindRoomByCodeInput) (*FindRoomByCodeOutput, error) {
+	roomID, err := resolveRoomCode(ctx, uc.codeRepo, input.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := uc.roomRepo.FindByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		// 部屋の削除後に解放されなかった予約
+		return nil, entity.ErrRoomNotFound
+	}
+
+	players, err := uc.playerRepo.FindAllByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FindRoomByCodeOutput{
+		RoomID:      roomID,
+		Code:        room.Code,
+		Status:      room.Status,
+		PlayerCount: len(players),
+		MaxPlayers:  room.Settings.MaxPlayers,
+	}, nil
+}
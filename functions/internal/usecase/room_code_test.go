@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+)
+
+func TestCreateRoomUseCase_ReservesRoomCode(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	out, err := env.createRoomUC().Execute(ctx, CreateRoomInput{UserID: "host", DisplayName: "ホスト"})
+	assertErr(t, err, nil)
+	if len(out.RoomCode) != entity.RoomCodeLengths[0] || !entity.ValidRoomCode(out.RoomCode) {
+		t.Fatalf("RoomCode = %q, want %d chars", out.RoomCode, entity.RoomCodeLengths[0])
+	}
+	if got := env.room(t, out.RoomID).Code; got != out.RoomCode {
+		t.Errorf("room.Code = %q, want %q", got, out.RoomCode)
+	}
+	if roomID, _ := env.codeRepo.FindRoomID(ctx, out.RoomCode); roomID != out.RoomID {
+		t.Errorf("予約した部屋 = %q, want %q", roomID, out.RoomID)
+	}
+}
+
+func TestFindRoomByCodeUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+	env.join(t, roomID, "p1")
+	code := env.room(t, roomID).Code
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{name: "予約した部屋コード", code: code},
+		{name: "小文字・区切りを入れても同じコード", code: strings.ToLower(code[:2] + "-" + code[2:])},
+		{name: "形式が正しくない", code: "AB", wantErr: entity.ErrInvalidRoomCode},
+		{name: "使わない文字を含む", code: "OOOO", wantErr: entity.ErrInvalidRoomCode},
+		{name: "予約されていない", code: "ZZZZZZ", wantErr: entity.ErrRoomNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := env.findRoomByCodeUC().Execute(ctx, FindRoomByCodeInput{Code: tt.code})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if out.RoomID != roomID || out.Code != code {
+				t.Errorf("RoomID, Code = %s, %s, want %s, %s", out.RoomID, out.Code, roomID, code)
+			}
+			if out.PlayerCount != 2 || out.Status != entity.RoomStatusLobby {
+				t.Errorf("PlayerCount, Status = %d, %s, want 2, LOBBY", out.PlayerCount, out.Status)
+			}
+		})
+	}
+}
+
+func TestJoinRoomUseCase_ByRoomCode(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+	code := env.room(t, roomID).Code
+
+	out, err := env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomCode: strings.ToLower(code), UserID: "guest", DisplayName: "ゲスト"})
+	assertErr(t, err, nil)
+	if out.RoomID != roomID {
+		t.Errorf("RoomID = %s, want %s", out.RoomID, roomID)
+	}
+	env.player(t, roomID, "guest")
+
+	_, err = env.joinRoomUC().Execute(ctx, JoinRoomInput{RoomCode: "ZZZZZZ", UserID: "guest2", DisplayName: "ゲスト2"})
+	assertErr(t, err, entity.ErrRoomNotFound)
+}
+
+func TestRoomCode_ReleasedWhenRoomDeleted(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	now := time.Now()
+
+	// 最後のプレイヤーが退出した部屋
+	leftRoom := env.createRoom(t)
+	leftCode := env.room(t, leftRoom).Code
+	_, err := env.leaveRoomUC().Execute(ctx, LeaveRoomInput{RoomID: leftRoom, UserID: "host"})
+	assertErr(t, err, nil)
+
+	// 操作がなく削除された部屋
+	idleRoom := env.createRoom(t)
+	idleCode := env.room(t, idleRoom).Code
+	env.updateRoom(t, idleRoom, func(room *entity.Room) { room.Touch(now.Add(-2 * time.Hour)) })
+	_, err = env.cleanupRoomsUC().Execute(ctx, CleanupRoomsInput{Now: now, TTL: entity.RoomTTL{Idle: time.Hour, Finished: time.Hour}})
+	assertErr(t, err, nil)
+
+	for _, code := range []string{leftCode, idleCode} {
+		if roomID, _ := env.codeRepo.FindRoomID(ctx, code); roomID != "" {
+			t.Errorf("部屋コード %s が解放されていない（%s）", code, roomID)
+		}
+		_, err := env.findRoomByCodeUC().Execute(ctx, FindRoomByCodeInput{Code: code})
+		assertErr(t, err, entity.ErrRoomNotFound)
+	}
+}
+
+func TestAllocateRoomCode_ReclaimsStaleReservation(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+	now := time.Now()
+
+	// 部屋がある予約は解放しない
+	liveCode := env.room(t, roomID).Code
+	reclaimed, err := reclaimRoomCode(ctx, env.codeRepo, env.roomRepo, env.transactor, liveCode)
+	assertErr(t, err, nil)
+	if reclaimed {
+		t.Error("部屋がある予約を解放した")
+	}
+
+	// 削除済みの部屋の予約は解放して再利用する
+	if err := env.codeRepo.Reserve(ctx, "ABCD", "deleted-room", now); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	reclaimed, err = reclaimRoomCode(ctx, env.codeRepo, env.roomRepo, env.transactor, "ABCD")
+	assertErr(t, err, nil)
+	if !reclaimed {
+		t.Fatal("削除済みの部屋の予約を解放していない")
+	}
+	assertErr(t, env.codeRepo.Reserve(ctx, "ABCD", roomID, now), nil)
+}
+
+func TestAllocateRoomCode_FallsBackToLongerCodes(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+
+	// 4文字のコードが全て使われている
+	codes := &takenCodeRepository{RoomCodeRepository: env.codeRepo, takenBelow: 5, roomID: roomID}
+	code, err := allocateRoomCode(ctx, codes, env.roomRepo, env.transactor, roomID, time.Now())
+	assertErr(t, err, nil)
+	if len(code) != 5 {
+		t.Errorf("code = %q, want 5 chars", code)
+	}
+
+	// 全ての文字数で使われている
+	codes.takenBelow = 7
+	_, err = allocateRoomCode(ctx, codes, env.roomRepo, env.transactor, roomID, time.Now())
+	assertErr(t, err, entity.ErrNoRoomCodeLeft)
+}
+
+// takenCodeRepository は takenBelow 文字未満の部屋コードが全て roomID の部屋に予約済みの RoomCodeRepository
+type takenCodeRepository struct {
+	repository.RoomCodeRepository
+	takenBelow int
+	roomID     string
+}
+
+func (r *takenCodeRepository) Reserve(ctx context.Context, code, roomID string, at time.Time) error {
+	if len(code) < r.takenBelow {
+		return entity.ErrRoomCodeTaken
+	}
+	return r.RoomCodeRepository.Reserve(ctx, code, roomID, at)
+}
+
+func (r *takenCodeRepository) FindRoomID(ctx context.Context, code string) (string, error) {
+	if len(code) < r.takenBelow {
+		return r.roomID, nil
+	}
+	return r.RoomCodeRepository.FindRoomID(ctx, code)
+}
@@ -2,10 +2,11 @@ package usecase
 
 import (
 	"context"
-	"math/rand"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
 )
 
 // StartGameInput はゲーム開始の入力
@@ -25,6 +26,9 @@ type StartGameUseCase struct {
 	roomRepo   repository.RoomRepository
 	playerRepo repository.PlayerRepository
 	policyRepo repository.PolicyRepository
+	transactor repository.Transactor
+	jobs       repository.JobQueue
+	publisher  service.EventPublisher
 }
 
 // NewStartGameUseCase は StartGameUseCase を作成する
@@ -32,95 +36,106 @@ func NewStartGameUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
 	policyRepo repository.PolicyRepository,
+	transactor repository.Transactor,
+	jobs repository.JobQueue,
+	publisher service.EventPublisher,
 ) *StartGameUseCase {
 	return &StartGameUseCase{
 		roomRepo:   roomRepo,
 		playerRepo: playerRepo,
 		policyRepo: policyRepo,
+		transactor: transactor,
+		jobs:       jobs,
+		publisher:  publisher,
 	}
 }
 
 // Execute はゲームを開始する
 // 1. ホストであることを確認
 // 2. 全員Readyであることを確認
-// 3. 全政策IDを取得してシャッフル → deckIds
-// 4. 先頭3枚を currentPolicyIds に
-// 5. deckIds から3枚を削除
-// 6. status を VOTING に、turn を 1 に
+// 3. 全政策IDを取得してシャッフル → deckIds（捨て札は空にする）
+// 4. 先頭 optionsPerTurn 枚を currentPolicyIds に
+// 5. deckIds から optionsPerTurn 枚を削除
+// 6. status を VOTING に、turn を 1 に（votingTimeLimit があれば投票の期限を設定）
 // 7. 全プレイヤーの投票状態をリセット
+// 1〜7 は1つのトランザクションで行う（開始と同時の参加・退出を取りこぼさない）
 func (uc *StartGameUseCase) Execute(ctx context.Context, input StartGameInput) (*StartGameOutput, error) {
-	// 部屋を取得
-	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+	// 全政策IDを取得（マスターデータのためトランザクション外で読む）
+	allPolicyIDs, err := uc.policyRepo.GetAllIDs(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if room == nil {
-		return nil, entity.ErrRoomNotFound
-	}
 
-	// ホストチェック
-	if room.HostID != input.UserID {
-		return nil, entity.ErrNotHost
-	}
+	var room *entity.Room
+	err = uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		// 部屋を取得
+		var err error
+		room, err = uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
 
-	// LOBBY状態でないとスタートできない
-	if room.Status != entity.RoomStatusLobby {
-		return nil, entity.ErrInvalidPhase
-	}
+		// ホストチェック
+		if room.HostID != input.UserID {
+			return entity.ErrNotHost
+		}
 
-	// プレイヤー数を確認
-	players, err := uc.playerRepo.FindAllByRoomID(ctx, input.RoomID)
-	if err != nil {
-		return nil, err
-	}
-	if !room.CanStart(len(players)) {
-		return nil, entity.ErrNotEnoughPlayers
-	}
+		// LOBBY状態でないとスタートできない
+		if room.Status != entity.RoomStatusLobby {
+			return entity.ErrInvalidPhase
+		}
 
-	// 全員Readyかチェック
-	for _, p := range players {
-		if !p.IsReady && !p.IsHost { // ホストはReady不要
-			return nil, entity.ErrNotAllReady
+		// プレイヤー数を確認
+		players, err := uc.playerRepo.FindAllByRoomID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if !room.CanStart(len(players)) {
+			return entity.ErrNotEnoughPlayers
 		}
-	}
 
-	// 全政策IDを取得
-	allPolicyIDs, err := uc.policyRepo.GetAllIDs(ctx)
-	if err != nil {
-		return nil, err
-	}
+		// 全員Readyかチェック
+		for _, p := range players {
+			if !p.IsReady && !p.IsHost { // ホストはReady不要
+				return entity.ErrNotAllReady
+			}
+		}
 
-	// シャッフル
-	rand.Shuffle(len(allPolicyIDs), func(i, j int) {
-		allPolicyIDs[i], allPolicyIDs[j] = allPolicyIDs[j], allPolicyIDs[i]
-	})
+		// 全プレイヤーの投票状態をリセット（プレイヤーの読み取りを伴うため部屋の更新より先に行う）
+		if err := uc.playerRepo.ClearAllVotes(ctx, input.RoomID); err != nil {
+			return err
+		}
 
-	// 先頭3枚を currentPolicyIds に
-	currentCount := 3
-	if len(allPolicyIDs) < currentCount {
-		currentCount = len(allPolicyIDs)
-	}
+		// 部屋の乱数でシャッフル（トランザクションの再試行に備えてコピーしてから並べ替える）
+		room.ResetDeck(entity.ShuffleDeck(room.Rand(), allPolicyIDs))
 
-	room.CurrentPolicyIDs = allPolicyIDs[:currentCount]
-	room.DeckIDs = allPolicyIDs[currentCount:]
+		// 先頭 optionsPerTurn 枚を currentPolicyIds に
+		room.DealPolicies()
 
-	// 投票状態をリセット（キーは既にcreate_room/join_room時に設定済み）
-	for userID := range room.Votes {
-		room.Votes[userID] = ""
-	}
+		// 投票状態をリセット（キーは既にcreate_room/join_room時に設定済み）
+		room.ResetVotes()
 
-	// ゲーム開始
-	room.Start()
+		// ゲーム開始
+		room.Start()
+		room.ScheduleDeadline(time.Now())
 
-	// 部屋を更新
-	if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
+		// 部屋を更新
+		room.Touch(time.Now())
+		return uc.roomRepo.Update(ctx, input.RoomID, room)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 全プレイヤーの投票状態をリセット
-	if err := uc.playerRepo.ClearAllVotes(ctx, input.RoomID); err != nil {
-		return nil, err
-	}
+	// 投票の期限に期限切れの処理を予約
+	scheduleDeadline(ctx, uc.jobs, input.RoomID, room)
+
+	event := entity.NewRoomEvent(entity.RoomEventGameStarted, input.RoomID, room)
+	event.Data["currentPolicyIds"] = room.CurrentPolicyIDs
+	publishEvent(ctx, uc.publisher, event)
 
 	return &StartGameOutput{
 		Room: room,
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestStartGameUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, env *testEnv) string
+		userID  string
+		wantErr error
+	}{
+		{
+			name: "ホストが開始できる",
+			setup: func(t *testing.T, env *testEnv) string {
+				roomID := env.createRoom(t)
+				env.join(t, roomID, "p1")
+				return roomID
+			},
+			userID: "host",
+		},
+		{
+			name:    "存在しない部屋",
+			setup:   func(t *testing.T, env *testEnv) string { return "missing" },
+			userID:  "host",
+			wantErr: entity.ErrRoomNotFound,
+		},
+		{
+			name: "ホスト以外は開始できない",
+			setup: func(t *testing.T, env *testEnv) string {
+				roomID := env.createRoom(t)
+				env.join(t, roomID, "p1")
+				return roomID
+			},
+			userID:  "p1",
+			wantErr: entity.ErrNotHost,
+		},
+		{
+			name:    "1人では開始できない",
+			setup:   func(t *testing.T, env *testEnv) string { return env.createRoom(t) },
+			userID:  "host",
+			wantErr: entity.ErrNotEnoughPlayers,
+		},
+		{
+			name: "Readyでないプレイヤーがいる",
+			setup: func(t *testing.T, env *testEnv) string {
+				roomID := env.createRoom(t)
+				env.join(t, roomID, "p1")
+				if _, err := env.toggleReadyUC().Execute(context.Background(), ToggleReadyInput{RoomID: roomID, UserID: "p1"}); err != nil {
+					t.Fatalf("ToggleReady: %v", err)
+				}
+				return roomID
+			},
+			userID:  "host",
+			wantErr: entity.ErrNotAllReady,
+		},
+		{
+			name:    "開始済みの部屋",
+			setup:   func(t *testing.T, env *testEnv) string { return env.startedRoom(t, "p1") },
+			userID:  "host",
+			wantErr: entity.ErrInvalidPhase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := tt.setup(t, env)
+
+			out, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: tt.userID})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			room := env.room(t, roomID)
+			if room.Status != entity.RoomStatusVoting || room.Turn != 1 {
+				t.Errorf("status = %s, turn = %d, want VOTING, 1", room.Status, room.Turn)
+			}
+			if len(room.CurrentPolicyIDs) != 3 {
+				t.Errorf("currentPolicyIds = %d枚, want 3枚", len(room.CurrentPolicyIDs))
+			}
+			total := len(entity.GetDefaultPolicies())
+			if got := len(room.CurrentPolicyIDs) + len(room.DeckIDs); got != total {
+				t.Errorf("手札+山札 = %d枚, want %d枚", got, total)
+			}
+			if len(out.Room.CurrentPolicyIDs) != 3 {
+				t.Error("出力の部屋に選択肢が含まれていない")
+			}
+			if !env.publisher.has(entity.RoomEventGameStarted) {
+				t.Error("GAME_STARTED が配信されていない")
+			}
+		})
+	}
+}
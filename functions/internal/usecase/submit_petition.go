@@ -2,10 +2,12 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
-	"github.com/techworld-hackathon/functions/internal/interface/gateway/ai"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
 )
 
 // SubmitPetitionInput はAI陳情の入力
@@ -17,18 +19,22 @@ type SubmitPetitionInput struct {
 
 // SubmitPetitionOutput はAI陳情の出力
 type SubmitPetitionOutput struct {
-	Approved bool
-	PolicyID string
-	Message  string
+	Approved    bool
+	PolicyID    string
+	SurfaceTurn int // 承認された政策が選択肢として提示されるターン（承認時のみ）
+	Message     string
 }
 
 // SubmitPetitionUseCase はAI陳情のユースケース
-// POST /api/rooms/{roomId}/petitions
+// POST /api/rooms/{roomId}/petition
 type SubmitPetitionUseCase struct {
 	roomRepo   repository.RoomRepository
 	playerRepo repository.PlayerRepository
 	policyRepo repository.PolicyRepository
-	aiClient   *ai.SakuraAIClient
+	reviewer   service.PetitionReviewer
+	moderator  service.PetitionModerator
+	transactor repository.Transactor
+	publisher  service.EventPublisher
 }
 
 // NewSubmitPetitionUseCase は SubmitPetitionUseCase を作成する
@@ -36,21 +42,33 @@ func NewSubmitPetitionUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
 	policyRepo repository.PolicyRepository,
-	aiClient *ai.SakuraAIClient,
+	reviewer service.PetitionReviewer,
+	moderator service.PetitionModerator,
+	transactor repository.Transactor,
+	publisher service.EventPublisher,
 ) *SubmitPetitionUseCase {
 	return &SubmitPetitionUseCase{
 		roomRepo:   roomRepo,
 		playerRepo: playerRepo,
 		policyRepo: policyRepo,
-		aiClient:   aiClient,
+		reviewer:   reviewer,
+		moderator:  moderator,
+		transactor: transactor,
+		publisher:  publisher,
 	}
 }
 
 // Execute はAI陳情を実行する
-// 1. プレイヤーの isPetitionUsed を確認
-// 2. OpenAI API で審査
-// 3. 承認なら政策カードを生成して deckIds に追加
-// 4. プレイヤーの isPetitionUsed を true に
+// 1. プレイヤーの陳情回数が部屋の設定（petitionsPerPlayer）に達していないか確認
+// 2. モデレーション（文字数・禁止語・指示の上書き）で拒否されたらエラー
+// 3. OpenAI API で審査（却下なら理由を返す）
+// 4. 承認された政策を採用できなければ（不自然な効果など）却下として返す
+// 5. 政策カードを生成し、設定（petitionPlacement）に従って山札に入れる
+//   - REPLACE_CURRENT で入れ替えられた政策に投票していたプレイヤーは投票を取り消す
+//
+// 6. プレイヤーの陳情回数を1増やす（上限に達したら isPetitionUsed を true に）
+// 5〜6 はAI審査の後、1つのトランザクションで行う（審査の間に投票が集計されて VOTING でなくなっていればエラー）
+// 陳情回数を消費するのは政策が採用された場合のみ（2〜4 で弾かれた陳情、審査の間にフェーズが変わった陳情は消費しない）
 func (uc *SubmitPetitionUseCase) Execute(ctx context.Context, input SubmitPetitionInput) (*SubmitPetitionOutput, error) {
 	// 部屋を取得
 	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
@@ -75,11 +93,20 @@ func (uc *SubmitPetitionUseCase) Execute(ctx context.Context, input SubmitPetiti
 		return nil, entity.ErrPlayerNotFound
 	}
 
-	// 陳情を使用済みか確認
-	if player.IsPetitionUsed {
+	// 陳情回数が残っているか確認
+	if !player.CanPetition(room.Settings.PetitionsPerPlayer) {
 		return nil, entity.ErrPetitionUsed
 	}
 
+	// モデレーション（AIに渡す前に不適切な陳情を弾く）
+	moderation, err := uc.moderator.Moderate(ctx, input.PetitionText)
+	if err != nil {
+		return nil, err
+	}
+	if !moderation.Allowed {
+		return nil, fmt.Errorf("%w: %s", entity.ErrPetitionBlocked, moderation.Reason)
+	}
+
 	// 過去に採用された政策を取得
 	var passedPolicies []*entity.MasterPolicy
 	for _, policyID := range room.PassedPolicyIDs {
@@ -94,43 +121,145 @@ func (uc *SubmitPetitionUseCase) Execute(ctx context.Context, input SubmitPetiti
 	}
 
 	// AI審査（国の状況と過去の政策を考慮）
-	petitionCtx := &ai.PetitionContext{
+	petitionCtx := &service.PetitionContext{
 		PetitionText:   input.PetitionText,
 		PassedPolicies: passedPolicies,
 		CityParams:     room.CityParams,
+		CityRules:      room.Settings.CityRules,
 	}
-	result, err := uc.aiClient.ReviewPetition(ctx, petitionCtx)
+	result, err := uc.reviewer.ReviewPetition(ctx, petitionCtx)
 	if err != nil {
 		return nil, err
 	}
 
-	// プレイヤーの陳情フラグを更新
-	player.IsPetitionUsed = true
-	if err := uc.playerRepo.Update(ctx, input.RoomID, input.PlayerID, player); err != nil {
-		return nil, err
-	}
-
+	// 却下された陳情は何も書き込まない（陳情回数も消費しない）
 	if !result.Approved {
+		uc.publishPetitionSubmitted(ctx, input, room, false, 0)
 		return &SubmitPetitionOutput{
 			Approved: false,
 			Message:  "提案は審査の結果、却下されました: " + result.Reason,
 		}, nil
 	}
 
-	// 承認された場合、政策をRoomに保存
-	policyID := room.AddGeneratedPolicy(result.Policy)
+	// 生成された政策が陳情の内容に沿っているか確認（指示の上書きで別の政策を作らせるのを防ぐ）
+	relevance, err := uc.moderator.CheckRelevance(ctx, input.PetitionText, result.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if !relevance.Allowed {
+		return &SubmitPetitionOutput{
+			Approved: false,
+			Message:  "提案は採用されませんでした: " + relevance.Reason,
+		}, nil
+	}
 
-	// deckIds に追加
-	room.DeckIDs = append(room.DeckIDs, policyID)
+	// AI審査の間に他の操作が行われている可能性があるため、
+	// 陳情フラグと政策の追加はトランザクション内で読み直してから反映する
+	var policyID string
+	var placement entity.PetitionPlacementResult
+	err = uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		room, err = uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+		// 審査の間に集計・ゲーム終了していれば、集計済みのターンに政策を入れないよう中止する
+		if room.Status != entity.RoomStatusVoting {
+			return entity.ErrInvalidPhase
+		}
+		player, err := uc.playerRepo.FindByID(ctx, input.RoomID, input.PlayerID)
+		if err != nil {
+			return err
+		}
+		if player == nil {
+			return entity.ErrPlayerNotFound
+		}
+		if !player.CanPetition(room.Settings.PetitionsPerPlayer) {
+			return entity.ErrPetitionUsed
+		}
+
+		// 政策をRoomに保存して山札に入れる
+		policyID = room.AddGeneratedPolicy(result.Policy)
+		placement = room.PlacePetition(policyID)
+
+		// 投票を取り消すプレイヤーを読み込む（書き込みより先に読む）
+		clearedVoters := make(map[string]*entity.Player)
+		for _, userID := range placement.ClearedVoters {
+			if userID == input.PlayerID {
+				player.ClearVote() // 陳情したプレイヤー本人は下で1回だけ更新する
+				continue
+			}
+			voter, err := uc.playerRepo.FindByID(ctx, input.RoomID, userID)
+			if err != nil {
+				return err
+			}
+			if voter != nil {
+				clearedVoters[userID] = voter
+			}
+		}
+
+		// プレイヤーの陳情回数を更新
+		player.UsePetition(room.Settings.PetitionsPerPlayer)
+		if err := uc.playerRepo.Update(ctx, input.RoomID, input.PlayerID, player); err != nil {
+			return err
+		}
+
+		// 入れ替えられた政策への投票を取り消す
+		for userID, voter := range clearedVoters {
+			voter.ClearVote()
+			if err := uc.playerRepo.Update(ctx, input.RoomID, userID, voter); err != nil {
+				return err
+			}
+		}
 
-	// 部屋を更新
-	if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
+		// 部屋を更新
+		room.Touch(time.Now())
+		return uc.roomRepo.Update(ctx, input.RoomID, room)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	uc.publishPetitionSubmitted(ctx, input, room, true, placement.SurfaceTurn)
+	if placement.ReplacedPolicyID != "" {
+		// 提示中の選択肢が変わったことを知らせる
+		event := entity.NewRoomEvent(entity.RoomEventOptionsReplaced, input.RoomID, room)
+		event.Data["currentPolicyIds"] = room.CurrentPolicyIDs
+		event.Data["clearedVoters"] = placement.ClearedVoters
+		publishEvent(ctx, uc.publisher, event)
+	}
+
 	return &SubmitPetitionOutput{
-		Approved: true,
-		PolicyID: policyID,
-		Message:  "提案が承認されました！次ターン以降の選択肢に追加される可能性があります。",
+		Approved:    true,
+		PolicyID:    policyID,
+		SurfaceTurn: placement.SurfaceTurn,
+		Message:     petitionApprovedMessage(room, placement),
 	}, nil
 }
+
+// petitionApprovedMessage は承認された政策がいつ提示されるかを伝えるメッセージを返す
+func petitionApprovedMessage(room *entity.Room, placement entity.PetitionPlacementResult) string {
+	switch {
+	case placement.ReplacedPolicyID != "":
+		return "提案が承認されました！今ターンの選択肢に追加されました。"
+	case placement.SurfaceTurn > room.MaxTurns:
+		return "提案が承認されましたが、残りのターン内には選択肢に登場しません。"
+	case placement.SurfaceTurn == room.Turn+1:
+		return "提案が承認されました！次のターンの選択肢に追加されます。"
+	default:
+		return fmt.Sprintf("提案が承認されました！ターン%dの選択肢に追加される予定です。", placement.SurfaceTurn)
+	}
+}
+
+// publishPetitionSubmitted は陳情の審査完了イベントを配信する（陳情内容・生成された政策は含めない）
+func (uc *SubmitPetitionUseCase) publishPetitionSubmitted(ctx context.Context, input SubmitPetitionInput, room *entity.Room, approved bool, surfaceTurn int) {
+	event := entity.NewRoomEvent(entity.RoomEventPetitionSubmitted, input.RoomID, room)
+	event.PlayerID = input.PlayerID
+	event.Data["approved"] = approved
+	if approved {
+		event.Data["surfaceTurn"] = surfaceTurn
+	}
+	publishEvent(ctx, uc.publisher, event)
+}
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+func TestSubmitPetitionUseCase_Execute(t *testing.T) {
+	approved := &service.PetitionResult{
+		Approved: true,
+		Policy: &entity.MasterPolicy{
+			Title:       "図書館の24時間化",
+			Description: "いつでも学べる街に",
+			NewsFlash:   "【速報】図書館が眠らない街に",
+			Effects:     map[string]int{"education": 10, "economy": -5},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		started      bool
+		playerID     string
+		alreadyUsed  bool
+		result       *service.PetitionResult
+		reviewErr    error
+		blockReason  string
+		irrelevant   bool
+		wantErr      error
+		wantApproved bool
+		wantUsed     bool
+	}{
+		{
+			name:         "承認されると山札に追加される",
+			started:      true,
+			playerID:     "p1",
+			result:       approved,
+			wantApproved: true,
+			wantUsed:     true,
+		},
+		{
+			name:     "却下された陳情は消費しない",
+			started:  true,
+			playerID: "p1",
+			result:   &service.PetitionResult{Approved: false, Reason: "非現実的"},
+			wantUsed: false,
+		},
+		{
+			name:     "VOTING以外では陳情できない",
+			playerID: "p1",
+			wantErr:  entity.ErrInvalidPhase,
+		},
+		{
+			name:     "存在しないプレイヤー",
+			started:  true,
+			playerID: "stranger",
+			wantErr:  entity.ErrPlayerNotFound,
+		},
+		{
+			name:        "陳情は1人1回まで",
+			started:     true,
+			playerID:    "p1",
+			alreadyUsed: true,
+			wantErr:     entity.ErrPetitionUsed,
+			wantUsed:    true,
+		},
+		{
+			name:      "AI審査の失敗では陳情を消費しない",
+			started:   true,
+			playerID:  "p1",
+			reviewErr: errors.New("ai unavailable"),
+		},
+		{
+			name:        "モデレーションで拒否された陳情は消費しない",
+			started:     true,
+			playerID:    "p1",
+			blockReason: "不適切な表現が含まれています",
+			result:      approved,
+			wantErr:     entity.ErrPetitionBlocked,
+		},
+		{
+			name:       "陳情と無関係な政策は却下として返し陳情も消費しない",
+			started:    true,
+			playerID:   "p1",
+			irrelevant: true,
+			result:     approved,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.reviewer.result = tt.result
+			env.reviewer.err = tt.reviewErr
+			env.moderator.blockReason = tt.blockReason
+			env.moderator.relevant = !tt.irrelevant
+
+			var roomID string
+			if tt.started {
+				roomID = env.startedRoom(t, "p1")
+			} else {
+				roomID = env.createRoom(t)
+				env.join(t, roomID, "p1")
+			}
+			if tt.alreadyUsed {
+				player := env.player(t, roomID, tt.playerID)
+				player.IsPetitionUsed = true
+				if err := env.playerRepo.Update(context.Background(), roomID, tt.playerID, player); err != nil {
+					t.Fatalf("Update: %v", err)
+				}
+			}
+			deckBefore := len(env.room(t, roomID).DeckIDs)
+
+			out, err := env.submitPetitionUC().Execute(context.Background(), SubmitPetitionInput{RoomID: roomID, PlayerID: tt.playerID, PetitionText: "図書館を24時間開けてほしい"})
+			if tt.reviewErr != nil {
+				if err == nil {
+					t.Fatal("AI審査のエラーが返されていない")
+				}
+			} else {
+				assertErr(t, err, tt.wantErr)
+			}
+
+			if tt.blockReason != "" && env.reviewer.calls != 0 {
+				t.Error("モデレーションで拒否された陳情がAI審査に渡された")
+			}
+			if tt.playerID != "stranger" {
+				if got := env.player(t, roomID, tt.playerID).IsPetitionUsed; got != tt.wantUsed {
+					t.Errorf("isPetitionUsed = %v, want %v", got, tt.wantUsed)
+				}
+			}
+			if err != nil {
+				if len(env.room(t, roomID).DeckIDs) != deckBefore {
+					t.Error("エラーなのに山札が変わった")
+				}
+				return
+			}
+
+			if out.Approved != tt.wantApproved {
+				t.Errorf("Approved = %v, want %v", out.Approved, tt.wantApproved)
+			}
+			room := env.room(t, roomID)
+			if !tt.wantApproved {
+				if len(room.DeckIDs) != deckBefore {
+					t.Error("却下されたのに山札が変わった")
+				}
+				return
+			}
+			// デフォルトは次の手札に入れる
+			if len(room.DeckIDs) != deckBefore+1 || room.DeckIDs[0] != out.PolicyID {
+				t.Errorf("山札の先頭に %s が追加されていない", out.PolicyID)
+			}
+			if out.SurfaceTurn != room.Turn+1 {
+				t.Errorf("SurfaceTurn = %d, want %d", out.SurfaceTurn, room.Turn+1)
+			}
+			if room.GetGeneratedPolicy(out.PolicyID) == nil {
+				t.Error("生成された政策が部屋に保存されていない")
+			}
+			if !env.publisher.has(entity.RoomEventPetitionSubmitted) {
+				t.Error("PETITION_SUBMITTED が配信されていない")
+			}
+		})
+	}
+}
+
+func TestSubmitPetitionUseCase_RoomNotFound(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.submitPetitionUC().Execute(context.Background(), SubmitPetitionInput{RoomID: "missing", PlayerID: "host", PetitionText: "x"})
+	assertErr(t, err, entity.ErrRoomNotFound)
+}
+
+func TestSubmitPetitionUseCase_PhaseChangedDuringReview(t *testing.T) {
+	env := newTestEnv(t)
+	env.reviewer.result = &service.PetitionResult{
+		Approved: true,
+		Policy:   &entity.MasterPolicy{Title: "図書館の24時間化", Effects: map[string]int{"education": 10}},
+	}
+	roomID := env.startedRoom(t, "p1")
+	env.updateRoom(t, roomID, func(room *entity.Room) { room.Settings.PetitionPlacement = entity.PetitionPlacementReplaceCurrent })
+	before := env.room(t, roomID).CurrentPolicyIDs
+
+	// AI審査の間に投票が集計された
+	env.reviewer.onReview = func() {
+		env.updateRoom(t, roomID, func(room *entity.Room) { room.Status = entity.RoomStatusResult })
+	}
+
+	_, err := env.submitPetitionUC().Execute(context.Background(), SubmitPetitionInput{RoomID: roomID, PlayerID: "p1", PetitionText: "図書館を24時間開けてほしい"})
+	assertErr(t, err, entity.ErrInvalidPhase)
+
+	if p := env.player(t, roomID, "p1"); p.PetitionsUsed != 0 {
+		t.Errorf("PetitionsUsed = %d, want 0（陳情回数を消費しない）", p.PetitionsUsed)
+	}
+	room := env.room(t, roomID)
+	if !reflect.DeepEqual(room.CurrentPolicyIDs, before) || len(room.GeneratedPolicies) != 0 {
+		t.Errorf("集計済みのターンの選択肢が変わった: %v → %v, generated %d", before, room.CurrentPolicyIDs, len(room.GeneratedPolicies))
+	}
+}
+
+func TestSubmitPetitionUseCase_Placement(t *testing.T) {
+	approved := &service.PetitionResult{
+		Approved: true,
+		Policy:   &entity.MasterPolicy{Title: "図書館の24時間化", Effects: map[string]int{"education": 10}},
+	}
+
+	t.Run("次の手札に入り、次のターンで提示される", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.reviewer.result = approved
+		roomID := env.startedRoom(t, "p1")
+
+		out, err := env.submitPetitionUC().Execute(context.Background(), SubmitPetitionInput{RoomID: roomID, PlayerID: "p1", PetitionText: "図書館を24時間開けてほしい"})
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if out.SurfaceTurn != 2 {
+			t.Errorf("SurfaceTurn = %d, want 2", out.SurfaceTurn)
+		}
+
+		policyID := env.firstOption(t, roomID)
+		env.voteAll(t, roomID, map[string]string{"host": policyID, "p1": policyID})
+		if _, err := env.nextTurnUC().Execute(context.Background(), NextTurnInput{RoomID: roomID}); err != nil {
+			t.Fatalf("NextTurn: %v", err)
+		}
+		if room := env.room(t, roomID); !contains(room.CurrentPolicyIDs, out.PolicyID) {
+			t.Errorf("ターン2の選択肢 %v に %s が含まれていない", room.CurrentPolicyIDs, out.PolicyID)
+		}
+	})
+
+	t.Run("提示中の選択肢を入れ替え、その選択肢への投票を取り消す", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.reviewer.result = approved
+		roomID := env.createRoom(t)
+		env.join(t, roomID, "p1", "p2")
+		placement := entity.PetitionPlacementReplaceCurrent
+		if _, err := env.updateSettingsUC().Execute(context.Background(), UpdateRoomSettingsInput{
+			RoomID: roomID, UserID: "host", Settings: RoomSettingsInput{PetitionPlacement: &placement},
+		}); err != nil {
+			t.Fatalf("UpdateRoomSettings: %v", err)
+		}
+		if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+			t.Fatalf("StartGame: %v", err)
+		}
+
+		// 2票入った選択肢は残り、票のない選択肢（最後のもの）が入れ替わる
+		options := env.room(t, roomID).CurrentPolicyIDs
+		for _, userID := range []string{"host", "p1"} {
+			if _, err := env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: userID, PolicyID: options[0]}); err != nil {
+				t.Fatalf("Vote(%s): %v", userID, err)
+			}
+		}
+
+		out, err := env.submitPetitionUC().Execute(context.Background(), SubmitPetitionInput{RoomID: roomID, PlayerID: "p2", PetitionText: "図書館を24時間開けてほしい"})
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if out.SurfaceTurn != 1 {
+			t.Errorf("SurfaceTurn = %d, want 1", out.SurfaceTurn)
+		}
+		room := env.room(t, roomID)
+		want := []string{options[0], options[1], out.PolicyID}
+		for i := range want {
+			if room.CurrentPolicyIDs[i] != want[i] {
+				t.Fatalf("currentPolicyIds = %v, want %v", room.CurrentPolicyIDs, want)
+			}
+		}
+		if room.DeckIDs[0] != options[2] {
+			t.Errorf("入れ替えられた %s が山札の先頭に戻っていない", options[2])
+		}
+		if room.Votes["host"] != options[0] || env.player(t, roomID, "host").CurrentVote != options[0] {
+			t.Error("入れ替えられていない選択肢への投票が取り消された")
+		}
+		if !env.publisher.has(entity.RoomEventOptionsReplaced) {
+			t.Error("OPTIONS_REPLACED が配信されていない")
+		}
+	})
+}
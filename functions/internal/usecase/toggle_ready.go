@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
 )
 
 // ToggleReadyInput はReady状態トグルの入力
@@ -23,22 +25,26 @@ type ToggleReadyOutput struct {
 type ToggleReadyUseCase struct {
 	roomRepo   repository.RoomRepository
 	playerRepo repository.PlayerRepository
+	publisher  service.EventPublisher
 }
 
 // NewToggleReadyUseCase は ToggleReadyUseCase を作成する
 func NewToggleReadyUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
+	publisher service.EventPublisher,
 ) *ToggleReadyUseCase {
 	return &ToggleReadyUseCase{
 		roomRepo:   roomRepo,
 		playerRepo: playerRepo,
+		publisher:  publisher,
 	}
 }
 
 // Execute はReady状態をトグルする
 // 1. LOBBY状態であることを確認
 // 2. isReadyをトグル
+// 3. 部屋の lastActivityAt を更新
 func (uc *ToggleReadyUseCase) Execute(ctx context.Context, input ToggleReadyInput) (*ToggleReadyOutput, error) {
 	// 部屋を取得
 	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
@@ -70,6 +76,14 @@ func (uc *ToggleReadyUseCase) Execute(ctx context.Context, input ToggleReadyInpu
 	if err := uc.playerRepo.Update(ctx, input.RoomID, input.UserID, player); err != nil {
 		return nil, err
 	}
+	if err := uc.roomRepo.Touch(ctx, input.RoomID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	event := entity.NewRoomEvent(entity.RoomEventReadyToggled, input.RoomID, room)
+	event.PlayerID = input.UserID
+	event.Data["isReady"] = player.IsReady
+	publishEvent(ctx, uc.publisher, event)
 
 	return &ToggleReadyOutput{
 		IsReady: player.IsReady,
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestToggleReadyUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		started   bool
+		userID    string
+		wantErr   error
+		wantReady bool
+	}{
+		{
+			name:      "Readyを解除できる（参加時はReady）",
+			userID:    "p1",
+			wantReady: false,
+		},
+		{
+			name:    "部屋にいないプレイヤー",
+			userID:  "stranger",
+			wantErr: entity.ErrPlayerNotInRoom,
+		},
+		{
+			name:    "ゲーム開始後はトグルできない",
+			started: true,
+			userID:  "p1",
+			wantErr: entity.ErrInvalidPhase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			var roomID string
+			if tt.started {
+				roomID = env.startedRoom(t, "p1")
+			} else {
+				roomID = env.createRoom(t)
+				env.join(t, roomID, "p1")
+			}
+
+			out, err := env.toggleReadyUC().Execute(context.Background(), ToggleReadyInput{RoomID: roomID, UserID: tt.userID})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if out.IsReady != tt.wantReady {
+				t.Errorf("IsReady = %v, want %v", out.IsReady, tt.wantReady)
+			}
+			if env.player(t, roomID, tt.userID).IsReady != tt.wantReady {
+				t.Error("IsReady が保存されていない")
+			}
+		})
+	}
+}
+
+func TestToggleReadyUseCase_RoomNotFound(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.toggleReadyUC().Execute(context.Background(), ToggleReadyInput{RoomID: "missing", UserID: "host"})
+	assertErr(t, err, entity.ErrRoomNotFound)
+}
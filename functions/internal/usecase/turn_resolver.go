@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// cityImageRetryDelay は街の画像の生成・アップロードに失敗してからやり直すまでの時間
+const cityImageRetryDelay = 10 * time.Second
+
+// turnResolver は投票集計の共通処理
+// VoteUseCase（全員投票時の自動resolve）と ResolveVoteUseCase で共有する
+type turnResolver struct {
+	roomRepo       repository.RoomRepository
+	policyRepo     repository.PolicyRepository
+	imageGenerator service.ImageGenerator
+	imageStorage   service.ImageStorage
+	jobs           repository.JobQueue
+}
+
+// turnOutcome は集計の結果を表す
+type turnOutcome struct {
+	resolved   bool // 政策が可決され、結果を反映したか（false なら同数で決着待ち）
+	isGameOver bool
+}
+
+// resolve は投票を集計し、結果を部屋に反映する
+// トランザクション内で呼び出す前提で、部屋の保存は呼び出し元で行う
+// 1. 票を設定の集計方式（votingMethod）で集計して政策を決定（同数の場合は tieBreakRule に従う）
+// 2. HOST_DECIDES・REVOTE で同数なら pendingTie を設定して決着待ちにする（REVOTE では votes もリセット）
+// 3. 可決した政策を apply で反映する
+// REVOTE で全員の票がリセットされた場合、プレイヤーの currentVote は呼び出し元でリセットする
+func (r *turnResolver) resolve(ctx context.Context, room *entity.Room, players []*repository.PlayerWithID) (turnOutcome, error) {
+	// 投票集計
+	return r.resolveTally(ctx, room, players, room.TallyVotes())
+}
+
+// resolveTally は集計済みの結果から政策を決め、結果を部屋に反映する（手順は resolve と同じ）
+func (r *turnResolver) resolveTally(ctx context.Context, room *entity.Room, players []*repository.PlayerWithID, tally entity.VoteTally) (turnOutcome, error) {
+	winningPolicyID, err := r.decideWinner(ctx, room, tally)
+	if err != nil {
+		return turnOutcome{}, err
+	}
+	if winningPolicyID == "" && room.PendingTie != nil {
+		return turnOutcome{}, nil
+	}
+
+	isGameOver, err := r.apply(ctx, room, players, winningPolicyID, tally.Rounds)
+	if err != nil {
+		return turnOutcome{}, err
+	}
+	return turnOutcome{resolved: true, isGameOver: isGameOver}, nil
+}
+
+// decideWinner は集計結果から可決する政策を決める
+// 決着待ちにした場合は空文字を返す
+func (r *turnResolver) decideWinner(ctx context.Context, room *entity.Room, tally entity.VoteTally) (string, error) {
+	if len(tally.Winners) <= 1 {
+		return room.PickWinner(tally), nil
+	}
+
+	switch room.Settings.TieBreakRule {
+	case entity.TieBreakLowestMagnitude:
+		policies := make([]*entity.MasterPolicy, 0, len(tally.Winners))
+		for _, policyID := range tally.Winners {
+			policy, err := findPolicy(ctx, room, r.policyRepo, policyID)
+			if err != nil {
+				return "", err
+			}
+			if policy == nil {
+				return "", entity.ErrPolicyNotFound
+			}
+			policies = append(policies, policy)
+		}
+		return entity.LeastImpactful(policies), nil
+	case entity.TieBreakHostDecides, entity.TieBreakRevote:
+		// 再投票でも同数ならランダムに決める（何度も再投票にならないように）
+		// 決着待ちの間も制限時間があれば期限を設定し直す（ホストの選択・再投票の時間）
+		if !room.Revoting() {
+			room.StartTieBreak(tally)
+			room.ScheduleDeadline(time.Now())
+			return "", nil
+		}
+	}
+	return room.PickWinner(tally), nil
+}
+
+// apply は可決した政策を部屋に反映する
+// rounds はこの投票の集計（同数の決着待ちを経た場合は、同数になった投票の集計を前に付ける）
+// 1. 政策の effects を cityRules に従って cityParams に適用し、isCollapsed をチェック
+// 2. 可決されなかった政策を得点とともに記録し、再利用する設定なら捨て札に移す
+// 3. lastResult を設定し（全員の票・集計のラウンド・実際の変化量・崩壊の原因を含む）、status を RESULT に（resultTimeLimit があれば期限を設定）
+// 4. ゲーム終了判定: turn >= maxTurns or isCollapsed or 山札・捨て札が空 → FINISHED（最終結果を記録）
+func (r *turnResolver) apply(ctx context.Context, room *entity.Room, players []*repository.PlayerWithID, winningPolicyID string, rounds []entity.VoteRound) (bool, error) {
+	// 可決された政策を取得
+	winningPolicy, err := findPolicy(ctx, room, r.policyRepo, winningPolicyID)
+	if err != nil {
+		return false, err
+	}
+	if winningPolicy == nil {
+		return false, entity.ErrPolicyNotFound
+	}
+
+	// 政策の効果を街に適用
+	outcome := room.ApplyPolicyEffects(winningPolicy.Effects)
+
+	// 可決された政策を履歴に追加
+	room.PassedPolicyIDs = append(room.PassedPolicyIDs, winningPolicy.PolicyID)
+
+	// 可決されなかった政策を墓場に記録し、再利用する設定なら捨て札へ（山札が足りなくなったら戻す）
+	room.RejectUnpassed(winningPolicy.PolicyID)
+
+	// 同数の決着待ちを経た場合は、同数になった投票の集計も残す
+	if room.PendingTie != nil {
+		rounds = append(append([]entity.VoteRound(nil), room.PendingTie.Rounds...), rounds...)
+		room.PendingTie = nil
+	}
+
+	// 投票結果を設定
+	room.LastResult = &entity.VoteResult{
+		PassedPolicyID:    winningPolicy.PolicyID,
+		PassedPolicyTitle: winningPolicy.Title,
+		ActualEffects:     outcome.Applied,
+		NewsFlash:         winningPolicy.NewsFlash,
+		VotingMethod:      room.Settings.VotingMethod,
+		VoteDetails:       room.CurrentBallots(),
+		Rounds:            rounds,
+		CollapseCause:     outcome.Collapse,
+	}
+
+	// 結果発表フェーズに移行
+	room.Status = entity.RoomStatusResult
+	room.ScheduleDeadline(time.Now())
+
+	// ゲーム終了判定
+	isGameOver := room.IsGameOver()
+	if isGameOver {
+		finishGame(room, players)
+	}
+
+	return isGameOver, nil
+}
+
+// clearPlayerVotes は再投票のため全プレイヤーの currentVote をリセットする
+// 読み取り済みのプレイヤーを1人ずつ更新する（トランザクション内で書き込み後に読み取らないため）
+func clearPlayerVotes(ctx context.Context, playerRepo repository.PlayerRepository, roomID string, players []*repository.PlayerWithID) error {
+	for _, p := range players {
+		p.Player.ClearVote()
+		if err := playerRepo.Update(ctx, roomID, p.UserID, p.Player); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachCityImage は街の画像を生成・アップロードし、lastResult に反映する
+// 外部APIを呼び出すため、トランザクションの外（集計結果のコミット後）で呼び出す
+// 画像の生成・保存に失敗してもゲームの進行は止めない（保存先があればジョブで後からやり直す）
+func (r *turnResolver) attachCityImage(ctx context.Context, roomID string, room *entity.Room) {
+	if r.imageGenerator == nil {
+		return
+	}
+	if err := r.generateCityImage(ctx, roomID, room); err != nil {
+		slog.Warn("failed to attach city image", slog.String("roomId", roomID), slog.Any("error", err))
+		if r.imageStorage != nil {
+			enqueueJob(ctx, r.jobs, entity.NewCityImageJob(roomID, room.Turn, time.Now().Add(cityImageRetryDelay)))
+		}
+	}
+}
+
+// generateCityImage は街の画像を生成して lastResult.cityImage に入れ、
+// 保存先があればアップロードして lastResult.cityImageUrl を保存する
+func (r *turnResolver) generateCityImage(ctx context.Context, roomID string, room *entity.Room) error {
+	passedPolicies, err := getPassedPolicies(ctx, room, r.policyRepo)
+	if err != nil {
+		return fmt.Errorf("get passed policies: %w", err)
+	}
+
+	imageResult, err := r.imageGenerator.GenerateCityImage(ctx, &room.CityParams, passedPolicies)
+	if err != nil {
+		return fmt.Errorf("generate city image: %w", err)
+	}
+	room.LastResult.CityImage = imageResult.Image
+
+	// GCSにアップロードしてsigned URLを取得
+	if r.imageStorage == nil {
+		return nil
+	}
+	imageData, err := base64.StdEncoding.DecodeString(imageResult.Image)
+	if err != nil {
+		return fmt.Errorf("decode base64 image: %w", err)
+	}
+	signedURL, err := r.imageStorage.UploadCityImage(ctx, roomID, room.Turn, imageData)
+	if err != nil {
+		return fmt.Errorf("upload city image: %w", err)
+	}
+	room.LastResult.CityImageURL = signedURL
+	slog.Info("city image uploaded to GCS", slog.String("url", signedURL))
+
+	// lastResult.cityImageUrl のみ更新（集計後に進んだ状態を上書きしない）
+	if err := r.roomRepo.UpdateCityImageURL(ctx, roomID, signedURL); err != nil {
+		return fmt.Errorf("save city image URL: %w", err)
+	}
+	return nil
+}
+
+// getPassedPolicies は可決された政策のリストを取得する
+func getPassedPolicies(ctx context.Context, room *entity.Room, policyRepo repository.PolicyRepository) ([]*entity.MasterPolicy, error) {
+	policies := make([]*entity.MasterPolicy, 0, len(room.PassedPolicyIDs))
+	for _, policyID := range room.PassedPolicyIDs {
+		policy, err := findPolicy(ctx, room, policyRepo, policyID)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
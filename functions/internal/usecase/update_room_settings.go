@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/repository"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+// RoomSettingsInput は部屋設定の入力（nil の項目は変更しない）
+type RoomSettingsInput struct {
+	MaxTurns           *int
+	MaxPlayers         *int
+	OptionsPerTurn     *int
+	InitialCityParams  *entity.CityParams
+	CityRules          entity.CityRules // 指定した分野のルールだけを上書きする
+	PetitionsPerPlayer *int
+	VotingMethod       *entity.VotingMethod
+	TieBreakRule       *entity.TieBreakRule
+	RequireLockIn      *bool
+	VotingTimeLimit    *int
+	ResultTimeLimit    *int
+	TimeoutVote        *entity.TimeoutVote
+	RecycleRejected    *bool
+	PetitionPlacement  *entity.PetitionPlacement
+	PetitionWindow     *int
+}
+
+// applyTo は指定された項目だけを base に上書きした設定を返す
+func (in RoomSettingsInput) applyTo(base entity.RoomSettings) entity.RoomSettings {
+	if in.MaxTurns != nil {
+		base.MaxTurns = *in.MaxTurns
+	}
+	if in.MaxPlayers != nil {
+		base.MaxPlayers = *in.MaxPlayers
+	}
+	if in.OptionsPerTurn != nil {
+		base.OptionsPerTurn = *in.OptionsPerTurn
+	}
+	if in.InitialCityParams != nil {
+		base.InitialCityParams = *in.InitialCityParams
+	}
+	if len(in.CityRules) > 0 {
+		rules := make(entity.CityRules, len(base.CityRules)+len(in.CityRules))
+		for key, rule := range base.CityRules {
+			rules[key] = rule
+		}
+		for key, rule := range in.CityRules {
+			rules[key] = rule
+		}
+		base.CityRules = rules
+	}
+	if in.PetitionsPerPlayer != nil {
+		base.PetitionsPerPlayer = *in.PetitionsPerPlayer
+	}
+	if in.VotingMethod != nil {
+		base.VotingMethod = *in.VotingMethod
+	}
+	if in.TieBreakRule != nil {
+		base.TieBreakRule = *in.TieBreakRule
+	}
+	if in.RequireLockIn != nil {
+		base.RequireLockIn = *in.RequireLockIn
+	}
+	if in.VotingTimeLimit != nil {
+		base.VotingTimeLimit = *in.VotingTimeLimit
+	}
+	if in.ResultTimeLimit != nil {
+		base.ResultTimeLimit = *in.ResultTimeLimit
+	}
+	if in.TimeoutVote != nil {
+		base.TimeoutVote = *in.TimeoutVote
+	}
+	if in.RecycleRejected != nil {
+		base.RecycleRejected = *in.RecycleRejected
+	}
+	if in.PetitionPlacement != nil {
+		base.PetitionPlacement = *in.PetitionPlacement
+	}
+	if in.PetitionWindow != nil {
+		base.PetitionWindow = *in.PetitionWindow
+	}
+	return base
+}
+
+// UpdateRoomSettingsInput は部屋設定変更の入力
+type UpdateRoomSettingsInput struct {
+	RoomID   string
+	UserID   string // ホストチェック用
+	Settings RoomSettingsInput
+}
+
+// UpdateRoomSettingsOutput は部屋設定変更の出力
+type UpdateRoomSettingsOutput struct {
+	Settings entity.RoomSettings
+}
+
+// UpdateRoomSettingsUseCase は部屋設定変更のユースケース
+// POST /api/rooms/{roomId}/settings
+type UpdateRoomSettingsUseCase struct {
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	transactor repository.Transactor
+	publisher  service.EventPublisher
+}
+
+// NewUpdateRoomSettingsUseCase は UpdateRoomSettingsUseCase を作成する
+func NewUpdateRoomSettingsUseCase(
+	roomRepo repository.RoomRepository,
+	playerRepo repository.PlayerRepository,
+	transactor repository.Transactor,
+	publisher service.EventPublisher,
+) *UpdateRoomSettingsUseCase {
+	return &UpdateRoomSettingsUseCase{
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+		transactor: transactor,
+		publisher:  publisher,
+	}
+}
+
+// Execute は部屋の設定を変更する
+// 1. ホストであることを確認
+// 2. LOBBY状態であることを確認
+// 3. 指定された項目を現在の設定に上書きして検証
+// 4. 最大人数が現在の参加人数を下回らないことを確認
+// 5. 設定を保存（街パラメータは新しい初期値に置き換える）
+// 1〜5 は1つのトランザクションで行う（設定変更と同時の参加・開始を取りこぼさない）
+func (uc *UpdateRoomSettingsUseCase) Execute(ctx context.Context, input UpdateRoomSettingsInput) (*UpdateRoomSettingsOutput, error) {
+	var room *entity.Room
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		// 部屋を取得
+		var err error
+		room, err = uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
+
+		// ホストチェック
+		if room.HostID != input.UserID {
+			return entity.ErrNotHost
+		}
+
+		// LOBBY状態でないと変更できない
+		if room.Status != entity.RoomStatusLobby {
+			return entity.ErrInvalidPhase
+		}
+
+		settings := input.Settings.applyTo(room.Settings)
+
+		// 既に参加しているプレイヤーを追い出す設定にはできない
+		players, err := uc.playerRepo.FindAllByRoomID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if settings.MaxPlayers < len(players) {
+			return fmt.Errorf("%w: maxPlayers must be at least the current number of players (%d)", entity.ErrInvalidSettings, len(players))
+		}
+
+		if err := room.UpdateSettings(settings); err != nil {
+			return err
+		}
+
+		// 部屋を更新
+		room.Touch(time.Now())
+		return uc.roomRepo.Update(ctx, input.RoomID, room)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := entity.NewRoomEvent(entity.RoomEventSettingsUpdated, input.RoomID, room)
+	event.PlayerID = input.UserID
+	event.Data["settings"] = room.Settings
+	publishEvent(ctx, uc.publisher, event)
+
+	return &UpdateRoomSettingsOutput{
+		Settings: room.Settings,
+	}, nil
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+	"github.com/techworld-hackathon/functions/internal/domain/service"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestUpdateRoomSettingsUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, env *testEnv) string
+		userID   string
+		settings RoomSettingsInput
+		wantErr  error
+	}{
+		{
+			name:     "ホストがLOBBYで変更できる",
+			setup:    func(t *testing.T, env *testEnv) string { return env.createRoom(t) },
+			userID:   "host",
+			settings: RoomSettingsInput{MaxTurns: intPtr(5), OptionsPerTurn: intPtr(4)},
+		},
+		{
+			name:     "存在しない部屋",
+			setup:    func(t *testing.T, env *testEnv) string { return "missing" },
+			userID:   "host",
+			settings: RoomSettingsInput{MaxTurns: intPtr(5)},
+			wantErr:  entity.ErrRoomNotFound,
+		},
+		{
+			name: "ホスト以外は変更できない",
+			setup: func(t *testing.T, env *testEnv) string {
+				roomID := env.createRoom(t)
+				env.join(t, roomID, "p1")
+				return roomID
+			},
+			userID:   "p1",
+			settings: RoomSettingsInput{MaxTurns: intPtr(5)},
+			wantErr:  entity.ErrNotHost,
+		},
+		{
+			name:     "ゲーム開始後は変更できない",
+			setup:    func(t *testing.T, env *testEnv) string { return env.startedRoom(t, "p1") },
+			userID:   "host",
+			settings: RoomSettingsInput{MaxTurns: intPtr(5)},
+			wantErr:  entity.ErrInvalidPhase,
+		},
+		{
+			name:     "範囲外の値",
+			setup:    func(t *testing.T, env *testEnv) string { return env.createRoom(t) },
+			userID:   "host",
+			settings: RoomSettingsInput{OptionsPerTurn: intPtr(10)},
+			wantErr:  entity.ErrInvalidSettings,
+		},
+		{
+			name: "最大人数を参加人数より少なくできない",
+			setup: func(t *testing.T, env *testEnv) string {
+				roomID := env.createRoom(t)
+				env.join(t, roomID, "p1", "p2")
+				return roomID
+			},
+			userID:   "host",
+			settings: RoomSettingsInput{MaxPlayers: intPtr(2)},
+			wantErr:  entity.ErrInvalidSettings,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			roomID := tt.setup(t, env)
+
+			out, err := env.updateSettingsUC().Execute(context.Background(), UpdateRoomSettingsInput{RoomID: roomID, UserID: tt.userID, Settings: tt.settings})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			room := env.room(t, roomID)
+			if !reflect.DeepEqual(room.Settings, out.Settings) {
+				t.Errorf("保存された設定 = %+v, want %+v", room.Settings, out.Settings)
+			}
+			if room.Settings.MaxTurns != 5 || room.MaxTurns != 5 || room.Settings.OptionsPerTurn != 4 {
+				t.Errorf("settings = %+v, maxTurns = %d", room.Settings, room.MaxTurns)
+			}
+			// 指定しなかった項目は変わらない
+			if room.Settings.MaxPlayers != entity.DefaultRoomSettings().MaxPlayers {
+				t.Errorf("MaxPlayers = %d, want 変更されない", room.Settings.MaxPlayers)
+			}
+			if !env.publisher.has(entity.RoomEventSettingsUpdated) {
+				t.Error("SETTINGS_UPDATED が配信されていない")
+			}
+		})
+	}
+}
+
+// TestRoomSettings_RespectedByUseCases は部屋の設定が各ユースケースに反映されることを確認する
+func TestRoomSettings_RespectedByUseCases(t *testing.T) {
+	env := newTestEnv(t)
+	initial := entity.CityParams{Economy: 60, Welfare: 60, Education: 60, Environment: 60, Security: 60, HumanRights: 60}
+
+	created, err := env.createRoomUC().Execute(context.Background(), CreateRoomInput{
+		UserID:      "host",
+		DisplayName: "ホスト",
+		Settings: RoomSettingsInput{
+			MaxTurns:           intPtr(2),
+			MaxPlayers:         intPtr(2),
+			OptionsPerTurn:     intPtr(4),
+			InitialCityParams:  &initial,
+			PetitionsPerPlayer: intPtr(2),
+		},
+	})
+	assertErr(t, err, nil)
+	roomID := created.RoomID
+
+	room := env.room(t, roomID)
+	if room.CityParams != initial || room.MaxTurns != 2 {
+		t.Fatalf("作成時の設定が反映されていない: cityParams = %+v, maxTurns = %d", room.CityParams, room.MaxTurns)
+	}
+
+	// 最大人数
+	env.join(t, roomID, "p1")
+	_, err = env.joinRoomUC().Execute(context.Background(), JoinRoomInput{RoomID: roomID, UserID: "p2", DisplayName: "p2"})
+	assertErr(t, err, entity.ErrRoomFull)
+
+	// 1ターンの選択肢の数
+	if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+	if got := len(env.room(t, roomID).CurrentPolicyIDs); got != 4 {
+		t.Errorf("開始時の選択肢 = %d枚, want 4", got)
+	}
+
+	// 陳情回数
+	env.reviewer.result = &service.PetitionResult{
+		Approved: true,
+		Policy:   &entity.MasterPolicy{Title: "図書館の新設", Effects: map[string]int{"education": 10}},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := env.submitPetitionUC().Execute(context.Background(), SubmitPetitionInput{RoomID: roomID, PlayerID: "p1", PetitionText: "図書館を建てたい"}); err != nil {
+			t.Fatalf("%d回目の陳情: %v", i+1, err)
+		}
+	}
+	_, err = env.submitPetitionUC().Execute(context.Background(), SubmitPetitionInput{RoomID: roomID, PlayerID: "p1", PetitionText: "図書館を建てたい"})
+	assertErr(t, err, entity.ErrPetitionUsed)
+
+	// 次のターンの選択肢の数・ターン数
+	env.voteAll(t, roomID, map[string]string{"host": env.firstOption(t, roomID), "p1": env.firstOption(t, roomID)})
+	if _, err := env.nextTurnUC().Execute(context.Background(), NextTurnInput{RoomID: roomID}); err != nil {
+		t.Fatalf("NextTurn: %v", err)
+	}
+	if got := len(env.room(t, roomID).CurrentPolicyIDs); got != 4 {
+		t.Errorf("2ターン目の選択肢 = %d枚, want 4", got)
+	}
+	out := env.voteAll(t, roomID, map[string]string{"host": env.firstOption(t, roomID), "p1": env.firstOption(t, roomID)})
+	if !out.IsGameOver {
+		t.Error("maxTurns = 2 なのに2ターン目で終了しない")
+	}
+}
+
+// firstOption は提示中の最初の政策IDを返す
+func (e *testEnv) firstOption(t *testing.T, roomID string) string {
+	t.Helper()
+	return e.room(t, roomID).CurrentPolicyIDs[0]
+}
+
+func TestUpdateRoomSettingsUseCase_MergesCityRules(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := env.createRoom(t)
+	clamp := entity.ParamRule{Floor: 0, Ceiling: 100, FloorMode: entity.BoundModeClamp, CeilingMode: entity.BoundModeClamp}
+
+	_, err := env.updateSettingsUC().Execute(context.Background(), UpdateRoomSettingsInput{
+		RoomID:   roomID,
+		UserID:   "host",
+		Settings: RoomSettingsInput{CityRules: entity.CityRules{"economy": clamp}},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	rules := env.room(t, roomID).Settings.CityRules
+	if rules.For("economy") != clamp {
+		t.Errorf("economy = %+v, want %+v", rules.For("economy"), clamp)
+	}
+	// 指定しなかった分野は変わらない
+	if len(rules) != len(entity.PolicyEffectKeys) || rules.For("welfare") != entity.DefaultParamRule() {
+		t.Errorf("cityRules = %+v, want economy 以外はデフォルト", rules)
+	}
+}
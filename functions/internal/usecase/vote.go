@@ -2,8 +2,7 @@ package usecase
 
 import (
 	"context"
-	"encoding/base64"
-	"log/slog"
+	"time"
 
 	"github.com/techworld-hackathon/functions/internal/domain/entity"
 	"github.com/techworld-hackathon/functions/internal/domain/repository"
@@ -14,26 +13,44 @@ import (
 type VoteInput struct {
 	RoomID   string
 	UserID   string
-	PolicyID string
+	PolicyID string   // 1つだけ選ぶ場合（Ballot が空のとき使う）
+	Ballot   []string // 複数選ぶ場合（APPROVAL は賛成する政策、INSTANT_RUNOFF・BORDA は希望順）
+	Change   bool     // 投票済みの票を変更する（PUT。未投票ならエラー）
+	LockIn   bool     // 投票を確定する（票を省略した場合は投票済みの票を確定する）
+}
+
+// ballot は入力された票を返す（Ballot が空なら PolicyID だけの票）
+func (in VoteInput) ballot() []string {
+	if len(in.Ballot) > 0 {
+		return in.Ballot
+	}
+	if in.PolicyID == "" {
+		return nil
+	}
+	return []string{in.PolicyID}
 }
 
 // VoteOutput は投票の出力
 type VoteOutput struct {
 	Success    bool
-	AllVoted   bool         // 全員投票済みか
-	IsResolved bool         // 自動でresolveされたか
-	Room       *entity.Room // resolve後の部屋情報（resolveされた場合のみ）
-	IsGameOver bool         // ゲーム終了か
+	AllVoted   bool               // 全員投票済みか
+	Undecided  int                // まだ決めていないプレイヤーの数（未投票と、requireLockIn の場合は未確定）
+	IsResolved bool               // 自動でresolveされたか
+	Room       *entity.Room       // resolve後の部屋情報（resolveされた場合のみ）
+	IsGameOver bool               // ゲーム終了か
+	PendingTie *entity.PendingTie // 同数で決着待ちになった場合（HOST_DECIDES・REVOTE）
 }
 
 // VoteUseCase は投票のユースケース
-// POST /api/rooms/{roomId}/vote
+// POST /api/rooms/{roomId}/vote（投票）
+// PUT /api/rooms/{roomId}/vote（投票の変更）
+// POST /api/rooms/{roomId}/lockin（投票の確定）
 type VoteUseCase struct {
-	roomRepo       repository.RoomRepository
-	playerRepo     repository.PlayerRepository
-	policyRepo     repository.PolicyRepository
-	imageGenerator service.ImageGenerator
-	imageStorage   service.ImageStorage
+	roomRepo   repository.RoomRepository
+	playerRepo repository.PlayerRepository
+	transactor repository.Transactor
+	resolver   *turnResolver
+	publisher  service.EventPublisher
 }
 
 // NewVoteUseCase は VoteUseCase を作成する
@@ -41,185 +58,169 @@ func NewVoteUseCase(
 	roomRepo repository.RoomRepository,
 	playerRepo repository.PlayerRepository,
 	policyRepo repository.PolicyRepository,
+	transactor repository.Transactor,
 	imageGenerator service.ImageGenerator,
 	imageStorage service.ImageStorage,
+	jobs repository.JobQueue,
+	publisher service.EventPublisher,
 ) *VoteUseCase {
 	return &VoteUseCase{
-		roomRepo:       roomRepo,
-		playerRepo:     playerRepo,
-		policyRepo:     policyRepo,
-		imageGenerator: imageGenerator,
-		imageStorage:   imageStorage,
+		roomRepo:   roomRepo,
+		playerRepo: playerRepo,
+		transactor: transactor,
+		resolver: &turnResolver{
+			roomRepo:       roomRepo,
+			policyRepo:     policyRepo,
+			imageGenerator: imageGenerator,
+			imageStorage:   imageStorage,
+			jobs:           jobs,
+		},
+		publisher: publisher,
 	}
 }
 
 // Execute は投票を行う
-// 1. VOTING状態であることを確認
-// 2. 有効な政策IDであることを確認（currentPolicyIdsに含まれる）
-// 3. プレイヤーのcurrentVoteを更新
-// 4. Roomのvotesを更新
-// 5. 全員投票済みなら自動でresolveを実行
+// 1. VOTING状態であること（ホストが同数の政策から選ぶのを待っていないこと）を確認
+// 2. 投票を確定していないこと、変更・票を省略した確定の場合は投票済みであることを確認
+// 3. 有効な票であることを確認（currentPolicyIdsに含まれ、集計方式に合った形か）
+// 4. プレイヤーのcurrentVote・currentBallotを更新
+// 5. Roomのvotes・ballots・lockedInを更新
+// 6. 全員が決めたら（全員投票済み、requireLockIn の場合は全員確定済み）自動でresolveを実行
+// （同数で再投票になった場合は全員の投票をリセット）
+// 1〜6 は1つのトランザクションで行う（同時投票での上書き・二重resolveを防ぐ）
+// 7. コミット後に街の画像を生成
 func (uc *VoteUseCase) Execute(ctx context.Context, input VoteInput) (*VoteOutput, error) {
-	// 部屋を取得
-	room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
-	if err != nil {
-		return nil, err
-	}
-	if room == nil {
-		return nil, entity.ErrRoomNotFound
-	}
-
-	// VOTING状態でないと投票できない
-	if room.Status != entity.RoomStatusVoting {
-		return nil, entity.ErrInvalidPhase
-	}
+	var output *VoteOutput
+	var turn int
+	err := uc.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		// 部屋を取得
+		room, err := uc.roomRepo.FindByID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return entity.ErrRoomNotFound
+		}
 
-	// プレイヤーを取得
-	player, err := uc.playerRepo.FindByID(ctx, input.RoomID, input.UserID)
-	if err != nil {
-		return nil, err
-	}
-	if player == nil {
-		return nil, entity.ErrPlayerNotInRoom
-	}
+		// VOTING状態でないと投票できない（ホストが同数の政策から選ぶ間も投票できない）
+		if room.Status != entity.RoomStatusVoting || room.AwaitingHost() {
+			return entity.ErrInvalidPhase
+		}
+		turn = room.Turn
 
-	// 有効な政策IDかチェック
-	validPolicy := false
-	for _, policyID := range room.CurrentPolicyIDs {
-		if policyID == input.PolicyID {
-			validPolicy = true
-			break
+		// プレイヤーを取得
+		player, err := uc.playerRepo.FindByID(ctx, input.RoomID, input.UserID)
+		if err != nil {
+			return err
+		}
+		if player == nil {
+			return entity.ErrPlayerNotInRoom
 		}
-	}
-	if !validPolicy {
-		return nil, entity.ErrInvalidPolicy
-	}
 
-	// プレイヤーの投票を更新
-	player.CurrentVote = input.PolicyID
-	if err := uc.playerRepo.Update(ctx, input.RoomID, input.UserID, player); err != nil {
-		return nil, err
-	}
+		// 確定した投票は変更できない
+		if room.IsLockedIn(input.UserID) {
+			return entity.ErrVoteLockedIn
+		}
 
-	// Roomのvotesを更新
-	room.Votes[input.UserID] = input.PolicyID
-	if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
-		return nil, err
-	}
+		// 有効な票かチェック（確定だけの場合は投票済みの票を使う）
+		ballot := input.ballot()
+		if (input.Change || (input.LockIn && len(ballot) == 0)) && !room.HasVoted(input.UserID) {
+			return entity.ErrNotVoted
+		}
+		if len(ballot) == 0 && input.LockIn {
+			ballot = room.CurrentBallots()[input.UserID]
+		}
+		if err := room.ValidateBallot(ballot); err != nil {
+			return err
+		}
 
-	// 全プレイヤー数を取得
-	players, err := uc.playerRepo.FindAllByRoomID(ctx, input.RoomID)
-	if err != nil {
-		return nil, err
-	}
+		// 全プレイヤーを取得（書き込み前に読み取りを済ませる）
+		players, err := uc.playerRepo.FindAllWithIDsByRoomID(ctx, input.RoomID)
+		if err != nil {
+			return err
+		}
 
-	// 全員投票済みかチェック
-	allVoted := room.AllPlayersVoted(len(players))
-	if !allVoted {
-		return &VoteOutput{
-			Success:  true,
-			AllVoted: false,
-		}, nil
-	}
+		// 全員が決めたかチェック
+		room.Touch(time.Now())
+		room.CastBallot(input.UserID, ballot)
+		if input.LockIn {
+			room.LockIn(input.UserID)
+		}
+		if !room.ReadyToResolve(len(players)) {
+			// プレイヤーの投票とRoomのvotes・ballots・lockedInを更新（自分の投票のみ）
+			if err := uc.playerRepo.UpdateCurrentVote(ctx, input.RoomID, input.UserID, ballot); err != nil {
+				return err
+			}
+			if err := uc.roomRepo.UpdateVote(ctx, input.RoomID, input.UserID, ballot, input.LockIn, room.LastActivityAt); err != nil {
+				return err
+			}
+			output = &VoteOutput{
+				Success:   true,
+				AllVoted:  room.AllPlayersVoted(len(players)),
+				Undecided: room.Undecided(len(players)),
+			}
+			return nil
+		}
 
-	// 全員投票済みなら自動でresolveを実行
-	return uc.resolveVote(ctx, input.RoomID, room, players)
-}
+		// 全員投票済みなら自動でresolveを実行
+		outcome, err := uc.resolver.resolve(ctx, room, players)
+		if err != nil {
+			return err
+		}
 
-// resolveVote は投票を集計し、結果を反映する（内部メソッド）
-func (uc *VoteUseCase) resolveVote(ctx context.Context, roomID string, room *entity.Room, players []*entity.Player) (*VoteOutput, error) {
-	// 投票集計
-	winningPolicyID := room.CountVotes()
+		// プレイヤーの投票を更新（再投票になった場合は全員の投票をリセットする。同じプレイヤーを2回書き込まない）
+		if room.Revoting() && !outcome.resolved {
+			err = clearPlayerVotes(ctx, uc.playerRepo, input.RoomID, players)
+		} else {
+			err = uc.playerRepo.UpdateCurrentVote(ctx, input.RoomID, input.UserID, ballot)
+		}
+		if err != nil {
+			return err
+		}
 
-	// 可決された政策を取得
-	winningPolicy, err := findPolicy(ctx, room, uc.policyRepo, winningPolicyID)
+		if err := uc.roomRepo.Update(ctx, input.RoomID, room); err != nil {
+			return err
+		}
+		output = &VoteOutput{
+			Success:    true,
+			AllVoted:   true,
+			IsResolved: outcome.resolved,
+			Room:       room,
+			IsGameOver: outcome.isGameOver,
+			PendingTie: room.PendingTie,
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	if winningPolicy == nil {
-		return nil, entity.ErrPolicyNotFound
-	}
 
-	// 政策の効果を街に適用
-	room.ApplyPolicyEffects(winningPolicy.Effects)
-
-	// 可決された政策を履歴に追加
-	room.PassedPolicyIDs = append(room.PassedPolicyIDs, winningPolicy.PolicyID)
-
-	// 投票結果を設定
-	room.LastResult = &entity.VoteResult{
-		PassedPolicyID:    winningPolicy.PolicyID,
-		PassedPolicyTitle: winningPolicy.Title,
-		ActualEffects:     winningPolicy.Effects,
-		NewsFlash:         winningPolicy.NewsFlash,
-		VoteDetails:       room.Votes,
-	}
-
-	// 街の画像を生成
-	if uc.imageGenerator != nil {
-		passedPolicies, err := uc.getPassedPolicies(ctx, room)
-		if err != nil {
-			slog.Warn("failed to get passed policies for image generation", slog.Any("error", err))
-		} else {
-			imageResult, err := uc.imageGenerator.GenerateCityImage(ctx, &room.CityParams, passedPolicies)
-			if err != nil {
-				slog.Warn("failed to generate city image", slog.Any("error", err))
-			} else {
-				room.LastResult.CityImage = imageResult.Image
-
-				// GCSにアップロードしてsigned URLを取得
-				if uc.imageStorage != nil {
-					imageData, err := base64.StdEncoding.DecodeString(imageResult.Image)
-					if err != nil {
-						slog.Warn("failed to decode base64 image", slog.Any("error", err))
-					} else {
-						signedURL, err := uc.imageStorage.UploadCityImage(ctx, roomID, room.Turn, imageData)
-						if err != nil {
-							slog.Warn("failed to upload city image to GCS", slog.Any("error", err))
-						} else {
-							room.LastResult.CityImageURL = signedURL
-							slog.Info("city image uploaded to GCS", slog.String("url", signedURL))
-						}
-					}
-				}
-			}
-		}
+	event := entity.NewRoomEvent(entity.RoomEventVoteCast, input.RoomID, nil)
+	event.PlayerID = input.UserID
+	event.Turn = turn
+	event.Data["changed"] = input.Change
+	event.Data["undecided"] = output.Undecided
+	publishEvent(ctx, uc.publisher, event)
+	if input.LockIn {
+		lockedIn := entity.NewRoomEvent(entity.RoomEventVoteLockedIn, input.RoomID, nil)
+		lockedIn.PlayerID = input.UserID
+		lockedIn.Turn = turn
+		lockedIn.Data["undecided"] = output.Undecided
+		publishEvent(ctx, uc.publisher, lockedIn)
 	}
 
-	// 結果発表フェーズに移行
-	room.Status = entity.RoomStatusResult
-
-	// ゲーム終了判定
-	isGameOver := room.IsGameOver()
-	if isGameOver {
-		room.Finish()
+	// 集計した場合（決着待ちを含む）は次の期限に期限切れの処理を予約
+	if output.Room != nil {
+		scheduleDeadline(ctx, uc.resolver.jobs, input.RoomID, output.Room)
 	}
 
-	// 部屋を更新
-	if err := uc.roomRepo.Update(ctx, roomID, room); err != nil {
-		return nil, err
+	if output.IsResolved {
+		// 街の画像を生成（外部API呼び出しのためコミット後に行う）
+		uc.resolver.attachCityImage(ctx, input.RoomID, output.Room)
+		publishTurnResolved(ctx, uc.publisher, input.RoomID, output.Room, output.IsGameOver)
+	} else if output.PendingTie != nil {
+		publishTiePending(ctx, uc.publisher, input.RoomID, output.Room)
 	}
 
-	return &VoteOutput{
-		Success:    true,
-		AllVoted:   true,
-		IsResolved: true,
-		Room:       room,
-		IsGameOver: isGameOver,
-	}, nil
-}
-
-// getPassedPolicies は可決された政策のリストを取得する
-func (uc *VoteUseCase) getPassedPolicies(ctx context.Context, room *entity.Room) ([]*entity.MasterPolicy, error) {
-	policies := make([]*entity.MasterPolicy, 0, len(room.PassedPolicyIDs))
-	for _, policyID := range room.PassedPolicyIDs {
-		policy, err := findPolicy(ctx, room, uc.policyRepo, policyID)
-		if err != nil {
-			return nil, err
-		}
-		if policy != nil {
-			policies = append(policies, policy)
-		}
-	}
-	return policies, nil
+	return output, nil
 }
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/techworld-hackathon/functions/internal/domain/entity"
+)
+
+func TestVoteUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name     string
+		started  bool
+		userID   string
+		policyID func(room *entity.Room) string
+		wantErr  error
+	}{
+		{
+			name:     "選択肢に投票できる",
+			started:  true,
+			userID:   "host",
+			policyID: func(room *entity.Room) string { return room.CurrentPolicyIDs[0] },
+		},
+		{
+			name:     "VOTING以外では投票できない",
+			userID:   "host",
+			policyID: func(room *entity.Room) string { return "policy_001" },
+			wantErr:  entity.ErrInvalidPhase,
+		},
+		{
+			name:     "部屋にいないプレイヤー",
+			started:  true,
+			userID:   "stranger",
+			policyID: func(room *entity.Room) string { return room.CurrentPolicyIDs[0] },
+			wantErr:  entity.ErrPlayerNotInRoom,
+		},
+		{
+			name:     "選択肢にない政策",
+			started:  true,
+			userID:   "host",
+			policyID: func(room *entity.Room) string { return room.DeckIDs[0] },
+			wantErr:  entity.ErrInvalidPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			var roomID string
+			if tt.started {
+				roomID = env.startedRoom(t, "p1")
+			} else {
+				roomID = env.createRoom(t)
+				env.join(t, roomID, "p1")
+			}
+			policyID := tt.policyID(env.room(t, roomID))
+
+			out, err := env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: tt.userID, PolicyID: policyID})
+			assertErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			if !out.Success || out.AllVoted || out.IsResolved {
+				t.Errorf("output = %+v, want 成功・未集計", out)
+			}
+			if got := env.room(t, roomID).Votes[tt.userID]; got != policyID {
+				t.Errorf("votes[%s] = %s, want %s", tt.userID, got, policyID)
+			}
+			if got := env.player(t, roomID, tt.userID).CurrentVote; got != policyID {
+				t.Errorf("currentVote = %s, want %s", got, policyID)
+			}
+		})
+	}
+}
+
+func TestVoteUseCase_RoomNotFound(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.voteUC().Execute(context.Background(), VoteInput{RoomID: "missing", UserID: "host", PolicyID: "policy_001"})
+	assertErr(t, err, entity.ErrRoomNotFound)
+}
+
+func TestVoteUseCase_AutoResolveWhenAllVoted(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := env.startedRoom(t, "p1")
+	policyID := env.room(t, roomID).CurrentPolicyIDs[0]
+
+	out := env.voteAll(t, roomID, map[string]string{"host": policyID, "p1": policyID})
+	if !out.AllVoted || !out.IsResolved {
+		t.Fatalf("output = %+v, want 自動集計", out)
+	}
+
+	room := env.room(t, roomID)
+	if room.Status != entity.RoomStatusResult {
+		t.Errorf("status = %s, want RESULT", room.Status)
+	}
+	if room.LastResult == nil || room.LastResult.PassedPolicyID != policyID {
+		t.Fatalf("lastResult = %+v, want 可決 %s", room.LastResult, policyID)
+	}
+	if room.LastResult.CityImageURL == "" {
+		t.Error("街の画像URLが保存されていない")
+	}
+	if env.imageGenerator.calls != 1 {
+		t.Errorf("画像生成の呼び出し = %d回, want 1回", env.imageGenerator.calls)
+	}
+	if !env.publisher.has(entity.RoomEventVoteCast) || !env.publisher.has(entity.RoomEventTurnResolved) {
+		t.Errorf("events = %v, want VOTE_CAST と TURN_RESOLVED", env.publisher.types())
+	}
+}
+
+func TestVoteUseCase_ConcurrentVotesResolveOnce(t *testing.T) {
+	env := newTestEnv(t)
+	guests := []string{"p1", "p2", "p3"}
+	roomID := env.startedRoom(t, guests...)
+	policyID := env.room(t, roomID).CurrentPolicyIDs[0]
+
+	var wg sync.WaitGroup
+	resolved := make(chan bool, 4)
+	for _, userID := range append([]string{"host"}, guests...) {
+		wg.Add(1)
+		go func(userID string) {
+			defer wg.Done()
+			out, err := env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: userID, PolicyID: policyID})
+			if err != nil {
+				t.Errorf("Vote(%s): %v", userID, err)
+				return
+			}
+			resolved <- out.IsResolved
+		}(userID)
+	}
+	wg.Wait()
+	close(resolved)
+
+	resolveCount := 0
+	for r := range resolved {
+		if r {
+			resolveCount++
+		}
+	}
+	if resolveCount != 1 {
+		t.Errorf("集計回数 = %d, want 1", resolveCount)
+	}
+
+	room := env.room(t, roomID)
+	if len(room.LastResult.VoteDetails) != 4 {
+		t.Errorf("voteDetails = %d票, want 4票（票が失われている）", len(room.LastResult.VoteDetails))
+	}
+	if len(room.PassedPolicyIDs) != 1 {
+		t.Errorf("passedPolicyIds = %v, want 1件", room.PassedPolicyIDs)
+	}
+}
+
+func TestVoteUseCase_InstantRunoff(t *testing.T) {
+	env := newTestEnv(t)
+	method := entity.VotingMethodInstantRunoff
+	created, err := env.createRoomUC().Execute(context.Background(), CreateRoomInput{
+		UserID:      "host",
+		DisplayName: "ホスト",
+		Settings:    RoomSettingsInput{MaxPlayers: intPtr(5), VotingMethod: &method},
+	})
+	assertErr(t, err, nil)
+	roomID := created.RoomID
+	env.join(t, roomID, "p1", "p2", "p3", "p4")
+	if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+	o := env.room(t, roomID).CurrentPolicyIDs
+
+	// 同じ政策を重ねて選ぶことはできない
+	_, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", Ballot: []string{o[0], o[0]}})
+	assertErr(t, err, entity.ErrInvalidBallot)
+
+	// 第1希望は o0:2 o1:2 o2:1 → o2 が脱落し、その票が o0 に移る
+	ballots := map[string][]string{
+		"host": {o[1]},
+		"p1":   {o[1], o[2]},
+		"p2":   {o[0]},
+		"p3":   {o[0]},
+		"p4":   {o[2], o[0]},
+	}
+	var out *VoteOutput
+	for _, userID := range []string{"host", "p1", "p2", "p3", "p4"} {
+		out, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: userID, Ballot: ballots[userID]})
+		if err != nil {
+			t.Fatalf("Vote(%s): %v", userID, err)
+		}
+		if userID == "p1" {
+			if got := env.player(t, roomID, "p1"); got.CurrentVote != o[1] || len(got.CurrentBallot) != 2 {
+				t.Errorf("currentVote = %s, currentBallot = %v, want 第1希望と票の全体", got.CurrentVote, got.CurrentBallot)
+			}
+		}
+	}
+	if !out.IsResolved {
+		t.Fatalf("output = %+v, want 自動集計", out)
+	}
+
+	result := env.room(t, roomID).LastResult
+	if result.PassedPolicyID != o[0] {
+		t.Errorf("passedPolicyId = %s, want 票が移った %s", result.PassedPolicyID, o[0])
+	}
+	if result.VotingMethod != method || len(result.Rounds) != 2 || len(result.Rounds[0].Eliminated) != 1 || result.Rounds[0].Eliminated[0] != o[2] {
+		t.Errorf("votingMethod = %s, rounds = %+v, want 1ラウンド目で %s が脱落", result.VotingMethod, result.Rounds, o[2])
+	}
+	if got := result.VoteDetails["p4"]; len(got) != 2 || got[1] != o[0] {
+		t.Errorf("voteDetails[p4] = %v, want 票の全体", got)
+	}
+}
+
+func TestVoteUseCase_Revote(t *testing.T) {
+	env := newTestEnv(t)
+	rule := entity.TieBreakRevote
+	created, err := env.createRoomUC().Execute(context.Background(), CreateRoomInput{
+		UserID:      "host",
+		DisplayName: "ホスト",
+		Settings:    RoomSettingsInput{TieBreakRule: &rule},
+	})
+	assertErr(t, err, nil)
+	roomID := created.RoomID
+	env.join(t, roomID, "p1")
+	if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+	o := env.room(t, roomID).CurrentPolicyIDs
+
+	out := env.voteAll(t, roomID, map[string]string{"host": o[0], "p1": o[1]})
+	if out.IsResolved || out.PendingTie == nil {
+		t.Fatalf("output = %+v, want 再投票待ち", out)
+	}
+	if got := env.player(t, roomID, "p1"); got.CurrentVote != "" {
+		t.Errorf("p1.currentVote = %q, want 再投票のためリセット", got.CurrentVote)
+	}
+
+	// 再投票では同数の政策にしか投票できない
+	_, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", PolicyID: o[2]})
+	assertErr(t, err, entity.ErrInvalidPolicy)
+
+	out = env.voteAll(t, roomID, map[string]string{"host": o[1], "p1": o[1]})
+	if !out.IsResolved {
+		t.Fatalf("output = %+v, want 再投票で可決", out)
+	}
+	room := env.room(t, roomID)
+	if room.PendingTie != nil {
+		t.Error("可決後も pendingTie が残っている")
+	}
+	if room.LastResult.PassedPolicyID != o[1] {
+		t.Errorf("passedPolicyId = %s, want %s", room.LastResult.PassedPolicyID, o[1])
+	}
+	if len(room.LastResult.Rounds) != 2 {
+		t.Errorf("rounds = %+v, want 同数の投票と再投票の2ラウンド", room.LastResult.Rounds)
+	}
+}
+
+func TestVoteUseCase_Change(t *testing.T) {
+	env := newTestEnv(t)
+	roomID := env.startedRoom(t, "p1")
+	o := env.room(t, roomID).CurrentPolicyIDs
+
+	// 未投票なら変更できない
+	_, err := env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", PolicyID: o[0], Change: true})
+	assertErr(t, err, entity.ErrNotVoted)
+
+	_, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", PolicyID: o[0]})
+	assertErr(t, err, nil)
+	out, err := env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", PolicyID: o[1], Change: true})
+	assertErr(t, err, nil)
+
+	if out.IsResolved || out.Undecided != 1 {
+		t.Errorf("output = %+v, want 未集計・残り1人", out)
+	}
+	if got := env.room(t, roomID).Votes["host"]; got != o[1] {
+		t.Errorf("votes[host] = %s, want 変更後の %s", got, o[1])
+	}
+	if got := env.player(t, roomID, "host").CurrentVote; got != o[1] {
+		t.Errorf("currentVote = %s, want 変更後の %s", got, o[1])
+	}
+}
+
+func TestVoteUseCase_RequireLockIn(t *testing.T) {
+	env := newTestEnv(t)
+	lockIn := true
+	created, err := env.createRoomUC().Execute(context.Background(), CreateRoomInput{
+		UserID:      "host",
+		DisplayName: "ホスト",
+		Settings:    RoomSettingsInput{RequireLockIn: &lockIn},
+	})
+	assertErr(t, err, nil)
+	roomID := created.RoomID
+	env.join(t, roomID, "p1")
+	if _, err := env.startGameUC().Execute(context.Background(), StartGameInput{RoomID: roomID, UserID: "host"}); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+	o := env.room(t, roomID).CurrentPolicyIDs
+
+	// 投票していなければ確定できない
+	_, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", LockIn: true})
+	assertErr(t, err, entity.ErrNotVoted)
+
+	// 全員投票しても確定するまで集計しない
+	out := env.voteAll(t, roomID, map[string]string{"host": o[0], "p1": o[1]})
+	if out.IsResolved || !out.AllVoted || out.Undecided != 2 {
+		t.Fatalf("output = %+v, want 全員投票済み・未集計・残り2人", out)
+	}
+	_, err = env.resolveVoteUC().Execute(context.Background(), ResolveVoteInput{RoomID: roomID})
+	assertErr(t, err, entity.ErrNotAllVoted)
+
+	out, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", LockIn: true})
+	assertErr(t, err, nil)
+	if out.IsResolved || out.Undecided != 1 {
+		t.Errorf("output = %+v, want 未集計・残り1人", out)
+	}
+	if !env.publisher.has(entity.RoomEventVoteLockedIn) {
+		t.Error("VOTE_LOCKED_IN が配信されていない")
+	}
+
+	// 確定した投票は変更できない
+	_, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "host", PolicyID: o[1], Change: true})
+	assertErr(t, err, entity.ErrVoteLockedIn)
+
+	// 最後の1人が投票を変更して確定したら集計する
+	out, err = env.voteUC().Execute(context.Background(), VoteInput{RoomID: roomID, UserID: "p1", PolicyID: o[0], Change: true, LockIn: true})
+	assertErr(t, err, nil)
+	if !out.IsResolved || out.Undecided != 0 {
+		t.Fatalf("output = %+v, want 集計済み", out)
+	}
+	if got := env.room(t, roomID).LastResult.PassedPolicyID; got != o[0] {
+		t.Errorf("passedPolicyId = %s, want %s", got, o[0])
+	}
+}
@@ -49,6 +49,7 @@ echo "$RESPONSE" | jq .
 
 ROOM_ID=$(echo "$RESPONSE" | jq -r '.roomId')
 HOST_ID=$(echo "$RESPONSE" | jq -r '.playerId')
+HOST_TOKEN=$(echo "$RESPONSE" | jq -r '.sessionToken')
 
 if [ "$ROOM_ID" != "null" ] && [ -n "$ROOM_ID" ]; then
     print_success "部屋作成成功: roomId=$ROOM_ID, playerId=$HOST_ID"
@@ -69,6 +70,7 @@ RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/join" \
 echo "$RESPONSE" | jq .
 
 GUEST_ID=$(echo "$RESPONSE" | jq -r '.playerId')
+GUEST_TOKEN=$(echo "$RESPONSE" | jq -r '.sessionToken')
 
 if [ "$GUEST_ID" != "null" ] && [ -n "$GUEST_ID" ]; then
     print_success "部屋参加成功: playerId=$GUEST_ID"
@@ -84,30 +86,26 @@ print_step "3. Ready状態トグル (POST /api/rooms/{roomId}/ready)"
 
 # ゲストをReadyに
 RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/ready" \
-    -H "Content-Type: application/json" \
-    -d "{\"playerId\": \"${GUEST_ID}\"}")
+    -H "Authorization: Bearer ${GUEST_TOKEN}")
 
 IS_READY=$(echo "$RESPONSE" | jq -r '.isReady')
 if [ "$IS_READY" != "true" ]; then
     # もう一度トグル
     RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/ready" \
-        -H "Content-Type: application/json" \
-        -d "{\"playerId\": \"${GUEST_ID}\"}")
+        -H "Authorization: Bearer ${GUEST_TOKEN}")
 fi
 echo "$RESPONSE" | jq .
 print_success "ゲストがReadyになりました"
 
 # ホストをReadyに (ホストは自動的にReadyになっていない場合)
 RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/ready" \
-    -H "Content-Type: application/json" \
-    -d "{\"playerId\": \"${HOST_ID}\"}")
+    -H "Authorization: Bearer ${HOST_TOKEN}")
 
 IS_READY=$(echo "$RESPONSE" | jq -r '.isReady')
 if [ "$IS_READY" != "true" ]; then
     # もう一度トグル
     RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/ready" \
-        -H "Content-Type: application/json" \
-        -d "{\"playerId\": \"${HOST_ID}\"}")
+        -H "Authorization: Bearer ${HOST_TOKEN}")
 fi
 echo "$RESPONSE" | jq .
 print_success "ホストがReadyになりました"
@@ -118,8 +116,7 @@ print_success "ホストがReadyになりました"
 print_step "4. ゲーム開始 (POST /api/rooms/{roomId}/start)"
 
 RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/start" \
-    -H "Content-Type: application/json" \
-    -d "{\"playerId\": \"${HOST_ID}\"}")
+    -H "Authorization: Bearer ${HOST_TOKEN}")
 
 echo "$RESPONSE" | jq .
 
@@ -146,7 +143,8 @@ print_step "5. 投票 - ホスト (POST /api/rooms/{roomId}/vote)"
 
 RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/vote" \
     -H "Content-Type: application/json" \
-    -d "{\"playerId\": \"${HOST_ID}\", \"policyId\": \"${FIRST_POLICY}\"}")
+    -H "Authorization: Bearer ${HOST_TOKEN}" \
+    -d "{\"policyId\": \"${FIRST_POLICY}\"}")
 
 echo "$RESPONSE" | jq .
 print_success "ホストが投票しました: policyId=$FIRST_POLICY"
@@ -158,7 +156,8 @@ print_step "6. 投票 - ゲスト (POST /api/rooms/{roomId}/vote)"
 
 RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/vote" \
     -H "Content-Type: application/json" \
-    -d "{\"playerId\": \"${GUEST_ID}\", \"policyId\": \"${FIRST_POLICY}\"}")
+    -H "Authorization: Bearer ${GUEST_TOKEN}" \
+    -d "{\"policyId\": \"${FIRST_POLICY}\"}")
 
 # 画像以外を表示
 echo "$RESPONSE" | jq 'del(.lastResult.cityImage)'
@@ -193,6 +192,7 @@ if [ "$IS_RESOLVED" != "true" ]; then
 
     RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/resolve" \
         -H "Content-Type: application/json" \
+        -H "Authorization: Bearer ${HOST_TOKEN}" \
         -d '{}')
 
     echo "$RESPONSE" | jq .
@@ -219,6 +219,7 @@ if [ "$STATUS" = "RESULT" ]; then
 
     RESPONSE=$(curl -s -X POST "${API_BASE}/rooms/${ROOM_ID}/next" \
         -H "Content-Type: application/json" \
+        -H "Authorization: Bearer ${HOST_TOKEN}" \
         -d '{}')
 
     echo "$RESPONSE" | jq .
@@ -69,23 +69,121 @@ export interface MasterIdeology {
 /** ゲームステータス */
 export type RoomStatus = 'LOBBY' | 'VOTING' | 'RESULT' | 'FINISHED';
 
+/** 部屋の公開範囲（PUBLIC はロビーの一覧に表示し、クイックマッチで相手を探す） */
+export type RoomVisibility = 'PUBLIC' | 'PRIVATE';
+
+/** 承認された陳情の政策を入れる位置（次の手札 / 先頭 petitionWindow 枚以内 / 提示中の選択肢と入れ替え） */
+export type PetitionPlacement = 'NEXT_HAND' | 'RANDOM_WITHIN' | 'REPLACE_CURRENT';
+
+/** 投票の集計方式（単純多数決 / 承認投票 / 即時決選投票 / ボルダ得点） */
+export type VotingMethod = 'PLURALITY' | 'APPROVAL' | 'INSTANT_RUNOFF' | 'BORDA';
+
+/**
+ * 最も得点の高い政策が同数の場合の決め方
+ * （ランダム / 提示順で先 / ホストが選ぶ / 効果の最も小さい政策 / 同数の政策だけで再投票）
+ */
+export type TieBreakRule = 'RANDOM' | 'FIRST_OPTION' | 'HOST_DECIDES' | 'LOWEST_MAGNITUDE' | 'REVOTE';
+
+/** 制限時間までに投票しなかったプレイヤーの扱い（棄権 / ランダムに1票） */
+export type TimeoutVote = 'ABSTAIN' | 'RANDOM';
+
+/** パラメータが下限・上限に達したときの扱い */
+export type BoundMode = 'COLLAPSE' | 'CLAMP' | 'DIMINISHING';
+
+/** 1分野の下限・上限とその扱い */
+export interface ParamRule {
+  floor: number;          // 下限（-100〜200）
+  ceiling: number;        // 上限（-100〜200、floor より大きい）
+  floorMode: BoundMode;
+  ceilingMode: BoundMode;
+}
+
+/** 分野ごとのルール（指定のない分野は 0〜100・COLLAPSE） */
+export type CityRules = Partial<Record<keyof CityParams, ParamRule>>;
+
+/**
+ * 部屋の設定
+ * 部屋作成時に指定し、LOBBY の間はホストが変更できる
+ */
+export interface RoomSettings {
+  maxTurns: number;             // ターン数（1〜30）
+  maxPlayers: number;           // 最大人数（2〜6）
+  optionsPerTurn: number;       // 1ターンに提示する政策の数（2〜5）
+  initialCityParams: CityParams; // 開始時の街パラメータ（各分野の floor と ceiling の間）
+  cityRules: CityRules;         // 分野ごとの下限・上限（変更時は指定した分野だけ上書き）
+  petitionsPerPlayer: number;   // 1人が陳情できる回数（0〜3）
+  votingMethod: VotingMethod;
+  tieBreakRule: TieBreakRule;
+  requireLockIn: boolean;       // 全員が投票を確定するまで集計しないか
+  votingTimeLimit: number;      // 投票の制限時間（秒、0 なら制限なし。10〜600）
+  resultTimeLimit: number;      // 結果発表から自動で次のターンに進むまでの時間（秒、0 なら進まない。5〜120）
+  timeoutVote: TimeoutVote;     // 制限時間までに投票しなかったプレイヤーの扱い
+  recycleRejected: boolean;     // 可決されなかった政策を後で山札に戻すか
+  petitionPlacement: PetitionPlacement;
+  petitionWindow: number;       // RANDOM_WITHIN で入れる範囲（1〜15）
+}
+
+/** 1回だけ使える招待（使った招待・期限切れの招待は削除する） */
+export interface RoomInvite {
+  createdAt: Timestamp;
+  expiresAt: Timestamp;
+}
+
+/** 参加の失敗の記録（1分間に5回失敗するとその期間が終わるまで参加できない） */
+export interface JoinFailures {
+  count: number;
+  windowStart: Timestamp;
+}
+
 /**
  * ゲームルーム
  * パス: rooms/{roomId}
  */
 export interface Room {
+  code: string;                         // 部屋コード（4〜6文字、口頭で伝える用）
   hostId: string;
+  visibility: RoomVisibility;           // 作成時に決まる。PUBLIC の LOBBY はロビーの一覧・クイックマッチの対象
+  inviteOnly: boolean;                  // ホストが発行した招待がないと参加できない
+  passwordHash?: string;                // 参加に必要なパスワードのハッシュ（API のレスポンスには含めない）
+  invites?: Record<string, RoomInvite>; // 有効な招待（キーは招待トークンのハッシュ。API のレスポンスには含めない）
+  joinFailures?: JoinFailures;          // パスワード・招待の不一致の記録（API のレスポンスには含めない）
   status: RoomStatus;
   turn: number;
-  maxTurns: number;
+  maxTurns: number;                     // settings.maxTurns と同じ値
+  settings: RoomSettings;
   createdAt: Timestamp;
+  lastActivityAt: Timestamp;            // プレイヤーが最後に操作した日時（一定時間操作がないと部屋は削除される）
   cityParams: CityParams;
   isCollapsed: boolean;
   currentPolicyIds: string[];           // ★ IDのみ。マスターから引いて表示
   deckIds: string[];                    // 山札
+  discardIds: string[];                 // 捨て札（山札が足りなくなると山札の下に戻る）
   passedPolicyIds: string[];            // 可決された政策の履歴
-  votes: Record<string, string | null>; // { userId: policyId | null }
+  rejectedPolicies: RejectedPolicy[];   // 可決されなかった政策の記録（政策の墓場）
+  votes: Record<string, string | null>; // { userId: policyId | null }（第1希望）
+  ballots: Record<string, string[]>;    // { userId: [policyId, ...] }（希望順・賛成した政策の全て）
+  lockedIn: Record<string, boolean>;    // { userId: true }（投票を確定したプレイヤー）
+  pendingTie: PendingTie | null;        // 同数で決着待ち（HOST_DECIDES / REVOTE のみ）
+  deadline: Timestamp | null;           // 現在のフェーズ（VOTING・RESULT）の期限（制限時間なしなら null）
+  seed: number;                         // 部屋の乱数の種
+  randCount: number;                    // 部屋の乱数を使った回数
   lastResult: VoteResult | null;
+  finalResult: FinalResult | null;      // FINISHED 時のみ
+}
+
+/** 同数で決着待ちの状態 */
+export interface PendingTie {
+  policyIds: string[];  // 同数の政策（提示順）。REVOTE ではこの政策にだけ投票できる
+  rule: TieBreakRule;   // HOST_DECIDES or REVOTE
+  rounds: VoteRound[];  // 同数になった投票の集計
+}
+
+/** 提示されたが可決されなかった政策の記録 */
+export interface RejectedPolicy {
+  policyId: string;
+  turn: number;       // 提示されたターン
+  votes: number;      // 得点（集計の最初のラウンド。PLURALITY なら得票数）
+  recycled: boolean;  // 捨て札に入り、山札に戻る対象になったか
 }
 
 /** 投票結果（RESULT フェーズで設定） */
@@ -94,7 +192,24 @@ export interface VoteResult {
   passedPolicyTitle: string;
   actualEffects: PolicyEffects;  // ここで効果を開示
   newsFlash: string;
-  voteDetails: Record<string, string>;  // { userId: policyId }
+  votingMethod: VotingMethod;
+  voteDetails: Record<string, string[]>; // { userId: [policyId, ...] }（各プレイヤーの票の全体）
+  rounds: VoteRound[];                   // 集計のラウンド（INSTANT_RUNOFF では脱落の経過）
+  collapseCause?: CollapseCause;        // 国家崩壊した場合の原因
+}
+
+/** 集計の1ラウンド */
+export interface VoteRound {
+  scores: Record<string, number>;  // 政策ごとの得点（得票数・賛成数・ボルダ点）
+  eliminated?: string[];           // このラウンドで脱落した政策
+}
+
+/** 国家崩壊の原因 */
+export interface CollapseCause {
+  param: keyof CityParams;       // 原因となった分野
+  value: number;                 // 崩壊時の値
+  bound: 'FLOOR' | 'CEILING';    // 下限以下 / 上限以上（過熱）
+  limit: number;                 // 達した境界の値
 }
 
 // =============================================================================
@@ -105,7 +220,7 @@ export interface VoteResult {
  * プレイヤー
  * パス: rooms/{roomId}/players/{userId}
  *
- * ⚠️ ideology, currentVote は Security Rules で本人以外読み取り禁止
+ * ⚠️ ideology, currentVote, currentBallot は Security Rules で本人以外読み取り禁止
  * 投票済みかは Room.votes の keys を監視して判断
  */
 export interface Player {
@@ -113,11 +228,13 @@ export interface Player {
   displayName: string;
   isHost: boolean;
   isReady: boolean;
-  isPetitionUsed: boolean;
+  isPetitionUsed: boolean;  // 陳情を使い切ったか
+  petitionsUsed: number;    // 陳情した回数
 
   // 🔒 秘匿情報（本人のみ読み取り可）
   ideology: MasterIdeology;      // 割り振られた思想
-  currentVote: string | null;    // 投票先の政策ID
+  currentVote: string | null;    // 投票先の政策ID（第1希望）
+  currentBallot: string[] | null; // 票の全体（希望順・賛成した政策の全て）
 }
 
 /** プレイヤー公開情報（他プレイヤーが見れる部分） */
@@ -132,6 +249,10 @@ export interface PlayerPublic {
 // Cloud Run API リクエスト/レスポンス型
 // =============================================================================
 
+// 部屋作成・参加以外の操作は、部屋作成・参加時に発行された sessionToken を
+// Authorization: Bearer {sessionToken} で送る（リクエストに playerId は含めない）
+// イベント配信（EventSource）はクエリパラメータ ?token={sessionToken} で送る
+
 // -----------------------------------------------------------------------------
 // POST /api/rooms - 部屋作成
 // -----------------------------------------------------------------------------
@@ -139,13 +260,68 @@ export interface PlayerPublic {
 /** 部屋作成リクエスト */
 export interface CreateRoomRequest {
   displayName: string;
+  seed?: number;                     // 部屋の乱数の種（不具合の再現用。ローカル開発のみ、省略時はランダム）
+  visibility?: RoomVisibility;       // 省略時は PRIVATE
+  password?: string;                 // 参加に必要なパスワード（4〜64文字。省略時はなし）
+  inviteOnly?: boolean;              // 招待がないと参加できない
+  settings?: Partial<RoomSettings>;  // 省略した項目はデフォルト値
 }
 
 /** 部屋作成レスポンス */
 export interface CreateRoomResponse {
   roomId: string;
+  roomCode: string;          // 他のプレイヤーに伝える部屋コード（POST /api/codes/{code}/join で参加できる）
   status: RoomStatus;
+  visibility: RoomVisibility;
+  hasPassword: boolean;
+  inviteOnly: boolean;
   playerId: string;
+  rejoinCode: string;        // 再参加コード（このレスポンスでのみ返る。プレイヤーに控えてもらう）
+  sessionToken: string;      // 以降のリクエストで Authorization: Bearer に使う
+  sessionExpiresAt: string;  // ISO 8601
+  settings: RoomSettings;
+}
+
+// -----------------------------------------------------------------------------
+// POST /api/rooms/{roomId}/settings - 部屋設定の変更
+// -----------------------------------------------------------------------------
+
+/** 部屋設定変更リクエスト（ホストのみ、LOBBY のみ） */
+export interface UpdateRoomSettingsRequest {
+  settings: Partial<RoomSettings>;  // 省略した項目は変更しない
+}
+
+/** 部屋設定変更レスポンス */
+export interface UpdateRoomSettingsResponse {
+  settings: RoomSettings;
+}
+
+// -----------------------------------------------------------------------------
+// POST /api/rooms/{roomId}/access - パスワード・招待のみの変更
+// POST /api/rooms/{roomId}/invites - 招待の発行
+// -----------------------------------------------------------------------------
+
+/** パスワード・招待のみの変更リクエスト（ホストのみ、LOBBY のみ。省略した項目は変更しない） */
+export interface UpdateRoomAccessRequest {
+  password?: string;     // 空文字でパスワードを解除する
+  inviteOnly?: boolean;
+}
+
+/** パスワード・招待のみの変更レスポンス */
+export interface UpdateRoomAccessResponse {
+  hasPassword: boolean;
+  inviteOnly: boolean;
+}
+
+/** 招待の発行リクエスト（ホストのみ、LOBBY のみ） */
+export interface CreateInviteRequest {
+  ttlSeconds?: number;  // 有効期間（60〜86400秒。省略時は1時間）
+}
+
+/** 招待の発行レスポンス */
+export interface CreateInviteResponse {
+  inviteToken: string;  // 参加時に invite として送る（このレスポンスでのみ返る）
+  expiresAt: string;    // ISO 8601
 }
 
 // -----------------------------------------------------------------------------
@@ -155,22 +331,103 @@ export interface CreateRoomResponse {
 /** 部屋参加リクエスト */
 export interface JoinRoomRequest {
   displayName: string;
+  password?: string;  // パスワードのある部屋の場合
+  invite?: string;    // ホストから受け取った招待トークン（パスワード・招待のみの制限を通れる。1回だけ使える）
 }
 
 /** 部屋参加レスポンス */
 export interface JoinRoomResponse {
+  roomId: string;            // 部屋コードで参加した場合は以降のAPIにこの値を使う
   playerId: string;
+  rejoinCode: string;        // 再参加コード（このレスポンスでのみ返る。プレイヤーに控えてもらう）
+  sessionToken: string;      // 以降のリクエストで Authorization: Bearer に使う
+  sessionExpiresAt: string;  // ISO 8601
 }
 
 // -----------------------------------------------------------------------------
-// POST /api/rooms/{roomId}/leave - 部屋退出
+// GET /api/lobbies - 公開ロビーの一覧
+// -----------------------------------------------------------------------------
+
+/** 参加者を募集している公開の部屋（満員の部屋は含まない） */
+export interface Lobby {
+  roomId: string;
+  roomCode: string;
+  hostName: string;
+  playerCount: number;
+  hasPassword: boolean;  // 参加にパスワードが必要（クイックマッチでは参加しない）
+  settings: RoomSettings;
+  createdAt: string;  // ISO 8601
+}
+
+/** 公開ロビーの一覧レスポンス（作成の新しい順。?limit= で件数を指定、デフォルト20・最大50） */
+export interface ListLobbiesResponse {
+  lobbies: Lobby[];
+}
+
+// -----------------------------------------------------------------------------
+// POST /api/quickmatch - クイックマッチ
+// -----------------------------------------------------------------------------
+
+/** クイックマッチリクエスト */
+export interface QuickMatchRequest {
+  displayName: string;
+  settings?: Partial<RoomSettings>;  // 指定した項目が一致する部屋を探す（部屋を作成する場合もこの設定）
+}
+
+/** クイックマッチレスポンス */
+export interface QuickMatchResponse {
+  roomId: string;
+  roomCode: string;
+  playerId: string;
+  rejoinCode: string;        // 再参加コード（このレスポンスでのみ返る。プレイヤーに控えてもらう）
+  created: boolean;          // 参加できる部屋がなく、公開の部屋を作成した（ホストになった）
+  sessionToken: string;      // 以降のリクエストで Authorization: Bearer に使う
+  sessionExpiresAt: string;  // ISO 8601
+}
+
+// -----------------------------------------------------------------------------
+// GET /api/codes/{code} - 部屋コードから部屋を探す
+// POST /api/codes/{code}/join - 部屋コードで参加（リクエスト・レスポンスは JoinRoomRequest / JoinRoomResponse）
+// -----------------------------------------------------------------------------
+
+/** 部屋コードの検索レスポンス（部屋コードは大文字・小文字、空白・ハイフンを区別しない） */
+export interface FindRoomByCodeResponse {
+  roomId: string;
+  roomCode: string;
+  status: RoomStatus;
+  playerCount: number;
+  maxPlayers: number;
+}
+
+// -----------------------------------------------------------------------------
+// POST /api/rooms/{roomId}/rejoin - 再参加
 // -----------------------------------------------------------------------------
 
-/** 部屋退出リクエスト */
-export interface LeaveRoomRequest {
+/** 再参加リクエスト（rejoinCode を省略した場合はセッショントークンのプレイヤーとして戻る） */
+export interface RejoinRoomRequest {
+  rejoinCode?: string;  // 大文字・小文字、空白・ハイフンは区別しない
+}
+
+/** 再参加レスポンス（本人の秘匿情報を含む） */
+export interface RejoinRoomResponse {
   playerId: string;
+  displayName: string;
+  isHost: boolean;
+  status: RoomStatus;
+  turn: number;
+  ideology: MasterIdeology;
+  currentVote: string;
+  currentBallot: string[] | null;
+  isLockedIn: boolean;
+  petitionsLeft: number;
+  sessionToken: string;
+  sessionExpiresAt: string;  // ISO 8601
 }
 
+// -----------------------------------------------------------------------------
+// POST /api/rooms/{roomId}/leave - 部屋退出
+// -----------------------------------------------------------------------------
+
 /** 部屋退出レスポンス */
 export interface LeaveRoomResponse {
   success: boolean;
@@ -180,11 +437,6 @@ export interface LeaveRoomResponse {
 // POST /api/rooms/{roomId}/ready - Ready状態トグル
 // -----------------------------------------------------------------------------
 
-/** Ready状態リクエスト */
-export interface ReadyRequest {
-  playerId: string;
-}
-
 /** Ready状態レスポンス */
 export interface ReadyResponse {
   isReady: boolean;
@@ -194,11 +446,6 @@ export interface ReadyResponse {
 // POST /api/rooms/{roomId}/start - ゲーム開始
 // -----------------------------------------------------------------------------
 
-/** ゲーム開始リクエスト */
-export interface StartGameRequest {
-  playerId: string;
-}
-
 /** ゲーム開始レスポンス */
 export interface StartGameResponse {
   status: RoomStatus;
@@ -208,25 +455,64 @@ export interface StartGameResponse {
 
 // -----------------------------------------------------------------------------
 // POST /api/rooms/{roomId}/vote - 投票
+// PUT /api/rooms/{roomId}/vote - 投票の変更（未投票ならエラー）
 // -----------------------------------------------------------------------------
 
 /** 投票リクエスト */
 export interface VoteRequest {
-  playerId: string;
-  policyId: string;
+  policyId?: string;    // 1つだけ選ぶ場合
+  policyIds?: string[]; // 複数選ぶ場合（APPROVAL は賛成する政策、INSTANT_RUNOFF・BORDA は希望順）
+  lockIn?: boolean;     // 投票と同時に確定する
 }
 
-/** 投票レスポンス */
+/** 投票レスポンス（POST /api/rooms/{roomId}/lockin も同じ） */
 export interface VoteResponse {
   success: boolean;
+  allVoted: boolean;
+  undecided: number;        // まだ決めていないプレイヤーの数（requireLockIn なら未確定も含む）
+  isResolved?: boolean;     // 全員が決めて集計した場合。false なら同数で決着待ち
+  status?: RoomStatus;
+  lastResult?: VoteResult;  // 集計した場合のみ
+  cityParams?: CityParams;  // 集計した場合のみ
+  isGameOver?: boolean;     // 集計した場合のみ
+  pendingTie?: PendingTie;  // 同数で決着待ちの場合のみ
+}
+
+// -----------------------------------------------------------------------------
+// DELETE /api/rooms/{roomId}/vote - 投票の取り消し
+// -----------------------------------------------------------------------------
+
+/** 投票取り消しレスポンス */
+export interface RetractVoteResponse {
+  success: boolean;
+  undecided: number;
 }
 
 // -----------------------------------------------------------------------------
 // POST /api/rooms/{roomId}/resolve - 投票集計
 // -----------------------------------------------------------------------------
 
-/** 投票集計レスポンス */
+/** 投票集計レスポンス（isResolved が false なら同数で決着待ちで、pendingTie のみ） */
 export interface ResolveVoteResponse {
+  status: RoomStatus;
+  isResolved: boolean;
+  lastResult?: VoteResult;
+  cityParams?: CityParams;
+  isGameOver?: boolean;
+  pendingTie?: PendingTie;
+}
+
+// -----------------------------------------------------------------------------
+// POST /api/rooms/{roomId}/tiebreak - 同数の決着（ホストのみ）
+// -----------------------------------------------------------------------------
+
+/** 同数の決着リクエスト（tieBreakRule が HOST_DECIDES の場合） */
+export interface DecideTieRequest {
+  policyId: string;  // pendingTie.policyIds のいずれか
+}
+
+/** 同数の決着レスポンス */
+export interface DecideTieResponse {
   status: RoomStatus;
   lastResult: VoteResult;
   cityParams: CityParams;
@@ -241,6 +527,7 @@ export interface ResolveVoteResponse {
 export interface NextTurnResponse {
   status: RoomStatus;
   turn: number;
+  isGameOver: boolean;  // 提示できる政策が尽きて終了した場合 true（status は FINISHED）
 }
 
 // -----------------------------------------------------------------------------
@@ -249,7 +536,6 @@ export interface NextTurnResponse {
 
 /** 陳情リクエスト */
 export interface PetitionRequest {
-  playerId: string;
   text: string;
 }
 
@@ -257,6 +543,7 @@ export interface PetitionRequest {
 export interface PetitionResponse {
   approved: boolean;
   policyId?: string;   // 承認時のみ
+  surfaceTurn: number; // 承認された政策が提示されるターン（却下時は 0）
   message: string;
 }
 
@@ -273,18 +560,43 @@ export interface ApiErrorResponse {
 // スコア計算用の型
 // =============================================================================
 
-/** プレイヤースコア（ゲーム終了後に表示） */
-export interface PlayerScore {
-  userId: string;
+/** ゲーム終了の理由 */
+export type GameEndReason = 'MAX_TURNS' | 'COLLAPSED' | 'DECK_EXHAUSTED';
+
+/** プレイヤーの最終スコア（ゲーム終了後に表示） */
+export interface PlayerResult {
+  playerId: string;
   displayName: string;
   ideology: MasterIdeology;  // ゲーム終了後に公開
   score: number;
-  rank: number;
+  rank: number;              // 同点は同順位
+  isWinner: boolean;
 }
 
-/** スコア計算結果 */
-export interface ScoreResult {
-  scores: PlayerScore[];
-  isCollapsed: boolean;
-  finalCityParams: CityParams;
+/**
+ * 最終結果
+ * GET /api/rooms/{roomId}/result のレスポンス、Room.finalResult と同一
+ */
+export interface FinalResult {
+  reason: GameEndReason;
+  cityParams: CityParams;    // 最終的な街のパラメータ
+  rankings: PlayerResult[];  // 順位順
+  finishedAt: Timestamp;
+}
+
+// -----------------------------------------------------------------------------
+// GET /api/rooms/{roomId}/graveyard - 政策の墓場
+// -----------------------------------------------------------------------------
+
+/** 可決されなかった政策（effects はゲーム終了後のみ） */
+export interface GraveyardEntry extends RejectedPolicy {
+  title: string;
+  description: string;
+  effects?: PolicyEffects;
+}
+
+/** 政策の墓場レスポンス */
+export interface GraveyardResponse {
+  recycleRejected: boolean;
+  policies: GraveyardEntry[];  // 提示されたターン順
 }